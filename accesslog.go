@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// accessLogMessage is the message of the entries written by LogAccess.
+const accessLogMessage = "http request"
+
+// AccessLog describes a served HTTP request. It is the schema shared by AccessLogMiddleware and
+// the middleware of the web framework integrations, so that access logs are uniform across
+// frameworks.
+type AccessLog struct {
+	Method string
+	// Path is the path of the request URL.
+	Path string
+	// Route is the route template that matched the request, e.g. "/users/{id}", if any.
+	Route     string
+	Status    int
+	Latency   time.Duration
+	ClientIP  string
+	UserAgent string
+	// Bytes is the size of the response body.
+	Bytes int64
+	// Err is the error the handler failed with, if any.
+	Err error
+}
+
+// LogAccess writes the access log entry of a request with the message "http request" and the
+// method, path, route, status, duration_ms, client_ip, user_agent, bytes and error fields,
+// omitting empty ones. The entry is written at InfoLevel, or at ErrorLevel for statuses of 500
+// and above, and carries the fields derived from ctx, such as the trace_id.
+func (l *Logger) LogAccess(ctx context.Context, a AccessLog) {
+	keyVals := make([]interface{}, 0, 18)
+	keyVals = append(keyVals, "method", a.Method, "path", a.Path)
+	if a.Route != "" {
+		keyVals = append(keyVals, "route", a.Route)
+	}
+	keyVals = append(keyVals, "status", a.Status, "duration_ms", float64(a.Latency.Microseconds())/1000)
+	if a.ClientIP != "" {
+		keyVals = append(keyVals, "client_ip", a.ClientIP)
+	}
+	if a.UserAgent != "" {
+		keyVals = append(keyVals, "user_agent", a.UserAgent)
+	}
+	keyVals = append(keyVals, "bytes", a.Bytes)
+	if a.Err != nil {
+		keyVals = append(keyVals, "error", a.Err)
+	}
+
+	lvl := zapcore.InfoLevel
+	if a.Status >= 500 {
+		lvl = zapcore.ErrorLevel
+	}
+	l.log(ctx, lvl, accessLogMessage, keyVals)
+}
+
+// AccessLogMiddleware returns HTTP middleware that writes an access log entry, see LogAccess,
+// for every request once the wrapped handler returns. The route is the pattern of the
+// http.ServeMux that matched the request, and the client_ip the host of the remote address.
+func AccessLogMiddleware(l *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				clientIP = r.RemoteAddr
+			}
+			l.LogAccess(r.Context(), AccessLog{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Route:     r.Pattern,
+				Status:    status,
+				Latency:   time.Since(start),
+				ClientIP:  clientIP,
+				UserAgent: r.UserAgent(),
+				Bytes:     rec.bytes,
+			})
+		})
+	}
+}
+
+// statusRecorder records the status code and body size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogAccess(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	ctx := context.WithValue(context.Background(), traceKey{}, "req-1")
+
+	l.LogAccess(ctx, logger.AccessLog{Method: "GET", Path: "/users/42", Route: "/users/{id}", Status: 200,
+		Latency: 1500 * time.Microsecond, ClientIP: "10.0.0.1", Bytes: 12})
+	l.LogAccess(ctx, logger.AccessLog{Method: "POST", Path: "/orders", Status: 503, Err: errors.New("db down")})
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "info", entries[0]["level"])
+	require.Equal(t, "http request", entries[0]["msg"])
+	require.Equal(t, "/users/{id}", entries[0]["route"])
+	require.Equal(t, 1.5, entries[0]["duration_ms"])
+	require.Equal(t, "10.0.0.1", entries[0]["client_ip"])
+	require.Equal(t, "req-1", entries[0]["trace_id"])
+	require.NotContains(t, entries[0], "user_agent", "empty fields should be omitted")
+	require.Equal(t, "error", entries[1]["level"])
+	require.Equal(t, "db down", entries[1]["error"])
+	require.NotContains(t, entries[1], "route")
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+	logger.AccessLogMiddleware(l)(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "GET", entries[0]["method"])
+	require.Equal(t, "/users/42", entries[0]["path"])
+	require.Equal(t, "GET /users/{id}", entries[0]["route"])
+	require.EqualValues(t, http.StatusCreated, entries[0]["status"])
+	require.EqualValues(t, 5, entries[0]["bytes"])
+	require.Equal(t, "192.0.2.1", entries[0]["client_ip"])
+	require.Equal(t, "curl/8.0", entries[0]["user_agent"])
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultErrorsThreshold is the number of errors Errors logs individually, unless
+	// WithErrorsThreshold is given.
+	defaultErrorsThreshold = 5
+	// maxAggregatedErrors bounds the errors listed by an aggregated entry of Errors.
+	maxAggregatedErrors = 100
+)
+
+// ErrorClassifier returns the class of an error, under which Errors counts it.
+type ErrorClassifier func(err error) string
+
+// WithErrorsThreshold sets the number of errors up to which Errors logs one entry per error;
+// more errors are aggregated into a single entry. It is 5 by default.
+func WithErrorsThreshold(n int) Option {
+	return func(l *Logger) {
+		l.errorsThreshold = &n
+	}
+}
+
+// WithErrorClassifier replaces the classifier of the errors aggregated by Errors. By default,
+// errors are classified by their message with volatile tokens, such as IDs and numbers,
+// replaced, so that the same failure of different items shares a class.
+func WithErrorClassifier(classify ErrorClassifier) Option {
+	return func(l *Logger) {
+		l.errorClassifier = classify
+	}
+}
+
+// Errors logs the errors of a batch operation at ErrorLevel. Up to the threshold set through
+// WithErrorsThreshold, every error is logged as its own entry with msg, keyVals, and the
+// error and its index in errs as the "error" and "error_index" fields. More errors are
+// logged as a single entry with an "error_count" field, an "error_classes" object counting
+// the errors by class, and an "errors" array listing the first 100 errors with their index,
+// class and message. Nil errors are skipped; nothing is logged when all errors are nil.
+func (l *Logger) Errors(ctx context.Context, msg string, errs []error, keyVals ...interface{}) {
+	var items batchErrors
+	for i, err := range errs {
+		if err != nil {
+			items = append(items, batchError{index: i, err: err})
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	threshold := defaultErrorsThreshold
+	if l.errorsThreshold != nil {
+		threshold = *l.errorsThreshold
+	}
+	if len(items) <= threshold {
+		for _, item := range items {
+			kv := append(keyVals[:len(keyVals):len(keyVals)], "error", item.err, "error_index", item.index)
+			l.log(ctx, zapcore.ErrorLevel, msg, kv)
+		}
+		return
+	}
+
+	classify := l.errorClassifier
+	if classify == nil {
+		classify = classifyError
+	}
+	classes := make(errorClasses)
+	for i := range items {
+		items[i].class = classify(items[i].err)
+		classes[items[i].class]++
+	}
+	count := len(items)
+	if count > maxAggregatedErrors {
+		items = items[:maxAggregatedErrors]
+	}
+	kv := append(keyVals[:len(keyVals):len(keyVals)],
+		"error_count", count,
+		zap.Object("error_classes", classes),
+		zap.Array("errors", items),
+	)
+	l.log(ctx, zapcore.ErrorLevel, msg, kv)
+}
+
+// classifyError is the default ErrorClassifier.
+func classifyError(err error) string {
+	return normalizeMessage(err.Error())
+}
+
+// batchError is an error passed to Errors.
+type batchError struct {
+	index int
+	class string
+	err   error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (e batchError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("index", e.index)
+	enc.AddString("class", e.class)
+	enc.AddString("error", e.err.Error())
+	return nil
+}
+
+// batchErrors is the "errors" array of an aggregated entry.
+type batchErrors []batchError
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (es batchErrors) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range es {
+		if err := enc.AppendObject(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorClasses counts errors by class, for the "error_classes" object of an aggregated entry.
+type errorClasses map[string]int
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, adding the classes in sorted order.
+func (c errorClasses) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	classes := make([]string, 0, len(c))
+	for class := range c {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		enc.AddInt(class, c[class])
+	}
+	return nil
+}
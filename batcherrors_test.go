@@ -0,0 +1,70 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorsIndividual(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	errs := []error{errors.New("row 1: invalid"), nil, errors.New("row 3: duplicate")}
+	l.Errors(context.Background(), "import failed", errs, "batch", "b-1")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "import failed", entries[0]["msg"])
+	require.Equal(t, "b-1", entries[0]["batch"])
+	require.Equal(t, "row 1: invalid", entries[0]["error"])
+	require.EqualValues(t, 0, entries[0]["error_index"])
+	require.Equal(t, "row 3: duplicate", entries[1]["error"])
+	require.EqualValues(t, 2, entries[1]["error_index"])
+
+	l.Errors(context.Background(), "import failed", []error{nil})
+	require.Len(t, decodeLines(t, sink), 2, "nil errors should not be logged")
+}
+
+func TestErrorsAggregated(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithErrorsThreshold(1))
+
+	errs := []error{
+		fmt.Errorf("row 1: invalid"),
+		fmt.Errorf("row 2: invalid"),
+		fmt.Errorf("row 3: duplicate"),
+	}
+	l.Errors(context.Background(), "import failed", errs, "batch", "b-1")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.Equal(t, "b-1", entry["batch"])
+	require.EqualValues(t, 3, entry["error_count"])
+	require.Equal(t, map[string]any{"row #: invalid": 2.0, "row #: duplicate": 1.0}, entry["error_classes"])
+	list := entry["errors"].([]any)
+	require.Len(t, list, 3)
+	require.Equal(t, map[string]any{"index": 2.0, "class": "row #: duplicate", "error": "row 3: duplicate"}, list[2])
+}
+
+func TestWithErrorClassifier(t *testing.T) {
+	l, sink := newMemoryLogger(t,
+		logger.WithErrorsThreshold(0),
+		logger.WithErrorClassifier(func(err error) string {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return "timeout"
+			}
+			return "other"
+		}),
+	)
+
+	errs := []error{fmt.Errorf("fetch: %w", context.DeadlineExceeded), errors.New("boom")}
+	l.Errors(context.Background(), "fetch failed", errs)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, map[string]any{"timeout": 1.0, "other": 1.0}, entries[0]["error_classes"])
+}
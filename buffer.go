@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithBufferedWrites buffers the encoded entries in memory and writes them to the output
+// paths in batches, once size bytes have accumulated or every flushInterval, whichever
+// comes first. This trades durability for fewer syscalls in high-throughput services:
+// buffered entries are lost if the process exits without calling Flush or Close.
+// A non-positive size or flushInterval selects zap's default of 256 kB or 30 seconds.
+func WithBufferedWrites(size int, flushInterval time.Duration) Option {
+	return func(l *Logger) {
+		l.buffering = &bufferConfig{size: size, flushInterval: flushInterval}
+	}
+}
+
+// bufferConfig holds the settings of WithBufferedWrites.
+type bufferConfig struct {
+	size          int
+	flushInterval time.Duration
+}
+
+// bufferSink wraps sink in the buffer configured through WithBufferedWrites, if any.
+func (l *Logger) bufferSink(sink zapcore.WriteSyncer) zapcore.WriteSyncer {
+	if l.buffering == nil || l.lambdaMode {
+		return sink
+	}
+	l.buffer = &zapcore.BufferedWriteSyncer{WS: sink, Size: l.buffering.size, FlushInterval: l.buffering.flushInterval}
+	if l.overflow != OverflowDrop {
+		return l.buffer
+	}
+	l.async = newAsyncWriter(l.buffer, l.buffering.flushInterval, func() {
+		l.recordDrop(zapcore.Entry{Time: time.Now()})
+	})
+	return l.async
+}
+
+// Flush writes out any buffered entries, giving up when ctx is done.
+func (l *Logger) Flush(ctx context.Context) error {
+	return withDeadline(ctx, l.Sync)
+}
+
+// Close flushes any buffered entries, stops the background flushing started by
+// WithBufferedWrites and closes the outputs, such as the connections and goroutines of the
+// sinks registered through RegisterSinkFactory, giving up when ctx is done. The Logger and its
+// children must not be used after Close.
+func (l *Logger) Close(ctx context.Context) error {
+	return withDeadline(ctx, func() error {
+		var err error
+		// The held back summaries are written before the outputs are flushed, those of the
+		// rate limiter first, since it writes through the dedup core.
+		if l.rateLimiter != nil {
+			err = l.rateLimiter.stop()
+		}
+		if l.dedup != nil {
+			err = errors.Join(err, l.dedup.stop())
+		}
+		err = errors.Join(err, l.flushAll())
+		if l.closeOutputs != nil {
+			l.closeOutputs()
+		}
+		return err
+	})
+}
+
+// flushAll writes out the buffered entries, stopping the background flushing.
+func (l *Logger) flushAll() error {
+	var err error
+	if l.async != nil {
+		err = l.async.Stop()
+	}
+	if l.buffer != nil {
+		return errors.Join(err, l.buffer.Stop())
+	}
+	return l.Sync()
+}
+
+// withDeadline runs fn, returning ctx's error if ctx is done before fn returns.
+// fn keeps running in the background in that case.
+func withDeadline(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBufferedWrites(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithBufferedWrites(1<<20, time.Hour))
+	ctx := context.Background()
+
+	l.Info(ctx, "buffered")
+	require.Empty(t, sink.logs.String(), "entries should be held in the buffer")
+
+	require.NoError(t, l.Flush(ctx))
+	require.Contains(t, sink.logs.String(), `"msg":"buffered"`)
+
+	l.With("component", "child").Info(ctx, "from child")
+	require.NoError(t, l.Close(ctx))
+	require.Contains(t, sink.logs.String(), `"msg":"from child"`, "Close should flush the buffer shared with children")
+}
+
+func TestWithBufferedWritesFlushesWhenFull(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithBufferedWrites(64, time.Hour))
+	t.Cleanup(func() { require.NoError(t, l.Close(context.Background())) })
+
+	l.Info(context.Background(), "an entry that does not fit in the small buffer")
+	l.Info(context.Background(), "another one")
+	require.Contains(t, sink.logs.String(), "does not fit")
+}
+
+// blockingSink is a memory sink whose Sync blocks until release is closed.
+type blockingSink struct {
+	memorySink
+	release chan struct{}
+}
+
+// Sync blocks until release is closed.
+func (b *blockingSink) Sync() error {
+	<-b.release
+	return nil
+}
+
+func TestFlushDeadline(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	defer close(sink.release)
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{registerSink(t, sink) + "://"}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.Flush(ctx), context.DeadlineExceeded)
+	require.ErrorIs(t, l.Close(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+	"slices"
+)
+
+// readBuildInfo returns the build information embedded in the binary.
+var readBuildInfo = debug.ReadBuildInfo
+
+// BuildField is an optional field of WithBuildInfo.
+type BuildField string
+
+const (
+	// BuildFieldGoVersion is the go_version field, the version of Go the binary was built with.
+	BuildFieldGoVersion BuildField = "go_version"
+	// BuildFieldPID is the pid field, the ID of the process.
+	BuildFieldPID BuildField = "pid"
+	// BuildFieldHostname is the hostname field, as reported by the kernel.
+	BuildFieldHostname BuildField = "hostname"
+)
+
+// WithBuildInfo adds the fields identifying the binary to every entry: version, the version of
+// the main module, vcs_revision and vcs_time, the revision and commit time stamped by the go
+// command, and vcs_modified when the working tree had uncommitted changes. Unknown values,
+// such as the "(devel)" version of binaries built from a checkout, are omitted. The fields have
+// the precedence of FieldsFromPlatform, so WithFields can override the version, e.g. with one
+// set through -ldflags. extra adds optional fields, such as BuildFieldPID.
+func WithBuildInfo(extra ...BuildField) Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromPlatform,
+			load:   func() ([]keyVal, error) { return mapFields(buildInfoFields(extra)), nil },
+		})
+	}
+}
+
+// buildInfoFields returns the fields of WithBuildInfo.
+func buildInfoFields(extra []BuildField) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if info, ok := readBuildInfo(); ok {
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			fields["version"] = v
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && s.Value != "":
+				fields["vcs_revision"] = s.Value
+			case s.Key == "vcs.time" && s.Value != "":
+				fields["vcs_time"] = s.Value
+			case s.Key == "vcs.modified" && s.Value == "true":
+				fields["vcs_modified"] = true
+			}
+		}
+		if slices.Contains(extra, BuildFieldGoVersion) {
+			fields[string(BuildFieldGoVersion)] = info.GoVersion
+		}
+	}
+	if slices.Contains(extra, BuildFieldPID) {
+		fields[string(BuildFieldPID)] = os.Getpid()
+	}
+	if slices.Contains(extra, BuildFieldHostname) {
+		if hostname, err := os.Hostname(); err == nil {
+			fields[string(BuildFieldHostname)] = hostname
+		}
+	}
+	return fields
+}
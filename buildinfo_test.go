@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBuildInfo makes WithBuildInfo read info for the duration of the test.
+func fakeBuildInfo(t *testing.T, info *debug.BuildInfo) {
+	t.Helper()
+	original := *logger.ReadBuildInfo
+	*logger.ReadBuildInfo = func() (*debug.BuildInfo, bool) { return info, true }
+	t.Cleanup(func() { *logger.ReadBuildInfo = original })
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	fakeBuildInfo(t, &debug.BuildInfo{
+		GoVersion: "go1.24.6",
+		Main:      debug.Module{Path: "example.com/orders", Version: "v1.4.2"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "3f34dd2a"},
+			{Key: "vcs.time", Value: "2025-06-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "false"},
+		},
+	})
+
+	l, sink := newMemoryLogger(t, logger.WithBuildInfo())
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "v1.4.2", entry["version"])
+	require.Equal(t, "3f34dd2a", entry["vcs_revision"])
+	require.Equal(t, "2025-06-01T12:00:00Z", entry["vcs_time"])
+	require.NotContains(t, entry, "vcs_modified")
+	require.NotContains(t, entry, "go_version")
+	require.NotContains(t, entry, "pid")
+	require.NotContains(t, entry, "hostname")
+}
+
+func TestWithBuildInfoExtraFields(t *testing.T) {
+	fakeBuildInfo(t, &debug.BuildInfo{
+		GoVersion: "go1.24.6",
+		Main:      debug.Module{Path: "example.com/orders", Version: "(devel)"},
+		Settings:  []debug.BuildSetting{{Key: "vcs.modified", Value: "true"}},
+	})
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	l, sink := newMemoryLogger(t,
+		logger.WithBuildInfo(logger.BuildFieldGoVersion, logger.BuildFieldPID, logger.BuildFieldHostname),
+		logger.WithFields("version", "v2.0.0"),
+	)
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "v2.0.0", entry["version"], "WithFields should override the version")
+	require.Equal(t, true, entry["vcs_modified"])
+	require.Equal(t, "go1.24.6", entry["go_version"])
+	require.EqualValues(t, os.Getpid(), entry["pid"])
+	require.Equal(t, hostname, entry["hostname"])
+}
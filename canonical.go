@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// canonicalMessage is the message of the entries written by Canonical.Emit.
+const canonicalMessage = "canonical-log-line"
+
+// canonicalKey is the context key of the Canonical of a request.
+type canonicalKey struct{}
+
+// Canonical accumulates the fields of a request's canonical log line, a single enriched
+// summary entry written when the request ends, instead of many small entries. It is safe for
+// concurrent use, and its methods do nothing on a nil Canonical, so code can add fields
+// without checking whether the request has one.
+type Canonical struct {
+	l     *Logger
+	ctx   context.Context
+	start time.Time
+
+	mu      sync.Mutex
+	keys    []string
+	values  map[string]interface{}
+	emitted bool
+}
+
+// StartCanonical starts the canonical log line of the request of ctx. It returns the
+// Canonical and a copy of ctx carrying it, from which CanonicalFromContext retrieves it, so
+// that the handlers and clients deeper in the call stack can add fields:
+//
+//	ctx, canon := log.StartCanonical(ctx)
+//	defer func() { canon.Emit(status) }()
+//	...
+//	logger.CanonicalFromContext(ctx).Add("db_calls", 1)
+func (l *Logger) StartCanonical(ctx context.Context) (context.Context, *Canonical) {
+	c := &Canonical{l: l, ctx: ctx, start: time.Now(), values: make(map[string]interface{})}
+	return context.WithValue(ctx, canonicalKey{}, c), c
+}
+
+// CanonicalFromContext returns the Canonical stored in ctx by StartCanonical, or nil.
+func CanonicalFromContext(ctx context.Context) *Canonical {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(canonicalKey{}).(*Canonical)
+	return c
+}
+
+// Set sets a field of the canonical log line, replacing an earlier value of the key.
+func (c *Canonical) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+// Add adds n to an integer field of the canonical log line, e.g. to count database calls.
+// A key that was not set, or holds another type, starts at zero.
+func (c *Canonical) Add(key string, n int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.values[key].(int64)
+	c.set(key, v+n)
+}
+
+// set sets a field, keeping the order in which the keys were first set. c.mu must be held.
+func (c *Canonical) set(key string, value interface{}) {
+	if _, ok := c.values[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.values[key] = value
+}
+
+// Emit writes the canonical log line with the message "canonical-log-line", the context of
+// StartCanonical, the status, typically the HTTP status code, the duration_ms since
+// StartCanonical and the fields set, in order. The entry is written at InfoLevel, or at
+// ErrorLevel for statuses of 500 and above. Only the first call writes an entry.
+func (c *Canonical) Emit(status int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if c.emitted {
+		c.mu.Unlock()
+		return
+	}
+	c.emitted = true
+	keyVals := make([]interface{}, 0, 4+2*len(c.keys))
+	keyVals = append(keyVals, "status", status, "duration_ms", float64(time.Since(c.start).Microseconds())/1000)
+	for _, key := range c.keys {
+		keyVals = append(keyVals, key, c.values[key])
+	}
+	c.mu.Unlock()
+
+	lvl := zapcore.InfoLevel
+	if status >= 500 {
+		lvl = zapcore.ErrorLevel
+	}
+	c.l.log(c.ctx, lvl, canonicalMessage, keyVals)
+}
@@ -0,0 +1,53 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx, canon := l.StartCanonical(logger.ContextWithRequestID(context.Background(), "req-1"))
+
+	canon.Set("route", "/orders")
+	logger.CanonicalFromContext(ctx).Add("db_calls", 2)
+	logger.CanonicalFromContext(ctx).Add("db_calls", 1)
+	canon.Set("route", "/orders/{id}")
+	canon.Emit(200)
+	canon.Emit(500)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1, "only the first Emit should write an entry")
+	require.Equal(t, "info", entries[0]["level"])
+	require.Equal(t, "canonical-log-line", entries[0]["msg"])
+	require.Equal(t, float64(200), entries[0]["status"])
+	require.Equal(t, float64(3), entries[0]["db_calls"])
+	require.Equal(t, "/orders/{id}", entries[0]["route"])
+	require.Equal(t, "req-1", entries[0]["request_id"])
+	require.Contains(t, entries[0], "duration_ms")
+}
+
+func TestCanonicalServerError(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	_, canon := l.StartCanonical(context.Background())
+
+	canon.Emit(503)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "error", entries[0]["level"])
+}
+
+func TestCanonicalNil(t *testing.T) {
+	canon := logger.CanonicalFromContext(context.Background())
+	require.Nil(t, canon)
+
+	require.NotPanics(t, func() {
+		canon.Set("key", "value")
+		canon.Add("count", 1)
+		canon.Emit(200)
+	})
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// cloudMetadataTimeout bounds the detection of the cloud provider by WithCloudMetadata, so
+// that creating a Logger outside of a cloud only takes that long.
+const cloudMetadataTimeout = 300 * time.Millisecond
+
+// metadataEndpoint is the link-local address of the instance metadata services of EC2, GCE and
+// Azure.
+var metadataEndpoint = "http://169.254.169.254"
+
+// cloudMetadata is the metadata of the instance the process runs on.
+type cloudMetadata struct {
+	provider, region, zone, instanceID, instanceType string
+}
+
+// WithCloudMetadata queries the instance metadata services of AWS EC2, Google Compute Engine
+// and Azure when the Logger is created, and adds the cloud_provider, cloud_region, cloud_zone,
+// instance_id and instance_type fields of the instance the process runs on to every entry.
+// The services are queried concurrently and the detection gives up after 300ms, so outside of
+// a cloud no fields are added and New is delayed by that long at most. The fields have the
+// precedence of FieldsFromPlatform.
+func WithCloudMetadata() Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromPlatform,
+			load: func() ([]keyVal, error) {
+				ctx, cancel := context.WithTimeout(context.Background(), cloudMetadataTimeout)
+				defer cancel()
+				md, ok := detectCloud(ctx)
+				if !ok {
+					return nil, nil
+				}
+				fields := map[string]interface{}{"cloud_provider": md.provider}
+				for key, value := range map[string]string{
+					"cloud_region":  md.region,
+					"cloud_zone":    md.zone,
+					"instance_id":   md.instanceID,
+					"instance_type": md.instanceType,
+				} {
+					if value != "" {
+						fields[key] = value
+					}
+				}
+				return mapFields(fields), nil
+			},
+		})
+	}
+}
+
+// detectCloud queries the metadata services of all providers concurrently, and returns the
+// metadata of the first one that answers. The services are link-local, so proxies are not used.
+func detectCloud(ctx context.Context) (cloudMetadata, bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	detectors := []func(context.Context, *http.Client) (cloudMetadata, error){awsMetadata, gcpMetadata, azureMetadata}
+	results := make(chan cloudMetadata, len(detectors))
+	for _, detect := range detectors {
+		go func() {
+			md, _ := detect(ctx, client)
+			results <- md
+		}()
+	}
+	for range detectors {
+		if md := <-results; md.provider != "" && md.instanceID != "" {
+			return md, true
+		}
+	}
+	return cloudMetadata{}, false
+}
+
+// awsMetadata reads the instance identity document of EC2 through IMDSv2.
+func awsMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, metadataEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := fetchMetadata(client, req)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, metadataEndpoint+"/latest/dynamic/instance-identity/document", nil)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+	var doc struct {
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+	}
+	if err := fetchMetadataJSON(client, req, &doc); err != nil {
+		return cloudMetadata{}, err
+	}
+	return cloudMetadata{
+		provider:     "aws",
+		region:       doc.Region,
+		zone:         doc.AvailabilityZone,
+		instanceID:   doc.InstanceID,
+		instanceType: doc.InstanceType,
+	}, nil
+}
+
+// gcpMetadata reads the instance metadata of Compute Engine.
+func gcpMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataEndpoint+"/computeMetadata/v1/instance/?recursive=true", nil)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var instance struct {
+		ID          json.Number `json:"id"`
+		Zone        string      `json:"zone"`
+		MachineType string      `json:"machineType"`
+	}
+	if err := fetchMetadataJSON(client, req, &instance); err != nil {
+		return cloudMetadata{}, err
+	}
+
+	// The zone and machine type are resource names, such as projects/123/zones/us-central1-a.
+	zone := instance.Zone[strings.LastIndexByte(instance.Zone, '/')+1:]
+	region := zone
+	if i := strings.LastIndexByte(zone, '-'); i > 0 {
+		region = zone[:i]
+	}
+	return cloudMetadata{
+		provider:     "gcp",
+		region:       region,
+		zone:         zone,
+		instanceID:   instance.ID.String(),
+		instanceType: instance.MachineType[strings.LastIndexByte(instance.MachineType, '/')+1:],
+	}, nil
+}
+
+// azureMetadata reads the compute metadata of an Azure virtual machine.
+func azureMetadata(ctx context.Context, client *http.Client) (cloudMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataEndpoint+"/metadata/instance/compute?api-version=2021-02-01", nil)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+	req.Header.Set("Metadata", "true")
+	var compute struct {
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+		VMID     string `json:"vmId"`
+		VMSize   string `json:"vmSize"`
+	}
+	if err := fetchMetadataJSON(client, req, &compute); err != nil {
+		return cloudMetadata{}, err
+	}
+	return cloudMetadata{
+		provider:     "azure",
+		region:       compute.Location,
+		zone:         compute.Zone,
+		instanceID:   compute.VMID,
+		instanceType: compute.VMSize,
+	}, nil
+}
+
+// fetchMetadata sends req and returns the body of its successful response.
+func fetchMetadata(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata service returned %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+}
+
+// fetchMetadataJSON sends req and decodes the JSON body of its successful response into v.
+func fetchMetadataJSON(client *http.Client, req *http.Request, v interface{}) error {
+	body, err := fetchMetadata(client, req)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return errors.New("empty metadata")
+	}
+	return json.Unmarshal(body, v)
+}
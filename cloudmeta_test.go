@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// serveMetadata points WithCloudMetadata at handler for the duration of the test.
+func serveMetadata(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	endpoint := *logger.MetadataEndpoint
+	*logger.MetadataEndpoint = srv.URL
+	t.Cleanup(func() { *logger.MetadataEndpoint = endpoint })
+}
+
+func TestWithCloudMetadataAWS(t *testing.T) {
+	serveMetadata(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("token"))
+		case r.URL.Path == "/latest/dynamic/instance-identity/document" && r.Header.Get("X-aws-ec2-metadata-token") == "token":
+			_, _ = w.Write([]byte(`{"region":"eu-west-1","availabilityZone":"eu-west-1a","instanceId":"i-0abc","instanceType":"m5.large"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	l, sink := newMemoryLogger(t, logger.WithCloudMetadata(), logger.WithFields("cloud_region", "override"))
+
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "aws", entry["cloud_provider"])
+	require.Equal(t, "override", entry["cloud_region"], "code should override the platform fields")
+	require.Equal(t, "eu-west-1a", entry["cloud_zone"])
+	require.Equal(t, "i-0abc", entry["instance_id"])
+	require.Equal(t, "m5.large", entry["instance_type"])
+	require.Equal(t, logger.FieldsFromPlatform, l.FieldConflicts()[0].OverriddenSource)
+}
+
+func TestWithCloudMetadataGCP(t *testing.T) {
+	serveMetadata(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/computeMetadata/v1/instance/" || r.Header.Get("Metadata-Flavor") != "Google" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":4520031799277581759,"zone":"projects/123/zones/us-central1-a","machineType":"projects/123/machineTypes/e2-medium"}`))
+	})
+	l, sink := newMemoryLogger(t, logger.WithCloudMetadata())
+
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "gcp", entry["cloud_provider"])
+	require.Equal(t, "us-central1", entry["cloud_region"])
+	require.Equal(t, "us-central1-a", entry["cloud_zone"])
+	require.Equal(t, "4520031799277581759", entry["instance_id"])
+	require.Equal(t, "e2-medium", entry["instance_type"])
+}
+
+func TestWithCloudMetadataUnavailable(t *testing.T) {
+	serveMetadata(t, http.NotFound)
+	l, sink := newMemoryLogger(t, logger.WithCloudMetadata())
+
+	l.Info(context.Background(), "hello")
+
+	require.NotContains(t, decodeLines(t, sink)[0], "cloud_provider")
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"os"
+)
+
+// ColorMode controls whether the console format colors its output.
+type ColorMode int
+
+const (
+	// ColorAuto colors output when writing to a terminal, honoring the NO_COLOR,
+	// FORCE_COLOR, CLICOLOR and CLICOLOR_FORCE environment variables. This is the default.
+	ColorAuto ColorMode = iota
+	// ColorAlways always colors output, regardless of the outputs and environment.
+	ColorAlways
+	// ColorNever never colors output.
+	ColorNever
+)
+
+// WithColor allows the color mode of the console format to be set, overriding the
+// environment variables consulted by ColorAuto.
+func WithColor(mode ColorMode) Option {
+	return func(l *Logger) {
+		l.colorMode = mode
+	}
+}
+
+// colorEnabled reports whether ANSI colors should be used for the given output paths.
+// In ColorAuto mode the environment conventions take precedence, in this order:
+//   - NO_COLOR set to any non-empty value disables colors (https://no-color.org).
+//   - FORCE_COLOR enables colors, unless it is "0" or "false", which disables them.
+//   - CLICOLOR_FORCE set to anything but "0" enables colors.
+//   - CLICOLOR=0 disables colors.
+//
+// Otherwise colors are only used when every output is a terminal, so files and pipes,
+// such as CI logs, stay free of escape codes.
+func colorEnabled(mode ColorMode, outputPaths []string, getenv func(string) string) bool {
+	switch mode {
+	case ColorAlways:
+		for _, f := range outputFiles(outputPaths) {
+			enableVirtualTerminal(f)
+		}
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch force := getenv("FORCE_COLOR"); force {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if getenv("CLICOLOR") == "0" {
+		return false
+	}
+
+	files := outputFiles(outputPaths)
+	if len(files) == 0 || len(files) != len(outputPaths) {
+		return false
+	}
+	for _, f := range files {
+		if !isTerminal(f) || !enableVirtualTerminal(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// outputFiles returns the standard streams among the given output paths.
+func outputFiles(outputPaths []string) []*os.File {
+	var files []*os.File
+	for _, path := range outputPaths {
+		switch path {
+		case "stdout":
+			files = append(files, os.Stdout)
+		case "stderr":
+			files = append(files, os.Stderr)
+		}
+	}
+	return files
+}
+
+// isTerminal reports whether f is a character device, such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+)
+
+// enableVirtualTerminal reports whether f understands ANSI escape codes.
+// Terminals on non-Windows platforms support them natively.
+func enableVirtualTerminal(_ *os.File) bool {
+	return true
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package logger_test
+
+import (
+	"os"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableVirtualTerminal(t *testing.T) {
+	require.True(t, logger.EnableVirtualTerminal(os.Stdout), "non-Windows terminals support ANSI natively")
+}
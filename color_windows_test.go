@@ -0,0 +1,32 @@
+//go:build windows
+
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableVirtualTerminalNotAConsole(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.False(t, logger.EnableVirtualTerminal(f), "files are not consoles")
+}
+
+func TestColorDisabledForRedirectedStdout(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdout.log"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	stdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	require.False(t, logger.ColorEnabled(logger.ColorAuto, []string{"stdout"}, func(string) string { return "" }))
+}
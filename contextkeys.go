@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+)
+
+// The keys of the fields injected from the values stored through the context helpers below.
+const (
+	RequestIDKey = "request_id"
+	TenantIDKey  = "tenant_id"
+	UserIDKey    = "user_id"
+	LocaleKey    = "locale"
+)
+
+// contextKey is the type of the context keys of the values injected into every entry.
+// Being unexported, only the helpers of this package can set them, so middleware and the
+// logger agree on where the values are stored.
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	tenantIDContextKey
+	userIDContextKey
+	localeContextKey
+)
+
+// contextIDs lists the context values injected into every entry, in order.
+var contextIDs = []struct {
+	key   contextKey
+	field string
+}{
+	{requestIDContextKey, RequestIDKey},
+	{tenantIDContextKey, TenantIDKey},
+	{userIDContextKey, UserIDKey},
+	{localeContextKey, LocaleKey},
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID. Entries logged with
+// the context, or a context derived from it, include it as request_id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, requestIDContextKey)
+}
+
+// ContextWithTenantID returns a copy of ctx carrying the tenant ID. Entries logged with
+// the context, or a context derived from it, include it as tenant_id.
+func ContextWithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantIDContextKey, id)
+}
+
+// TenantIDFromContext returns the tenant ID carried by ctx, or an empty string.
+func TenantIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, tenantIDContextKey)
+}
+
+// ContextWithUserID returns a copy of ctx carrying the user ID. Entries logged with
+// the context, or a context derived from it, include it as user_id. Use WithHashFields to
+// pseudonymize it in the logs.
+func ContextWithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDContextKey, id)
+}
+
+// UserIDFromContext returns the user ID carried by ctx, or an empty string.
+func UserIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, userIDContextKey)
+}
+
+// ContextWithLocale returns a copy of ctx carrying the locale of the request, e.g. "nl-NL".
+// Entries logged with the context, or a context derived from it, include it as locale.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey, locale)
+}
+
+// LocaleFromContext returns the locale carried by ctx, or an empty string.
+func LocaleFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, localeContextKey)
+}
+
+// ContextValue returns a GetFieldsFn adding the value stored in the context under ctxKey, for
+// example by the middleware of another package, as the field key. Contexts without the value,
+// or with a nil value, add nothing.
+func ContextValue(key string, ctxKey interface{}) GetFieldsFn {
+	return func(ctx context.Context) []interface{} {
+		if ctx == nil {
+			return nil
+		}
+		if v := ctx.Value(ctxKey); v != nil {
+			return []interface{}{key, v}
+		}
+		return nil
+	}
+}
+
+// stringFromContext returns the string stored in ctx under key, or an empty string.
+func stringFromContext(ctx context.Context, key contextKey) string {
+	if ctx == nil {
+		return ""
+	}
+	s, _ := ctx.Value(key).(string)
+	return s
+}
+
+// idFields returns the key-value pairs of the IDs carried by ctx.
+func idFields(ctx context.Context) []interface{} {
+	var keyVals []interface{}
+	for _, id := range contextIDs {
+		if v := stringFromContext(ctx, id.key); v != "" {
+			keyVals = append(keyVals, id.field, v)
+		}
+	}
+	return keyVals
+}
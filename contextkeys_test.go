@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextIDs(t *testing.T) {
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+	ctx = logger.ContextWithTenantID(ctx, "acme")
+	ctx = logger.ContextWithUserID(ctx, "u-42")
+
+	require.Equal(t, "req-1", logger.RequestIDFromContext(ctx))
+	require.Equal(t, "acme", logger.TenantIDFromContext(ctx))
+	require.Equal(t, "u-42", logger.UserIDFromContext(ctx))
+	require.Empty(t, logger.RequestIDFromContext(context.Background()))
+
+	l, sink := newMemoryLogger(t)
+	l.Info(ctx, "with ids")
+	l.Info(logger.ContextWithTenantID(context.Background(), "globex"), "tenant only")
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "req-1", entries[0]["request_id"])
+	require.Equal(t, "acme", entries[0]["tenant_id"])
+	require.Equal(t, "u-42", entries[0]["user_id"])
+	require.Equal(t, "globex", entries[1]["tenant_id"])
+	require.NotContains(t, entries[1], "request_id")
+}
+
+// sessionKey is a context key of another package, read through ContextValue.
+type sessionKey struct{}
+
+func TestWithContextFields(t *testing.T) {
+	region := func(ctx context.Context) []interface{} { return []interface{}{"region", "eu"} }
+	l, sink := newMemoryLogger(t,
+		logger.WithTraceID(func(context.Context) string { return "trace-1" }),
+		logger.WithContextFields(logger.ContextValue("session_id", sessionKey{}), region),
+	)
+
+	ctx := logger.ContextWithLocale(context.Background(), "nl-NL")
+	ctx = context.WithValue(ctx, sessionKey{}, "s-9")
+	l.Info(ctx, "with context")
+	l.Info(context.Background(), "without context")
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "trace-1", entries[0]["trace_id"])
+	require.Equal(t, "nl-NL", entries[0]["locale"])
+	require.Equal(t, "s-9", entries[0]["session_id"])
+	require.Equal(t, "eu", entries[0]["region"])
+	require.NotContains(t, entries[1], "locale")
+	require.NotContains(t, entries[1], "session_id")
+	require.Equal(t, "eu", entries[1]["region"])
+}
@@ -0,0 +1,80 @@
+// Package echolog provides Echo middleware writing access logs and recovered panics through a
+// Logger, with the same field schema as logger.AccessLogMiddleware and contrib/ginlog:
+//
+//	e := echo.New()
+//	e.Use(echolog.Middleware(log), echolog.Recovery(log))
+//
+// Entries are written through the context of the request, so they carry its trace_id.
+package echolog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap/zapcore"
+)
+
+// Middleware returns Echo middleware that writes an access log entry, see logger.LogAccess,
+// for every request once the following handlers return. An error returned by the handlers is
+// first passed to the Echo error handler, so that the logged status is the one sent. The route
+// is the route template of the request, e.g. "/users/:id", and the client_ip is
+// echo.Context.RealIP.
+func Middleware(l *logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			req, res := c.Request(), c.Response()
+			l.LogAccess(req.Context(), logger.AccessLog{
+				Method:    req.Method,
+				Path:      req.URL.Path,
+				Route:     c.Path(),
+				Status:    res.Status,
+				Latency:   time.Since(start),
+				ClientIP:  c.RealIP(),
+				UserAgent: req.UserAgent(),
+				Bytes:     res.Size,
+				Err:       err,
+			})
+			return err
+		}
+	}
+}
+
+// Recovery returns Echo middleware that recovers from panics in the following handlers, logs
+// them at ErrorLevel with the message "recovered from panic", the panic value, the goroutine
+// stack, the method and the path, and returns a 500 Internal Server Error. http.ErrAbortHandler
+// is re-panicked, so that net/http can abort the response as intended.
+func Recovery(l *logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				req := c.Request()
+				keyVals := append(logger.PanicFields(v), "method", req.Method, "path", req.URL.Path)
+				l.Log(req.Context(), zapcore.ErrorLevel, "recovered from panic", keyVals...)
+
+				panicErr, ok := v.(error)
+				if !ok {
+					panicErr = fmt.Errorf("%v", v)
+				}
+				err = echo.NewHTTPError(http.StatusInternalServerError).SetInternal(panicErr)
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package echolog_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/echolog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func newEcho(l *logger.Logger) *echo.Echo {
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := logger.ContextWithRequestID(c.Request().Context(), "req-1")
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}, echolog.Middleware(l), echolog.Recovery(l))
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "hello")
+	})
+	e.GET("/fail", func(echo.Context) error {
+		return echo.NewHTTPError(http.StatusServiceUnavailable).SetInternal(errors.New("db down"))
+	})
+	e.GET("/panic", func(echo.Context) error {
+		panic("handler exploded")
+	})
+	return e
+}
+
+func serve(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	e := newEcho(l)
+
+	serve(e, "/users/42")
+	rec := serve(e, "/fail")
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "http request",
+		"method", "GET", "path", "/users/42", "route", "/users/:id", "status", 200,
+		"client_ip", "10.0.0.1", "bytes", int64(5), "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request", "path", "/fail", "status", 503)
+	require.Len(t, entries.FilterMessage("http request").All(), 2, "errors should be handled only once")
+}
+
+func TestRecovery(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+
+	rec := serve(newEcho(l), "/panic")
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "recovered from panic",
+		"panic", "handler exploded", "path", "/panic", "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request", "status", 500)
+}
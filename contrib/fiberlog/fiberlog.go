@@ -0,0 +1,71 @@
+// Package fiberlog provides Fiber middleware writing access logs and recovered panics through a
+// Logger, with the same field schema as logger.AccessLogMiddleware, contrib/ginlog and
+// contrib/echolog:
+//
+//	app := fiber.New()
+//	app.Use(fiberlog.Middleware(log), fiberlog.Recovery(log))
+//
+// Entries are written through fiber.Ctx.UserContext, so they carry the trace_id of the context
+// set with SetUserContext.
+package fiberlog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Middleware returns Fiber middleware that writes an access log entry, see logger.LogAccess,
+// for every request once the following handlers return. An error returned by the handlers is
+// passed to the app's error handler, so that the logged status is the one sent, and is not
+// returned. The route is the route template of the request, e.g. "/users/:id", and the
+// client_ip is fiber.Ctx.IP.
+func Middleware(l *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// fasthttp reuses the buffers of the request once the handler returns, so the strings
+		// are cloned in case the Logger holds the fields, e.g. for WithPrecedingDebug.
+		l.LogAccess(c.UserContext(), logger.AccessLog{
+			Method:    strings.Clone(c.Method()),
+			Path:      strings.Clone(c.Path()),
+			Route:     c.Route().Path,
+			Status:    c.Response().StatusCode(),
+			Latency:   time.Since(start),
+			ClientIP:  strings.Clone(c.IP()),
+			UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+			Bytes:     int64(len(c.Response().Body())),
+			Err:       err,
+		})
+		return nil
+	}
+}
+
+// Recovery returns Fiber middleware that recovers from panics in the following handlers, logs
+// them at ErrorLevel with the message "recovered from panic", the panic value, the goroutine
+// stack, the method and the path, and returns a 500 Internal Server Error.
+func Recovery(l *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			keyVals := append(logger.PanicFields(v), "method", strings.Clone(c.Method()), "path", strings.Clone(c.Path()))
+			l.Log(c.UserContext(), zapcore.ErrorLevel, "recovered from panic", keyVals...)
+
+			err = fiber.ErrInternalServerError
+		}()
+		return c.Next()
+	}
+}
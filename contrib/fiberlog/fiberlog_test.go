@@ -0,0 +1,67 @@
+package fiberlog_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/janduursma/zap-logger-wrapper/contrib/fiberlog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func newApp(l *logger.Logger) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(logger.ContextWithRequestID(c.UserContext(), "req-1"))
+		return c.Next()
+	}, fiberlog.Middleware(l), fiberlog.Recovery(l))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendString("hello")
+	})
+	app.Get("/fail", func(*fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "db down")
+	})
+	app.Get("/panic", func(*fiber.Ctx) error {
+		panic(errors.New("handler exploded"))
+	})
+	return app
+}
+
+func serve(t *testing.T, app *fiber.App, path string) *http.Response {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func TestMiddleware(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	app := newApp(l)
+
+	serve(t, app, "/users/42")
+	resp := serve(t, app, "/fail")
+
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "http request",
+		"method", "GET", "path", "/users/42", "route", "/users/:id", "status", 200,
+		"client_ip", "0.0.0.0", "bytes", int64(5), "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request",
+		"path", "/fail", "status", 503, "error", "db down")
+}
+
+func TestRecovery(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+
+	resp := serve(t, newApp(l), "/panic")
+
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "recovered from panic",
+		"panic", "handler exploded", "error", "handler exploded", "path", "/panic", "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request", "status", 500)
+}
@@ -0,0 +1,82 @@
+// Package ginlog provides Gin middleware writing access logs and recovered panics through a
+// Logger, with the same field schema as logger.AccessLogMiddleware and
+// logger.RecoverMiddleware. It replaces the writers of gin.Default():
+//
+//	r := gin.New()
+//	r.Use(ginlog.Middleware(log), ginlog.Recovery(log))
+//
+// Entries are written through the context of the request, so they carry its trace_id.
+package ginlog
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Middleware returns Gin middleware that writes an access log entry, see logger.LogAccess,
+// for every request once the following handlers return. The route is the route template
+// of the request, e.g. "/users/:id", the client_ip is gin.Context.ClientIP, and the error the
+// last error added to the context.
+func Middleware(l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		a := logger.AccessLog{
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Route:     c.FullPath(),
+			Status:    c.Writer.Status(),
+			Latency:   time.Since(start),
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Bytes:     int64(max(c.Writer.Size(), 0)),
+		}
+		if err := c.Errors.Last(); err != nil {
+			a.Err = err
+		}
+		l.LogAccess(c.Request.Context(), a)
+	}
+}
+
+// Recovery returns Gin middleware that recovers from panics in the following handlers, logs
+// them at ErrorLevel with the message "recovered from panic", the panic value, the goroutine
+// stack, the method and the path, and responds with 500 Internal Server Error. No response is
+// written when the panic was caused by a broken connection, and http.ErrAbortHandler is
+// re-panicked, so that net/http can abort the response as intended.
+func Recovery(l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			keyVals := append(logger.PanicFields(v), "method", c.Request.Method, "path", c.Request.URL.Path)
+			l.Log(c.Request.Context(), zapcore.ErrorLevel, "recovered from panic", keyVals...)
+
+			if err, ok := v.(error); ok && brokenConnection(err) {
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
+
+// brokenConnection reports whether err is caused by the client closing the connection, in
+// which case no response can be written.
+func brokenConnection(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
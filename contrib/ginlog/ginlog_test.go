@@ -0,0 +1,74 @@
+package ginlog_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/janduursma/zap-logger-wrapper/contrib/ginlog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newRouter(l *logger.Logger) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := logger.ContextWithRequestID(c.Request.Context(), "req-1")
+		c.Request = c.Request.WithContext(ctx)
+	}, ginlog.Middleware(l), ginlog.Recovery(l))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("db down"))
+		c.Status(http.StatusServiceUnavailable)
+	})
+	r.GET("/panic", func(*gin.Context) {
+		panic("handler exploded")
+	})
+	return r
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	r := newRouter(l)
+
+	serve(r, "/users/42")
+	serve(r, "/fail")
+
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "http request",
+		"method", "GET", "path", "/users/42", "route", "/users/:id", "status", 200,
+		"client_ip", "10.0.0.1", "bytes", int64(5), "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request", "status", 503)
+	fail := entries.FilterField("path", "/fail").All()
+	require.Len(t, fail, 1)
+	require.Equal(t, "db down", fail[0].ContextMap()["error"])
+}
+
+func TestRecovery(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+
+	rec := serve(newRouter(l), "/panic")
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "recovered from panic",
+		"panic", "handler exploded", "path", "/panic", "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "http request", "status", 500)
+}
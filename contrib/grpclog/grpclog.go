@@ -0,0 +1,190 @@
+// Package grpclog installs a Logger as the internal logger of gRPC-go, so that transport and
+// connectivity messages are written as structured entries instead of raw stderr lines:
+//
+//	grpclog.Install(log, grpclog.WithInfoLevel(zapcore.DebugLevel))
+//
+// Install replaces gRPC's logger through gogrpclog.SetLoggerV2, which is not safe for
+// concurrent use: call it before any other gRPC function. The component prefix of gRPC's
+// messages, such as "[core]", is logged as the grpc_component field. gRPC passes no context,
+// so the entries carry no trace ID.
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+	gogrpclog "google.golang.org/grpc/grpclog"
+)
+
+// componentKey is the key of the field holding the gRPC component that logged the entry.
+const componentKey = "grpc_component"
+
+// verbosityEnv is the environment variable gRPC reads its verbosity from.
+const verbosityEnv = "GRPC_GO_LOG_VERBOSITY_LEVEL"
+
+// config holds the settings of the logger.
+type config struct {
+	infoLevel zapcore.Level
+	verbosity int
+}
+
+// Option defines a functional option for configuring the logger.
+type Option func(cfg *config)
+
+// WithInfoLevel sets the level at which gRPC's info messages are written. It defaults to
+// InfoLevel; DebugLevel keeps gRPC's chatty connectivity messages out of production logs.
+func WithInfoLevel(level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.infoLevel = level
+	}
+}
+
+// WithVerbosity sets gRPC's verbosity: V(v) reports true only for v up to verbosity, so gRPC
+// logs its verbose info messages up to that level. It defaults to the value of the
+// GRPC_GO_LOG_VERBOSITY_LEVEL environment variable, or 0.
+func WithVerbosity(verbosity int) Option {
+	return func(cfg *config) {
+		cfg.verbosity = verbosity
+	}
+}
+
+// Logger is a gogrpclog.LoggerV2 writing through a Logger.
+type Logger struct {
+	l   *logger.Logger
+	cfg config
+}
+
+// New returns a gogrpclog.LoggerV2 writing through l.
+func New(l *logger.Logger, opts ...Option) *Logger {
+	cfg := config{infoLevel: zapcore.InfoLevel}
+	if v, err := strconv.Atoi(os.Getenv(verbosityEnv)); err == nil {
+		cfg.verbosity = v
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Logger{l: l, cfg: cfg}
+}
+
+// Install sets a Logger writing through l as gRPC's internal logger.
+func Install(l *logger.Logger, opts ...Option) {
+	gogrpclog.SetLoggerV2(New(l, opts...))
+}
+
+// Info implements gogrpclog.LoggerV2.
+func (g *Logger) Info(args ...any) {
+	g.log(0, g.cfg.infoLevel, fmt.Sprint(args...))
+}
+
+// Infoln implements gogrpclog.LoggerV2.
+func (g *Logger) Infoln(args ...any) {
+	g.log(0, g.cfg.infoLevel, fmt.Sprintln(args...))
+}
+
+// Infof implements gogrpclog.LoggerV2.
+func (g *Logger) Infof(format string, args ...any) {
+	g.log(0, g.cfg.infoLevel, fmt.Sprintf(format, args...))
+}
+
+// InfoDepth implements gogrpclog.DepthLoggerV2.
+func (g *Logger) InfoDepth(depth int, args ...any) {
+	g.log(depth, g.cfg.infoLevel, fmt.Sprintln(args...))
+}
+
+// Warning implements gogrpclog.LoggerV2.
+func (g *Logger) Warning(args ...any) {
+	g.log(0, zapcore.WarnLevel, fmt.Sprint(args...))
+}
+
+// Warningln implements gogrpclog.LoggerV2.
+func (g *Logger) Warningln(args ...any) {
+	g.log(0, zapcore.WarnLevel, fmt.Sprintln(args...))
+}
+
+// Warningf implements gogrpclog.LoggerV2.
+func (g *Logger) Warningf(format string, args ...any) {
+	g.log(0, zapcore.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// WarningDepth implements gogrpclog.DepthLoggerV2.
+func (g *Logger) WarningDepth(depth int, args ...any) {
+	g.log(depth, zapcore.WarnLevel, fmt.Sprintln(args...))
+}
+
+// Error implements gogrpclog.LoggerV2.
+func (g *Logger) Error(args ...any) {
+	g.log(0, zapcore.ErrorLevel, fmt.Sprint(args...))
+}
+
+// Errorln implements gogrpclog.LoggerV2.
+func (g *Logger) Errorln(args ...any) {
+	g.log(0, zapcore.ErrorLevel, fmt.Sprintln(args...))
+}
+
+// Errorf implements gogrpclog.LoggerV2.
+func (g *Logger) Errorf(format string, args ...any) {
+	g.log(0, zapcore.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// ErrorDepth implements gogrpclog.DepthLoggerV2.
+func (g *Logger) ErrorDepth(depth int, args ...any) {
+	g.log(depth, zapcore.ErrorLevel, fmt.Sprintln(args...))
+}
+
+// Fatal implements gogrpclog.LoggerV2, exiting after the entry has been written.
+func (g *Logger) Fatal(args ...any) {
+	g.log(0, zapcore.FatalLevel, fmt.Sprint(args...))
+}
+
+// Fatalln implements gogrpclog.LoggerV2, exiting after the entry has been written.
+func (g *Logger) Fatalln(args ...any) {
+	g.log(0, zapcore.FatalLevel, fmt.Sprintln(args...))
+}
+
+// Fatalf implements gogrpclog.LoggerV2, exiting after the entry has been written.
+func (g *Logger) Fatalf(format string, args ...any) {
+	g.log(0, zapcore.FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// FatalDepth implements gogrpclog.DepthLoggerV2, exiting after the entry has been written.
+func (g *Logger) FatalDepth(depth int, args ...any) {
+	g.log(depth, zapcore.FatalLevel, fmt.Sprintln(args...))
+}
+
+// V implements gogrpclog.LoggerV2, reporting whether verbose info messages at level v are
+// written.
+func (g *Logger) V(v int) bool {
+	return v <= g.cfg.verbosity && g.l.Enabled(g.cfg.infoLevel)
+}
+
+// log writes msg at level, with its component prefix as a field. gRPC calls the Logger through
+// the functions of its grpclog package, so the entry's caller is the function depth frames above
+// the caller of that function, as for the depth the Depth methods receive.
+func (g *Logger) log(depth int, level zapcore.Level, msg string) {
+	msg = strings.TrimSpace(msg)
+	var keyVals []interface{}
+	if component, rest, ok := cutComponent(msg); ok {
+		keyVals = []interface{}{componentKey, component}
+		msg = rest
+	}
+	g.l.LogDepth(context.Background(), depth+3, level, msg, keyVals...)
+}
+
+// cutComponent splits the "[component]" prefix gRPC adds to the messages of its components
+// from msg.
+func cutComponent(msg string) (component, rest string, ok bool) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg, false
+	}
+	end := strings.IndexByte(msg, ']')
+	if end < 2 || strings.ContainsAny(msg[1:end], " #") {
+		// Not a component, e.g. "[Channel #1]" or "[]".
+		return "", msg, false
+	}
+	return msg[1:end], strings.TrimSpace(msg[end+1:]), true
+}
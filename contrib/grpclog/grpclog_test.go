@@ -0,0 +1,52 @@
+package grpclog_test
+
+import (
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/grpclog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	gogrpclog "google.golang.org/grpc/grpclog"
+)
+
+var _ gogrpclog.DepthLoggerV2 = (*grpclog.Logger)(nil)
+
+func TestInstall(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	grpclog.Install(l, grpclog.WithInfoLevel(zapcore.DebugLevel))
+
+	core := gogrpclog.Component("core")
+	core.Warningf("[Channel #1] addrConn.createTransport failed to connect to %q", "10.0.0.1:443")
+	core.Info("Channel Connectivity change to READY")
+	gogrpclog.Errorf("grpc: server failed to encode response: %v", "boom")
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel,
+		`[Channel #1] addrConn.createTransport failed to connect to "10.0.0.1:443"`, "grpc_component", "core")
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "Channel Connectivity change to READY",
+		"grpc_component", "core")
+	errs := entries.FilterLevel(zapcore.ErrorLevel).All()
+	require.Len(t, errs, 1)
+	require.Equal(t, "grpc: server failed to encode response: boom", errs[0].Message)
+	require.NotContains(t, errs[0].ContextMap(), "grpc_component")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/grpclog_test\.go:\d+$`, e.Caller.String())
+	}
+}
+
+func TestVerbosity(t *testing.T) {
+	t.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "2")
+	l, _ := loggertest.NewTestLogger(t)
+
+	g := grpclog.New(l)
+	require.True(t, g.V(2))
+	require.False(t, g.V(3))
+
+	g = grpclog.New(l, grpclog.WithVerbosity(0))
+	require.True(t, g.V(0))
+	require.False(t, g.V(1))
+
+	l, _ = loggertest.NewTestLogger(t, logger.WithLevel(zapcore.WarnLevel))
+	require.False(t, grpclog.New(l).V(0), "V should be false when info messages are discarded")
+}
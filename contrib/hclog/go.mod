@@ -0,0 +1,24 @@
+module github.com/janduursma/zap-logger-wrapper/contrib/hclog
+
+go 1.24.0
+
+require (
+	github.com/hashicorp/go-hclog v1.6.3
+	github.com/janduursma/zap-logger-wrapper/v2 v2.0.1
+	github.com/stretchr/testify v1.10.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fatih/color v1.13.0 // indirect
+	github.com/mattn/go-colorable v0.1.12 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.5.2 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/sys v0.0.0-20220503163025-988cb79eb6c6 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/janduursma/zap-logger-wrapper/v2 => ../..
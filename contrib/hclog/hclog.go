@@ -0,0 +1,264 @@
+// Package hclog implements HashiCorp's hclog.Logger on top of a Logger, so that Vault, Consul,
+// raft and other libraries using hclog write structured entries with the service's fields,
+// levels and sinks:
+//
+//	raftConfig.Logger = hclog.New(log.ForLibrary("raft", zapcore.InfoLevel))
+//
+// Trace entries are written at DebugLevel, the name set through Named as the logger field, and
+// the arguments as fields; gohclog.Format arguments are formatted. hclog passes no context,
+// so the entries carry no trace ID.
+package hclog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	gohclog "github.com/hashicorp/go-hclog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// nameKey is the key of the field holding the name of the hclog logger.
+const nameKey = "logger"
+
+// extraValueKey is the key of the last argument when the arguments are not pairs, as in hclog.
+const extraValueKey = "EXTRA_VALUE_AT_END"
+
+// Logger is a gohclog.Logger writing through a Logger.
+type Logger struct {
+	l       *logger.Logger
+	name    string
+	implied []interface{}
+	// level is shared with the loggers derived through With and Named, as in hclog.
+	level *atomic.Int32
+}
+
+// New returns a gohclog.Logger writing through l. Its level is gohclog.NoLevel, leaving the
+// filtering to l, until SetLevel is called.
+func New(l *logger.Logger) *Logger {
+	return &Logger{l: l, level: new(atomic.Int32)}
+}
+
+// Log implements gohclog.Logger.
+func (h *Logger) Log(level gohclog.Level, msg string, args ...interface{}) {
+	if !h.enabled(level) {
+		return
+	}
+	keyVals := make([]interface{}, 0, len(h.implied)+len(args)+3)
+	if h.name != "" {
+		keyVals = append(keyVals, nameKey, h.name)
+	}
+	keyVals = append(keyVals, h.implied...)
+	keyVals = append(keyVals, pairs(args)...)
+	// The level methods and the standard library loggers of StandardLogger call Log.
+	h.l.LogDepth(context.Background(), logger.CallerDepth("log"), zapLevel(level), msg, keyVals...)
+}
+
+// Trace implements gohclog.Logger.
+func (h *Logger) Trace(msg string, args ...interface{}) {
+	h.Log(gohclog.Trace, msg, args...)
+}
+
+// Debug implements gohclog.Logger.
+func (h *Logger) Debug(msg string, args ...interface{}) {
+	h.Log(gohclog.Debug, msg, args...)
+}
+
+// Info implements gohclog.Logger.
+func (h *Logger) Info(msg string, args ...interface{}) {
+	h.Log(gohclog.Info, msg, args...)
+}
+
+// Warn implements gohclog.Logger.
+func (h *Logger) Warn(msg string, args ...interface{}) {
+	h.Log(gohclog.Warn, msg, args...)
+}
+
+// Error implements gohclog.Logger.
+func (h *Logger) Error(msg string, args ...interface{}) {
+	h.Log(gohclog.Error, msg, args...)
+}
+
+// IsTrace implements gohclog.Logger.
+func (h *Logger) IsTrace() bool {
+	return h.enabled(gohclog.Trace)
+}
+
+// IsDebug implements gohclog.Logger.
+func (h *Logger) IsDebug() bool {
+	return h.enabled(gohclog.Debug)
+}
+
+// IsInfo implements gohclog.Logger.
+func (h *Logger) IsInfo() bool {
+	return h.enabled(gohclog.Info)
+}
+
+// IsWarn implements gohclog.Logger.
+func (h *Logger) IsWarn() bool {
+	return h.enabled(gohclog.Warn)
+}
+
+// IsError implements gohclog.Logger.
+func (h *Logger) IsError() bool {
+	return h.enabled(gohclog.Error)
+}
+
+// ImpliedArgs implements gohclog.Logger.
+func (h *Logger) ImpliedArgs() []interface{} {
+	return h.implied
+}
+
+// With implements gohclog.Logger.
+func (h *Logger) With(args ...interface{}) gohclog.Logger {
+	child := *h
+	child.implied = append(h.implied[:len(h.implied):len(h.implied)], pairs(args)...)
+	return &child
+}
+
+// Name implements gohclog.Logger.
+func (h *Logger) Name() string {
+	return h.name
+}
+
+// Named implements gohclog.Logger, appending name to the current name with a dot.
+func (h *Logger) Named(name string) gohclog.Logger {
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	return h.ResetNamed(name)
+}
+
+// ResetNamed implements gohclog.Logger.
+func (h *Logger) ResetNamed(name string) gohclog.Logger {
+	child := *h
+	child.name = name
+	return &child
+}
+
+// SetLevel implements gohclog.Logger. Entries below level are discarded, in addition to those
+// discarded by the Logger's level.
+func (h *Logger) SetLevel(level gohclog.Level) {
+	h.level.Store(int32(level))
+}
+
+// GetLevel implements gohclog.Logger.
+func (h *Logger) GetLevel() gohclog.Level {
+	return gohclog.Level(h.level.Load())
+}
+
+// StandardLogger implements gohclog.Logger.
+func (h *Logger) StandardLogger(opts *gohclog.StandardLoggerOptions) *log.Logger {
+	return log.New(h.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter implements gohclog.Logger. Lines are written at InfoLevel, unless
+// opts.ForceLevel is set, or opts.InferLevels is set and the lines start with a level such
+// as [WARN] or [ERROR].
+func (h *Logger) StandardWriter(opts *gohclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &gohclog.StandardLoggerOptions{}
+	}
+	return &stdWriter{h: h, infer: opts.InferLevels, force: opts.ForceLevel}
+}
+
+// enabled reports whether entries at level are written.
+func (h *Logger) enabled(level gohclog.Level) bool {
+	if level == gohclog.Off || level < h.GetLevel() {
+		return false
+	}
+	return h.l.Enabled(zapLevel(level))
+}
+
+// stdWriter is the io.Writer of StandardWriter.
+type stdWriter struct {
+	h     *Logger
+	infer bool
+	force gohclog.Level
+}
+
+// Write implements io.Writer.
+func (w *stdWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, " \t\n"))
+	level := gohclog.Info
+	if w.infer || w.force != gohclog.NoLevel {
+		var inferred gohclog.Level
+		inferred, msg = levelPrefix(msg)
+		if w.force != gohclog.NoLevel {
+			level = w.force
+		} else if inferred != gohclog.NoLevel {
+			level = inferred
+		}
+	}
+	w.h.Log(level, msg)
+	return len(p), nil
+}
+
+// levelPrefixes are the level prefixes of the lines written to a standard logger.
+var levelPrefixes = []struct {
+	prefix string
+	level  gohclog.Level
+}{
+	{"[TRACE]", gohclog.Trace},
+	{"[DEBUG]", gohclog.Debug},
+	{"[INFO]", gohclog.Info},
+	{"[WARN]", gohclog.Warn},
+	{"[ERROR]", gohclog.Error},
+	{"[ERR]", gohclog.Error},
+}
+
+// levelPrefix returns the level msg starts with, if any, and msg without it.
+func levelPrefix(msg string) (gohclog.Level, string) {
+	for _, p := range levelPrefixes {
+		if strings.HasPrefix(msg, p.prefix) {
+			return p.level, strings.TrimSpace(msg[len(p.prefix):])
+		}
+	}
+	return gohclog.NoLevel, msg
+}
+
+// zapLevel maps an hclog level to a zap level.
+func zapLevel(level gohclog.Level) zapcore.Level {
+	switch level {
+	case gohclog.Trace, gohclog.Debug:
+		return zapcore.DebugLevel
+	case gohclog.Warn:
+		return zapcore.WarnLevel
+	case gohclog.Error:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// pairs returns args as key-value pairs: gohclog.Format values are formatted, and a last
+// argument without a value is added under EXTRA_VALUE_AT_END, as hclog does.
+func pairs(args []interface{}) []interface{} {
+	keyVals := make([]interface{}, 0, len(args)+1)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			keyVals = append(keyVals, extraValueKey, value(args[i]))
+			break
+		}
+		keyVals = append(keyVals, fmt.Sprint(args[i]), value(args[i+1]))
+	}
+	return keyVals
+}
+
+// value formats gohclog.Format values, and returns other values unchanged.
+func value(v interface{}) interface{} {
+	f, ok := v.(gohclog.Format)
+	if !ok || len(f) == 0 {
+		return v
+	}
+	format, ok := f[0].(string)
+	if !ok {
+		return fmt.Sprint(f...)
+	}
+	return fmt.Sprintf(format, f[1:]...)
+}
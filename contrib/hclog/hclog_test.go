@@ -0,0 +1,68 @@
+package hclog_test
+
+import (
+	"testing"
+
+	gohclog "github.com/hashicorp/go-hclog"
+	"github.com/janduursma/zap-logger-wrapper/contrib/hclog"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ gohclog.Logger = (*hclog.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	h := hclog.New(l).Named("raft").With("node", "n1")
+
+	h.Trace("heartbeat")
+	h.Warn("failed to contact", "server", "n2", "time", gohclog.Fmt("%dms", 500))
+	h.Named("snapshot").Error("failed", "odd")
+
+	all := entries.All()
+	require.Len(t, all, 3)
+	require.Equal(t, zapcore.DebugLevel, all[0].Level)
+	require.Equal(t, "raft", all[0].ContextMap()["logger"])
+	require.Equal(t, zapcore.WarnLevel, all[1].Level)
+	require.Equal(t, map[string]interface{}{
+		"service": "test-service", "logger": "raft", "node": "n1", "server": "n2", "time": "500ms",
+	}, all[1].ContextMap())
+	require.Equal(t, "raft.snapshot", all[2].ContextMap()["logger"])
+	require.Equal(t, "odd", all[2].ContextMap()["EXTRA_VALUE_AT_END"])
+	require.Equal(t, []interface{}{"node", "n1"}, h.ImpliedArgs())
+	for _, e := range entries.All() {
+		require.Regexp(t, `/hclog_test\.go:\d+$`, e.Caller.String())
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	h := hclog.New(l)
+	child := h.Named("child")
+
+	require.True(t, child.IsTrace())
+	h.SetLevel(gohclog.Warn)
+	require.Equal(t, gohclog.Warn, child.GetLevel(), "derived loggers should share the level")
+	require.False(t, child.IsInfo())
+	require.True(t, child.IsWarn())
+
+	child.Info("dropped")
+	child.Error("kept")
+	loggertest.AssertNotLogged(t, entries, zapcore.InfoLevel, "dropped")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "kept")
+}
+
+func TestStandardLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	std := hclog.New(l).StandardLogger(&gohclog.StandardLoggerOptions{InferLevels: true})
+
+	std.Println("[WARN] raft: heartbeat timeout reached")
+	std.Println("plain line")
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "raft: heartbeat timeout reached")
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "plain line")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/hclog_test\.go:\d+$`, e.Caller.String())
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/janduursma/zap-logger-wrapper/contrib/klog
+
+go 1.24.0
+
+require (
+	github.com/go-logr/logr v1.4.4
+	github.com/janduursma/zap-logger-wrapper/v2 v2.0.1
+	github.com/stretchr/testify v1.10.0
+	go.uber.org/zap v1.27.0
+	k8s.io/klog/v2 v2.140.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.5.2 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/janduursma/zap-logger-wrapper/v2 => ../..
@@ -0,0 +1,153 @@
+// Package klog redirects klog, the logger of client-go and the other Kubernetes libraries, to a
+// Logger, so that controller binaries write one structured log stream:
+//
+//	klog.Redirect(log, klog.WithVerbosity(2))
+//
+// klog's verbosity levels are mapped to zap levels: V(0) messages are written at InfoLevel,
+// and more verbose ones at DebugLevel. The logr.Logger returned by New can also be passed to
+// libraries using logr directly, such as controller-runtime's SetLogger.
+//
+// klog reports unstructured warnings, such as those of klog.Warningf, as info messages to the
+// loggers it redirects to, so they are written at InfoLevel. klog passes no context, so the
+// entries carry no trace ID.
+package klog
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+	goklog "k8s.io/klog/v2"
+)
+
+// nameKey is the key of the field holding the name of the logr logger.
+const nameKey = "logger"
+
+// config holds the settings of the redirect.
+type config struct {
+	verbosity      int
+	debugVerbosity int
+}
+
+// Option defines a functional option for configuring the redirect.
+type Option func(cfg *config)
+
+// WithVerbosity sets the highest verbosity level written, as klog's -v flag does. It defaults
+// to 0, which writes only the non-verbose messages.
+func WithVerbosity(verbosity int) Option {
+	return func(cfg *config) {
+		cfg.verbosity = verbosity
+	}
+}
+
+// WithDebugVerbosity sets the lowest verbosity level written at DebugLevel; the levels below are
+// written at InfoLevel. It defaults to 1.
+func WithDebugVerbosity(verbosity int) Option {
+	return func(cfg *config) {
+		cfg.debugVerbosity = verbosity
+	}
+}
+
+// newConfig applies opts on top of the default settings.
+func newConfig(opts []Option) config {
+	cfg := config{debugVerbosity: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Redirect sets a logr.Logger writing through l as klog's logger, also returned by
+// klog.FromContext, and sets klog's verbosity.
+func Redirect(l *logger.Logger, opts ...Option) {
+	cfg := newConfig(opts)
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	goklog.InitFlags(fs)
+	_ = fs.Set("v", strconv.Itoa(cfg.verbosity))
+	goklog.SetLoggerWithOptions(logr.New(&sink{l: l, cfg: cfg}), goklog.ContextualLogger(true))
+}
+
+// New returns a logr.Logger writing through l.
+func New(l *logger.Logger, opts ...Option) logr.Logger {
+	return logr.New(&sink{l: l, cfg: newConfig(opts)})
+}
+
+// sink is the logr.LogSink of the loggers returned by New.
+type sink struct {
+	l    *logger.Logger
+	cfg  config
+	name string
+
+	// callDepth is the number of frames between the caller of the logr.Logger and the sink.
+	callDepth int
+}
+
+// Init implements logr.LogSink, recording the frames logr adds.
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled implements logr.LogSink.
+func (s *sink) Enabled(level int) bool {
+	return level <= s.cfg.verbosity && s.l.Enabled(s.level(level))
+}
+
+// Info implements logr.LogSink.
+func (s *sink) Info(level int, msg string, keysAndValues ...any) {
+	s.l.LogDepth(context.Background(), s.callDepth+1, s.level(level), msg, s.keyVals(nil, keysAndValues)...)
+}
+
+// Error implements logr.LogSink. err may be nil, as for the errors klog reports.
+func (s *sink) Error(err error, msg string, keysAndValues ...any) {
+	s.l.LogDepth(context.Background(), s.callDepth+1, zapcore.ErrorLevel, msg, s.keyVals(err, keysAndValues)...)
+}
+
+// WithValues implements logr.LogSink.
+func (s *sink) WithValues(keysAndValues ...any) logr.LogSink {
+	child := *s
+	child.l = s.l.With(keysAndValues...)
+	return &child
+}
+
+// WithCallDepth implements logr.CallDepthLogSink, for helpers logging on behalf of their caller,
+// such as klog's own functions.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	child := *s
+	child.callDepth += depth
+	return &child
+}
+
+// WithName implements logr.LogSink, appending name to the current name with a slash, as logr
+// recommends.
+func (s *sink) WithName(name string) logr.LogSink {
+	child := *s
+	if s.name != "" {
+		name = s.name + "/" + name
+	}
+	child.name = name
+	return &child
+}
+
+// level maps a logr verbosity level to a zap level.
+func (s *sink) level(verbosity int) zapcore.Level {
+	if verbosity >= s.cfg.debugVerbosity {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// keyVals returns the fields of an entry: the name, keysAndValues and err.
+func (s *sink) keyVals(err error, keysAndValues []any) []interface{} {
+	keyVals := make([]interface{}, 0, len(keysAndValues)+4)
+	if s.name != "" {
+		keyVals = append(keyVals, nameKey, s.name)
+	}
+	keyVals = append(keyVals, keysAndValues...)
+	if err != nil {
+		keyVals = append(keyVals, "error", err)
+	}
+	return keyVals
+}
@@ -0,0 +1,50 @@
+package klog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/klog"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	goklog "k8s.io/klog/v2"
+)
+
+func TestRedirect(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	klog.Redirect(l, klog.WithVerbosity(2))
+	t.Cleanup(goklog.ClearLogger)
+
+	goklog.InfoS("Starting reflector", "resource", "pods")
+	goklog.V(2).InfoS("Listing and watching", "resource", "pods")
+	goklog.V(4).InfoS("too verbose")
+	goklog.ErrorS(errors.New("connection refused"), "Failed to watch", "resource", "pods")
+	goklog.Warningf("unstructured %s", "warning")
+
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "Starting reflector", "resource", "pods")
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "Listing and watching")
+	loggertest.AssertNotLogged(t, entries, zapcore.DebugLevel, "too verbose")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "Failed to watch", "error", "connection refused")
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "unstructured warning")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/klog_test\.go:\d+$`, e.Caller.String())
+	}
+}
+
+func TestNew(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t, logger.WithLevel(zapcore.InfoLevel))
+	lr := klog.New(l, klog.WithVerbosity(5), klog.WithDebugVerbosity(3)).WithName("controller").WithName("pods")
+
+	lr.V(2).Info("reconciling", "pod", "web-0")
+	require.False(t, lr.V(3).Enabled(), "debug verbosity should be disabled by the Logger's level")
+	lr.WithValues("namespace", "default").Error(nil, "requeue")
+
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "reconciling", "logger", "controller/pods", "pod", "web-0")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "requeue", "namespace", "default")
+	require.NotContains(t, entries.FilterMessage("requeue").All()[0].ContextMap(), "error")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/klog_test\.go:\d+$`, e.Caller.String())
+	}
+}
@@ -0,0 +1,54 @@
+// Package lambda configures a Logger for AWS Lambda functions: WithLambdaMode enables
+// logger.WithLambdaMode and adds the request ID of the invocation to every entry, and Wrap logs
+// the failures of a handler and syncs the Logger after every invocation:
+//
+//	log, err := logger.New("orders", lambda.WithLambdaMode())
+//	...
+//	golambda.Start(lambda.Wrap(log, handle))
+package lambda
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+)
+
+// RequestIDKey is the key of the field holding the request ID of the invocation, as in
+// Lambda's JSON log format.
+const RequestIDKey = "requestId"
+
+// WithLambdaMode enables logger.WithLambdaMode and adds the request ID of the invocation, read
+// from the lambdacontext.LambdaContext of the context, to every entry.
+func WithLambdaMode() logger.Option {
+	return func(l *logger.Logger) {
+		logger.WithLambdaMode()(l)
+		logger.WithContextFields(RequestIDFields)(l)
+	}
+}
+
+// RequestIDFields is a logger.GetFieldsFn returning the requestId field of the invocation of
+// ctx, if any.
+func RequestIDFields(ctx context.Context) []interface{} {
+	lc, ok := lambdacontext.FromContext(ctx)
+	if !ok || lc.AwsRequestID == "" {
+		return nil
+	}
+	return []interface{}{RequestIDKey, lc.AwsRequestID}
+}
+
+// Wrap returns a handler calling handler, logging the error it returns with the message
+// "invocation failed" and the panics it raises, which are re-panicked, and syncing l before
+// returning, so that no entry is lost when Lambda freezes the execution environment.
+func Wrap[In, Out any](l *logger.Logger, handler func(context.Context, In) (Out, error)) func(context.Context, In) (Out, error) {
+	return func(ctx context.Context, in In) (Out, error) {
+		defer func() { _ = l.Sync() }()
+		defer logger.RecoverAndLog(ctx, l, logger.WithRepanic())
+
+		out, err := handler(ctx, in)
+		if err != nil {
+			l.Error(ctx, "invocation failed", "error", err)
+		}
+		return out, err
+	}
+}
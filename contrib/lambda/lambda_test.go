@@ -0,0 +1,44 @@
+package lambda_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/janduursma/zap-logger-wrapper/contrib/lambda"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWrap(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t, lambda.WithLambdaMode())
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+	handler := lambda.Wrap(l, func(ctx context.Context, order string) (string, error) {
+		switch order {
+		case "bad":
+			return "", errors.New("invalid order")
+		case "boom":
+			panic("handler exploded")
+		}
+		l.Info(ctx, "order placed", "order", order)
+		return "ok", nil
+	})
+
+	out, err := handler(ctx, "42")
+	require.NoError(t, err)
+	require.Equal(t, "ok", out)
+	_, err = handler(ctx, "bad")
+	require.EqualError(t, err, "invalid order")
+	require.PanicsWithValue(t, "handler exploded", func() { _, _ = handler(ctx, "boom") })
+
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "order placed", "requestId", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "invocation failed", "error", "invalid order",
+		"requestId", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "recovered from panic", "panic", "handler exploded")
+}
+
+func TestRequestIDFields(t *testing.T) {
+	require.Nil(t, lambda.RequestIDFields(context.Background()))
+}
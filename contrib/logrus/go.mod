@@ -0,0 +1,19 @@
+module github.com/janduursma/zap-logger-wrapper/contrib/logrus
+
+go 1.24.0
+
+require (
+	github.com/janduursma/zap-logger-wrapper/v2 v2.0.1
+	github.com/sirupsen/logrus v1.10.2
+	github.com/stretchr/testify v1.12.1
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/stretchr/objx v0.5.3 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
+
+replace github.com/janduursma/zap-logger-wrapper/v2 => ../..
@@ -0,0 +1,85 @@
+// Package logrus forwards the entries of logrus loggers to a Logger, so that codebases can
+// migrate from logrus service by service while keeping one output pipeline:
+//
+//	logrus.Install(gologrus.StandardLogger(), log)
+//
+// Entries keep their level, message and fields, and are written through the context set with
+// WithContext, so they carry its trace_id. logrus still filters entries by its own level
+// before they reach the hook.
+package logrus
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	gologrus "github.com/sirupsen/logrus"
+	"go.uber.org/zap/zapcore"
+)
+
+// Hook is a gologrus.Hook forwarding entries to a Logger.
+type Hook struct {
+	l *logger.Logger
+}
+
+// NewHook returns a hook forwarding the entries of the loggers it is added to to l.
+func NewHook(l *logger.Logger) *Hook {
+	return &Hook{l: l}
+}
+
+// Install adds a hook forwarding entries to l to lr, and discards the output of lr itself.
+func Install(lr *gologrus.Logger, l *logger.Logger) {
+	lr.AddHook(NewHook(l))
+	lr.SetFormatter(discardFormatter{})
+	lr.SetOutput(io.Discard)
+}
+
+// Levels implements gologrus.Hook, forwarding the entries of all levels.
+func (h *Hook) Levels() []gologrus.Level {
+	return gologrus.AllLevels
+}
+
+// Fire implements gologrus.Hook. Trace entries are written at DebugLevel, and panic and fatal
+// entries at ErrorLevel, since logrus itself panics or exits after the hooks have run.
+func (h *Hook) Fire(entry *gologrus.Entry) error {
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	keyVals := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		keyVals = append(keyVals, key, entry.Data[key])
+	}
+
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	h.l.LogDepth(ctx, logger.CallerDepth("github.com/sirupsen/logrus"), zapLevel(entry.Level), entry.Message, keyVals...)
+	return nil
+}
+
+// zapLevel maps a logrus level to a zap level.
+func zapLevel(level gologrus.Level) zapcore.Level {
+	switch level {
+	case gologrus.TraceLevel, gologrus.DebugLevel:
+		return zapcore.DebugLevel
+	case gologrus.InfoLevel:
+		return zapcore.InfoLevel
+	case gologrus.WarnLevel:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// discardFormatter is a gologrus.Formatter skipping the formatting of entries whose output is
+// discarded.
+type discardFormatter struct{}
+
+// Format implements gologrus.Formatter.
+func (discardFormatter) Format(*gologrus.Entry) ([]byte, error) {
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+package logrus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/logrus"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	gologrus "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ gologrus.Hook = (*logrus.Hook)(nil)
+
+func TestInstall(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	lr := gologrus.New()
+	var out bytes.Buffer
+	lr.SetOutput(&out)
+	lr.SetLevel(gologrus.TraceLevel)
+	logrus.Install(lr, l)
+
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+	lr.WithContext(ctx).WithFields(gologrus.Fields{"order_id": 42}).Info("order placed")
+	lr.Trace("cache miss")
+	lr.WithError(errors.New("boom")).Warn("retrying")
+
+	require.Empty(t, out.String(), "logrus output should be discarded")
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "order placed", "order_id", int64(42), "request_id", "req-1")
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "cache miss")
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "retrying", "error", "boom")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/logrus_test\.go:\d+$`, e.Caller.String())
+	}
+}
+
+func TestHookPanic(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	lr := gologrus.New()
+	logrus.Install(lr, l)
+
+	require.Panics(t, func() { lr.Panic("invariant violated") })
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "invariant violated")
+}
@@ -0,0 +1,82 @@
+// Package metrics exports Prometheus metrics about the entries written by a logger.
+//
+// It registers the following counters:
+//   - log_entries_total{level,service}: the number of entries logged per level, including
+//     those that could not be written.
+//   - log_write_errors_total{service}: the number of entries that could not be written.
+//
+// The counters are maintained through the logger's hooks, so they reflect the entries that
+// were handed to the outputs, after level filtering and sampling. The entries written
+// successfully are log_entries_total minus log_write_errors_total:
+//
+//	m, err := metrics.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	log, err := logger.New("myServiceName", m.Option())
+package metrics
+
+import (
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// serviceKey is the field that holds the service name set by logger.New.
+const serviceKey = "service"
+
+// Metrics holds the Prometheus counters maintained for a logger.
+type Metrics struct {
+	entries     *prometheus.CounterVec
+	writeErrors *prometheus.CounterVec
+}
+
+// New creates the logging counters and registers them with reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "log_entries_total",
+			Help: "Total number of log entries logged, by level, including those counted by log_write_errors_total.",
+		}, []string{"level", "service"}),
+		writeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "log_write_errors_total",
+			Help: "Total number of log entries that could not be written.",
+		}, []string{"service"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.entries, m.writeErrors} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Option returns a logger.Option that keeps the counters up to date for the configured logger.
+func (m *Metrics) Option() logger.Option {
+	return func(l *logger.Logger) {
+		logger.WithHook(m.countEntry)(l)
+		logger.WithWriteErrorHook(m.countWriteError)(l)
+	}
+}
+
+// countEntry is a logger.Hook incrementing log_entries_total.
+func (m *Metrics) countEntry(ent zapcore.Entry, fields []zapcore.Field) error {
+	m.entries.WithLabelValues(ent.Level.String(), service(fields)).Inc()
+	return nil
+}
+
+// countWriteError is a logger.WriteErrorHook incrementing log_write_errors_total.
+func (m *Metrics) countWriteError(_ zapcore.Entry, fields []zapcore.Field, _ error) {
+	m.writeErrors.WithLabelValues(service(fields)).Inc()
+}
+
+// service returns the service name carried by fields.
+func service(fields []zapcore.Field) string {
+	for _, f := range fields {
+		if f.Key == serviceKey && f.Type == zapcore.StringType {
+			return f.String
+		}
+	}
+	return ""
+}
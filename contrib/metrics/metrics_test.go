@@ -0,0 +1,72 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/metrics"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// sink is a zap.Sink that discards writes, or fails them when fail is set.
+type sink struct{ fail bool }
+
+// Write implements io.Writer.
+func (s *sink) Write(p []byte) (int, error) {
+	if s.fail {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+// Sync is a no-op.
+func (*sink) Sync() error { return nil }
+
+// Close is a no-op.
+func (*sink) Close() error { return nil }
+
+func TestMetrics(t *testing.T) {
+	out := &sink{}
+	require.NoError(t, zap.RegisterSink("metrics", func(*url.URL) (zap.Sink, error) { return out, nil }))
+
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	require.NoError(t, err)
+
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"metrics://"}), m.Option())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	l.Info(ctx, "one")
+	l.Info(ctx, "two")
+	l.Error(ctx, "three")
+	l.Debug(ctx, "filtered by level")
+	out.fail = true
+	l.Error(ctx, "lost")
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP log_entries_total Total number of log entries logged, by level, including those counted by log_write_errors_total.
+# TYPE log_entries_total counter
+log_entries_total{level="error",service="checkout"} 2
+log_entries_total{level="info",service="checkout"} 2
+# HELP log_write_errors_total Total number of log entries that could not be written.
+# TYPE log_write_errors_total counter
+log_write_errors_total{service="checkout"} 1
+`)))
+}
+
+func TestNewRejectsDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := metrics.New(reg)
+	require.NoError(t, err)
+
+	_, err = metrics.New(reg)
+	require.Error(t, err, "registering the counters twice should fail")
+}
@@ -0,0 +1,102 @@
+// Package nats provides connection handlers for NATS clients logging through a Logger:
+//
+//	nc, err := gonats.Connect(url, nats.Options(log)...)
+//
+// Entries carry the metadata of the connection: the client name as nats_client, the redacted
+// URL of the server as nats_url, the name of the server as nats_server and the status of the
+// connection as nats_status, omitting empty ones. NATS passes no context, so the entries carry
+// no trace ID.
+package nats
+
+import (
+	"context"
+	"errors"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	gonats "github.com/nats-io/nats.go"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options returns the options setting all the handlers of this package.
+func Options(l *logger.Logger) []gonats.Option {
+	return []gonats.Option{
+		gonats.ErrorHandler(ErrorHandler(l)),
+		gonats.DisconnectErrHandler(DisconnectErrHandler(l)),
+		gonats.ReconnectHandler(ReconnectHandler(l)),
+		gonats.ClosedHandler(ClosedHandler(l)),
+	}
+}
+
+// ErrorHandler returns a handler logging asynchronous errors, such as slow consumers and
+// permission violations, at ErrorLevel with the message "nats async error", the subject and
+// queue group of the subscription, if any, and the error. Slow consumer errors also carry the
+// number of pending and dropped messages of the subscription.
+func ErrorHandler(l *logger.Logger) gonats.ErrHandler {
+	return func(nc *gonats.Conn, sub *gonats.Subscription, err error) {
+		keyVals := connFields(nc)
+		if sub != nil {
+			keyVals = append(keyVals, "subject", sub.Subject)
+			if sub.Queue != "" {
+				keyVals = append(keyVals, "queue", sub.Queue)
+			}
+			if errors.Is(err, gonats.ErrSlowConsumer) {
+				if pending, _, pendingErr := sub.Pending(); pendingErr == nil {
+					keyVals = append(keyVals, "pending_msgs", pending)
+				}
+				if dropped, droppedErr := sub.Dropped(); droppedErr == nil {
+					keyVals = append(keyVals, "dropped_msgs", dropped)
+				}
+			}
+		}
+		keyVals = append(keyVals, "error", err)
+		l.Log(context.Background(), zapcore.ErrorLevel, "nats async error", keyVals...)
+	}
+}
+
+// DisconnectErrHandler returns a handler logging disconnections at WarnLevel with the message
+// "nats disconnected" and the error that caused it, if any.
+func DisconnectErrHandler(l *logger.Logger) gonats.ConnErrHandler {
+	return func(nc *gonats.Conn, err error) {
+		keyVals := connFields(nc)
+		if err != nil {
+			keyVals = append(keyVals, "error", err)
+		}
+		l.Log(context.Background(), zapcore.WarnLevel, "nats disconnected", keyVals...)
+	}
+}
+
+// ReconnectHandler returns a handler logging reconnections at InfoLevel with the message
+// "nats reconnected" and the number of reconnections of the connection.
+func ReconnectHandler(l *logger.Logger) gonats.ConnHandler {
+	return func(nc *gonats.Conn) {
+		keyVals := append(connFields(nc), "reconnects", nc.Stats().Reconnects)
+		l.Log(context.Background(), zapcore.InfoLevel, "nats reconnected", keyVals...)
+	}
+}
+
+// ClosedHandler returns a handler logging the closing of the connection at InfoLevel with the
+// message "nats connection closed" and the last error of the connection, if any.
+func ClosedHandler(l *logger.Logger) gonats.ConnHandler {
+	return func(nc *gonats.Conn) {
+		keyVals := connFields(nc)
+		if err := nc.LastError(); err != nil {
+			keyVals = append(keyVals, "error", err)
+		}
+		l.Log(context.Background(), zapcore.InfoLevel, "nats connection closed", keyVals...)
+	}
+}
+
+// connFields returns the metadata of nc as fields.
+func connFields(nc *gonats.Conn) []interface{} {
+	keyVals := make([]interface{}, 0, 12)
+	if nc.Opts.Name != "" {
+		keyVals = append(keyVals, "nats_client", nc.Opts.Name)
+	}
+	if url := nc.ConnectedUrlRedacted(); url != "" {
+		keyVals = append(keyVals, "nats_url", url)
+	}
+	if server := nc.ConnectedServerName(); server != "" {
+		keyVals = append(keyVals, "nats_server", server)
+	}
+	return append(keyVals, "nats_status", nc.Status().String())
+}
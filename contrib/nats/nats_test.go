@@ -0,0 +1,48 @@
+package nats_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/nats"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	gonats "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHandlers(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	nc := &gonats.Conn{Opts: gonats.Options{Name: "orders-service"}}
+
+	nats.ErrorHandler(l)(nc, &gonats.Subscription{Subject: "orders.created", Queue: "workers"}, gonats.ErrSlowConsumer)
+	nats.DisconnectErrHandler(l)(nc, errors.New("read: connection reset by peer"))
+	nats.DisconnectErrHandler(l)(nc, nil)
+	nats.ReconnectHandler(l)(nc)
+	nats.ClosedHandler(l)(nc)
+
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "nats async error", "nats_client", "orders-service",
+		"subject", "orders.created", "queue", "workers", "error", gonats.ErrSlowConsumer.Error())
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "nats disconnected",
+		"nats_status", "DISCONNECTED", "error", "read: connection reset by peer")
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "nats reconnected", "reconnects", uint64(0))
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "nats connection closed", "nats_client", "orders-service")
+
+	disconnects := entries.FilterMessage("nats disconnected").All()
+	require.Len(t, disconnects, 2)
+	require.NotContains(t, disconnects[1].ContextMap(), "error")
+	require.NotContains(t, disconnects[1].ContextMap(), "nats_url", "a disconnected connection has no URL")
+}
+
+func TestOptions(t *testing.T) {
+	l, _ := loggertest.NewTestLogger(t)
+	opts := gonats.GetDefaultOptions()
+	for _, opt := range nats.Options(l) {
+		require.NoError(t, opt(&opts))
+	}
+
+	require.NotNil(t, opts.AsyncErrorCB)
+	require.NotNil(t, opts.DisconnectedErrCB)
+	require.NotNil(t, opts.ReconnectedCB)
+	require.NotNil(t, opts.ClosedCB)
+}
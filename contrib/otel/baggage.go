@@ -0,0 +1,31 @@
+package otel
+
+import (
+	"context"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// WithBaggageFields adds the named members of the OpenTelemetry baggage carried by the context
+// to every entry, as fields keyed by the member name, so that cross-cutting metadata such as
+// the tenant or a feature flag propagates into the logs:
+//
+//	log, err := logger.New("myServiceName", otel.WithBaggageFields("tenant", "feature_flag"))
+//
+// Members missing from the baggage are omitted.
+func WithBaggageFields(members ...string) logger.Option {
+	return logger.WithContextFields(func(ctx context.Context) []interface{} {
+		b := baggage.FromContext(ctx)
+		if b.Len() == 0 {
+			return nil
+		}
+		var keyVals []interface{}
+		for _, name := range members {
+			if m := b.Member(name); m.Key() != "" {
+				keyVals = append(keyVals, name, m.Value())
+			}
+		}
+		return keyVals
+	})
+}
@@ -0,0 +1,36 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/otel"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithBaggageFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	l, err := logger.New("checkout",
+		otel.WithBaggageFields("tenant", "feature_flag"),
+		logger.WithCore(core),
+		logger.WithOutputPaths(nil),
+	)
+	require.NoError(t, err)
+
+	b, err := baggage.Parse("tenant=acme,region=eu")
+	require.NoError(t, err)
+	l.Info(baggage.ContextWithBaggage(context.Background(), b), "order placed")
+	l.Info(context.Background(), "without baggage")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	fields := entries[0].ContextMap()
+	require.Equal(t, "acme", fields["tenant"])
+	require.NotContains(t, fields, "feature_flag")
+	require.NotContains(t, fields, "region")
+	require.NotContains(t, entries[1].ContextMap(), "tenant")
+}
@@ -0,0 +1,116 @@
+// Package otel connects the logger to OpenTelemetry tracing and context propagation.
+//
+// WithSpanEvents mirrors the entries logged with a context carrying a recording span as
+// events of that span, so that they can be inspected next to the trace:
+//
+//	log, err := logger.New("myServiceName", otel.WithSpanEvents(zapcore.InfoLevel))
+//
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	defer span.End()
+//	log.Info(ctx, "payment authorized", "amount", 42) // Also added to span.
+//
+// WithBaggageFields adds members of the OpenTelemetry baggage to every entry.
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// spanEventsClearance is the most sensitive privacy level of the entries added to spans,
+	// which are exported to the tracing backend.
+	spanEventsClearance = logger.Internal
+
+	// levelKey is the attribute holding the level of a span event.
+	levelKey = "log.severity"
+
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// WithSpanEvents adds the entries at or above level that are logged with a context carrying a
+// recording span as events of the span. The event is named after the entry's message and
+// carries its level and fields as attributes; errors are also recorded through
+// span.RecordError. Entries logged without such a context are only written, and so are the
+// entries marked logger.Restricted through logger.Privacy, like for a sink with Internal
+// clearance.
+func WithSpanEvents(level zapcore.Level) logger.Option {
+	return logger.WithContextHook(func(ctx context.Context, ent zapcore.Entry, fields []zapcore.Field) error {
+		if ent.Level < level || logger.PrivacyOf(fields) > spanEventsClearance {
+			return nil
+		}
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return nil
+		}
+
+		enc := zapcore.NewMapObjectEncoder()
+		var errs []error
+		for _, f := range fields {
+			if f.Type == zapcore.ErrorType {
+				if err, ok := f.Interface.(error); ok && err != nil {
+					errs = append(errs, err)
+				}
+			}
+			f.AddTo(enc)
+		}
+		attrs := make([]attribute.KeyValue, 0, len(enc.Fields)+1)
+		attrs = append(attrs, attribute.String(levelKey, ent.Level.String()))
+		for key, value := range enc.Fields {
+			switch key {
+			case traceIDKey, spanIDKey:
+				// The span carries them already.
+				continue
+			}
+			attrs = append(attrs, attributeOf(key, value))
+		}
+
+		span.AddEvent(ent.Message, trace.WithTimestamp(ent.Time), trace.WithAttributes(attrs...))
+		for _, err := range errs {
+			span.RecordError(err, trace.WithTimestamp(ent.Time))
+		}
+		return nil
+	})
+}
+
+// attributeOf converts a field value, as encoded by zapcore.MapObjectEncoder, into an attribute.
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case []string:
+		return attribute.StringSlice(key, v)
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return attribute.String(key, string(data))
+		}
+	}
+	return attribute.String(key, fmt.Sprint(value))
+}
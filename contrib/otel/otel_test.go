@@ -0,0 +1,91 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/otel"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTracer returns a tracer whose ended spans are recorded.
+func newTracer(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, recorder
+}
+
+// attributes returns the attributes of a span event.
+func attributes(kvs []attribute.KeyValue) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		attrs[string(kv.Key)] = kv.Value
+	}
+	return attrs
+}
+
+func TestWithSpanEvents(t *testing.T) {
+	provider, recorder := newTracer(t)
+	l, err := logger.New("checkout", otel.WithSpanEvents(zapcore.InfoLevel), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	l.Info(ctx, "payment authorized", "amount", 42, "card", "visa")
+	l.Error(ctx, "receipt failed", "err", errors.New("smtp down"))
+	l.Debug(ctx, "below the level")
+	l.Info(context.Background(), "without a span")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 3)
+
+	require.Equal(t, "payment authorized", events[0].Name)
+	attrs := attributes(events[0].Attributes)
+	require.Equal(t, "info", attrs["log.severity"].AsString())
+	require.Equal(t, int64(42), attrs["amount"].AsInt64())
+	require.Equal(t, "visa", attrs["card"].AsString())
+	require.Equal(t, "checkout", attrs["service"].AsString())
+
+	require.Equal(t, "receipt failed", events[1].Name)
+	require.Equal(t, "smtp down", attributes(events[1].Attributes)["err"].AsString())
+	require.Equal(t, "exception", events[2].Name)
+}
+
+func TestWithSpanEventsNonRecordingSpan(t *testing.T) {
+	provider, recorder := newTracer(t)
+	l, err := logger.New("checkout", otel.WithSpanEvents(zapcore.InfoLevel), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	span.End()
+	l.Info(ctx, "after the span ended")
+
+	require.Empty(t, recorder.Ended()[0].Events())
+}
+
+func TestWithSpanEventsPrivacy(t *testing.T) {
+	provider, recorder := newTracer(t)
+	l, err := logger.New("checkout", otel.WithSpanEvents(zapcore.InfoLevel), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	l.Info(ctx, "card holder verified", "holder", "Jane Doe", logger.Privacy(logger.Restricted))
+	l.With(logger.Privacy(logger.Restricted)).Info(ctx, "restricted child")
+	l.Info(ctx, "internal", logger.Privacy(logger.Internal))
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	require.Len(t, events, 1, "restricted entries should not become span events")
+	require.Equal(t, "internal", events[0].Name)
+}
@@ -0,0 +1,371 @@
+// Package otlp emits every entry as an OpenTelemetry LogRecord over OTLP, so that logs can flow
+// through an OpenTelemetry Collector pipeline without changing call sites.
+//
+// The Core returned by New is added to a logger with logger.WithCore. Every entry becomes a
+// LogRecord carrying the level as its severity, the message as its body, the fields as
+// attributes, and the trace_id, span_id and trace_sampled fields as its trace context; records
+// are only flagged as sampled when trace_sampled, as added by logger.WithTraceContext, is true.
+// Records are exported in batches over gRPC or HTTP; syncing the logger flushes them:
+//
+//	core, err := otlp.New(ctx, otlp.Config{Protocol: otlp.HTTP, Endpoint: "collector:4318", Insecure: true})
+//	if err != nil {
+//		return err
+//	}
+//	defer core.Shutdown(ctx)
+//	log, err := logger.New("myServiceName", logger.WithCore(core))
+//
+// Pass logger.WithOutputPaths(nil) to only export through OTLP.
+package otlp
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// scopeName is the instrumentation scope of the emitted records.
+	scopeName = "github.com/janduursma/zap-logger-wrapper/contrib/otlp"
+	// defaultSyncTimeout is used when Config.SyncTimeout is not set.
+	defaultSyncTimeout = 5 * time.Second
+
+	traceIDKey      = "trace_id"
+	spanIDKey       = "span_id"
+	traceSampledKey = "trace_sampled"
+	serviceKey      = "service"
+)
+
+// Protocol selects the OTLP transport.
+type Protocol string
+
+const (
+	// GRPC exports over OTLP/gRPC, by default to localhost:4317. This is the default.
+	GRPC Protocol = "grpc"
+	// HTTP exports over OTLP/HTTP with protobuf payloads, by default to localhost:4318.
+	HTTP Protocol = "http"
+)
+
+// Config configures the OTLP exporter.
+type Config struct {
+	// Protocol is the OTLP transport, GRPC by default.
+	Protocol Protocol
+	// Endpoint is the host and port of the collector. The OTEL_EXPORTER_OTLP_ENDPOINT and
+	// OTEL_EXPORTER_OTLP_LOGS_ENDPOINT environment variables are used when it is empty.
+	Endpoint string
+	// Insecure disables TLS.
+	Insecure bool
+	// Headers are sent with every export, e.g. for authentication.
+	Headers map[string]string
+	// Resource describes the service emitting the records. When nil, the resource is built
+	// from the environment, such as OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES.
+	Resource *resource.Resource
+	// Level is the minimum level exported, InfoLevel by default.
+	Level zapcore.Level
+	// SyncTimeout bounds how long Sync waits for buffered records. It defaults to five seconds.
+	SyncTimeout time.Duration
+}
+
+// Core is a zapcore.Core emitting entries as OpenTelemetry LogRecords.
+type Core struct {
+	logger      log.Logger
+	provider    log.LoggerProvider
+	level       zapcore.Level
+	syncTimeout time.Duration
+	fields      []zapcore.Field
+}
+
+// New creates a Core exporting records over OTLP through a new LoggerProvider with a batch
+// processor. Shutdown flushes the records and stops the exporter.
+func New(ctx context.Context, cfg Config) (*Core, error) {
+	var (
+		exporter sdklog.Exporter
+		err      error
+	)
+	switch cfg.Protocol {
+	case "", GRPC:
+		var opts []otlploggrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploggrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+		}
+		exporter, err = otlploggrpc.New(ctx, opts...)
+	case HTTP:
+		var opts []otlploghttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+		}
+		exporter, err = otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otlp: unknown protocol %q", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("otlp: %w", err)
+	}
+
+	opts := []sdklog.LoggerProviderOption{sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter))}
+	if cfg.Resource != nil {
+		opts = append(opts, sdklog.WithResource(cfg.Resource))
+	}
+	return NewWithProvider(sdklog.NewLoggerProvider(opts...), cfg), nil
+}
+
+// NewWithProvider creates a Core emitting records through an existing LoggerProvider, for
+// example one shared with other instrumentation. The Protocol, Endpoint, Insecure, Headers and
+// Resource settings of cfg are ignored.
+func NewWithProvider(provider log.LoggerProvider, cfg Config) *Core {
+	timeout := cfg.SyncTimeout
+	if timeout <= 0 {
+		timeout = defaultSyncTimeout
+	}
+	return &Core{
+		logger:      provider.Logger(scopeName),
+		provider:    provider,
+		level:       cfg.Level,
+		syncTimeout: timeout,
+	}
+}
+
+// Enabled implements zapcore.Core.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return level >= c.level
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx, record := c.record(ent, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+	c.logger.Emit(ctx, record)
+	if ent.Level > zapcore.ErrorLevel {
+		// Panic and Fatal entries end the goroutine or process, so flush right away.
+		return c.Sync()
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core, flushing the buffered records when the LoggerProvider
+// supports it.
+func (c *Core) Sync() error {
+	p, ok := c.provider.(interface{ ForceFlush(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.syncTimeout)
+	defer cancel()
+	if err := p.ForceFlush(ctx); err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	return nil
+}
+
+// Shutdown flushes the buffered records and shuts the LoggerProvider down, when it supports
+// it. The Core must not be written to afterwards.
+func (c *Core) Shutdown(ctx context.Context) error {
+	p, ok := c.provider.(interface{ Shutdown(context.Context) error })
+	if !ok {
+		return nil
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	return nil
+}
+
+// record converts an entry into a LogRecord, and returns the context carrying its trace context.
+func (c *Core) record(ent zapcore.Entry, fields []zapcore.Field) (context.Context, log.Record) {
+	var r log.Record
+	r.SetTimestamp(ent.Time)
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(severity(ent.Level))
+	r.SetSeverityText(ent.Level.CapitalString())
+	r.SetBody(log.StringValue(ent.Message))
+
+	enc := zapcore.NewMapObjectEncoder()
+	var errs []error
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok && err != nil {
+				errs = append(errs, err)
+			}
+		}
+		f.AddTo(enc)
+	}
+
+	var sc trace.SpanContextConfig
+	for key, value := range enc.Fields {
+		switch key {
+		case traceIDKey:
+			if id, ok := parseID(value, 16); ok {
+				copy(sc.TraceID[:], id)
+				continue
+			}
+		case traceSampledKey:
+			if sampled, ok := value.(bool); ok {
+				if sampled {
+					sc.TraceFlags = trace.FlagsSampled
+				}
+				continue
+			}
+		case spanIDKey:
+			if id, ok := parseID(value, 8); ok {
+				copy(sc.SpanID[:], id)
+				continue
+			}
+		case serviceKey:
+			key = "service.name"
+		}
+		r.AddAttributes(log.KeyValue{Key: key, Value: value2log(value)})
+	}
+
+	if ent.LoggerName != "" {
+		r.AddAttributes(log.String("logger.name", ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		r.AddAttributes(
+			log.String("code.file.path", ent.Caller.File),
+			log.Int("code.line.number", ent.Caller.Line),
+		)
+		if ent.Caller.Function != "" {
+			r.AddAttributes(log.String("code.function.name", ent.Caller.Function))
+		}
+	}
+	if len(errs) > 0 {
+		r.AddAttributes(
+			log.String("exception.message", errs[0].Error()),
+			log.String("exception.type", fmt.Sprintf("%T", errs[0])),
+		)
+	}
+	if ent.Stack != "" {
+		r.AddAttributes(log.String("exception.stacktrace", ent.Stack))
+	}
+
+	ctx := context.Background()
+	if sc.TraceID.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(sc))
+	}
+	return ctx, r
+}
+
+// parseID decodes a hex trace or span ID field of n bytes.
+func parseID(value interface{}, n int) ([]byte, bool) {
+	s, ok := value.(string)
+	if !ok || len(s) != 2*n {
+		return nil, false
+	}
+	id, err := hex.DecodeString(s)
+	return id, err == nil
+}
+
+// severity maps a zap level to the corresponding OpenTelemetry severity.
+func severity(l zapcore.Level) log.Severity {
+	switch l {
+	case zapcore.DebugLevel:
+		return log.SeverityDebug
+	case zapcore.InfoLevel:
+		return log.SeverityInfo
+	case zapcore.WarnLevel:
+		return log.SeverityWarn
+	case zapcore.ErrorLevel:
+		return log.SeverityError
+	case zapcore.DPanicLevel:
+		return log.SeverityFatal
+	case zapcore.PanicLevel:
+		return log.SeverityFatal2
+	case zapcore.FatalLevel:
+		return log.SeverityFatal3
+	default:
+		return log.SeverityUndefined
+	}
+}
+
+// value2log converts a field value, as encoded by zapcore.MapObjectEncoder, into a log.Value.
+func value2log(value interface{}) log.Value {
+	switch v := value.(type) {
+	case nil:
+		return log.Value{}
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case []byte:
+		return log.BytesValue(v)
+	case float64:
+		return log.Float64Value(v)
+	case float32:
+		return log.Float64Value(float64(v))
+	case time.Time:
+		return log.StringValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return log.StringValue(v.String())
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	case error:
+		return log.StringValue(v.Error())
+	case map[string]interface{}:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for k, e := range v {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: value2log(e)})
+		}
+		return log.MapValue(kvs...)
+	case []interface{}:
+		vals := make([]log.Value, len(v))
+		for i, e := range v {
+			vals[i] = value2log(e)
+		}
+		return log.SliceValue(vals...)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return log.Int64Value(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return log.Int64Value(int64(u))
+		}
+		return log.StringValue(fmt.Sprint(value))
+	case reflect.Slice, reflect.Array:
+		vals := make([]log.Value, rv.Len())
+		for i := range vals {
+			vals[i] = value2log(rv.Index(i).Interface())
+		}
+		return log.SliceValue(vals...)
+	default:
+		return log.StringValue(fmt.Sprint(value))
+	}
+}
@@ -0,0 +1,166 @@
+package otlp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/otlp"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// exporter is an sdklog.Exporter recording exported records.
+type exporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *exporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *exporter) Shutdown(context.Context) error { return nil }
+
+func (e *exporter) ForceFlush(context.Context) error { return nil }
+
+// newLogger creates a logger emitting records to a recording exporter.
+func newLogger(t *testing.T, cfg otlp.Config) (*logger.Logger, *exporter) {
+	t.Helper()
+
+	exp := &exporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	core := otlp.NewWithProvider(provider, cfg)
+	t.Cleanup(func() { _ = core.Shutdown(context.Background()) })
+
+	traceFn := func(context.Context) string { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+	l, err := logger.New("checkout", logger.WithCore(core), logger.WithTraceID(traceFn), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+	return l, exp
+}
+
+// attributes returns the attributes of a record.
+func attributes(r sdklog.Record) map[string]log.Value {
+	attrs := make(map[string]log.Value)
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestEmitsRecords(t *testing.T) {
+	l, exp := newLogger(t, otlp.Config{})
+
+	l.Error(context.Background(), "payment failed", "order", 42, "paid", false, "err", errors.New("card declined"))
+	require.NoError(t, l.Sync())
+
+	require.Len(t, exp.records, 1)
+	r := exp.records[0]
+	require.Equal(t, log.SeverityError, r.Severity())
+	require.Equal(t, "ERROR", r.SeverityText())
+	require.Equal(t, "payment failed", r.Body().AsString())
+	require.WithinDuration(t, time.Now(), r.Timestamp(), time.Minute)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", r.TraceID().String())
+
+	attrs := attributes(r)
+	require.Equal(t, int64(42), attrs["order"].AsInt64())
+	require.False(t, attrs["paid"].AsBool())
+	require.Equal(t, "checkout", attrs["service.name"].AsString())
+	require.Equal(t, "card declined", attrs["exception.message"].AsString())
+	require.Equal(t, "*errors.errorString", attrs["exception.type"].AsString())
+	require.Contains(t, attrs["code.file.path"].AsString(), "otlp_test.go")
+	require.NotContains(t, attrs, "trace_id")
+	require.False(t, r.TraceFlags().IsSampled(), "entries without trace_sampled should not be flagged as sampled")
+}
+
+// sampledKey is the context key of the sampling decision returned by traceContext.
+type sampledKey struct{}
+
+// traceContext is a logger.GetTraceContextFn returning a fixed trace and the sampling
+// decision stored under sampledKey.
+func traceContext(ctx context.Context) (string, string, bool) {
+	sampled, _ := ctx.Value(sampledKey{}).(bool)
+	return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", sampled
+}
+
+func TestTraceContext(t *testing.T) {
+	exp := &exporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	core := otlp.NewWithProvider(provider, otlp.Config{})
+	t.Cleanup(func() { _ = core.Shutdown(context.Background()) })
+	l, err := logger.New("checkout", logger.WithCore(core), logger.WithTraceContext(traceContext), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+
+	l.Info(context.WithValue(context.Background(), sampledKey{}, true), "sampled")
+	l.Info(context.Background(), "not sampled")
+	require.NoError(t, l.Sync())
+
+	require.Len(t, exp.records, 2)
+	require.Equal(t, "00f067aa0ba902b7", exp.records[0].SpanID().String())
+	require.True(t, exp.records[0].TraceFlags().IsSampled())
+	require.False(t, exp.records[1].TraceFlags().IsSampled())
+	require.NotContains(t, attributes(exp.records[0]), "trace_sampled")
+}
+
+func TestLevel(t *testing.T) {
+	l, exp := newLogger(t, otlp.Config{Level: zapcore.ErrorLevel})
+
+	l.Info(context.Background(), "order placed")
+	l.Error(context.Background(), "payment failed")
+
+	require.Len(t, exp.records, 1)
+	require.Equal(t, "payment failed", exp.records[0].Body().AsString())
+}
+
+func TestExportsOverHTTP(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		select {
+		case received <- r.URL.Path + " " + string(body):
+		default:
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	core, err := otlp.New(context.Background(), otlp.Config{
+		Protocol: otlp.HTTP,
+		Endpoint: strings.TrimPrefix(srv.URL, "http://"),
+		Insecure: true,
+	})
+	require.NoError(t, err)
+	l, err := logger.New("checkout", logger.WithCore(core), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.NoError(t, core.Shutdown(context.Background()))
+
+	select {
+	case req := <-received:
+		require.True(t, strings.HasPrefix(req, "/v1/logs "), req)
+		require.Contains(t, req, "order placed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("records not exported")
+	}
+}
+
+func TestUnknownProtocol(t *testing.T) {
+	_, err := otlp.New(context.Background(), otlp.Config{Protocol: "carrier-pigeon"})
+	require.ErrorContains(t, err, `unknown protocol "carrier-pigeon"`)
+}
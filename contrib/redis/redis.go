@@ -0,0 +1,216 @@
+// Package redis logs the commands of go-redis clients through a Logger.
+//
+// The hook returned by NewHook is added to a client with AddHook. Every command is logged with
+// the message "redis command", its name, the prefix of its key, its duration_ms and its error,
+// through the context passed to the command, so entries carry its trace ID. Pipelines are
+// logged as one "redis pipeline" entry, and failed dials as "redis dial failed":
+//
+//	rdb := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(redis.NewHook(log, redis.WithSlowThreshold(50*time.Millisecond)))
+//
+// Neither keys nor values are logged, since they may hold personal data: only the part of the
+// first key up to its first colon, e.g. "user" for "user:42", is logged as key_prefix.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap/zapcore"
+)
+
+// config holds the settings of the hook.
+type config struct {
+	level         zapcore.Level
+	errorLevel    zapcore.Level
+	slowThreshold time.Duration
+	sampleRate    float64
+}
+
+// Option defines a functional option for configuring the hook.
+type Option func(cfg *config)
+
+// WithLevel sets the level at which commands are logged. It defaults to DebugLevel.
+func WithLevel(level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.level = level
+	}
+}
+
+// WithErrorLevel sets the level at which failed commands and dials are logged. It defaults to
+// ErrorLevel.
+func WithErrorLevel(level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.errorLevel = level
+	}
+}
+
+// WithSlowThreshold logs only the commands that took at least d, and those that failed.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.slowThreshold = d
+	}
+}
+
+// WithSampleRate logs only a fraction of the successful commands, between 0 and 1. Failed
+// commands are always logged.
+func WithSampleRate(rate float64) Option {
+	return func(cfg *config) {
+		cfg.sampleRate = rate
+	}
+}
+
+// Hook is a goredis.Hook logging commands.
+type Hook struct {
+	l   *logger.Logger
+	cfg config
+}
+
+// NewHook returns a hook logging the commands of the clients it is added to through l.
+func NewHook(l *logger.Logger, opts ...Option) *Hook {
+	cfg := config{level: zapcore.DebugLevel, errorLevel: zapcore.ErrorLevel, sampleRate: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Hook{l: l, cfg: cfg}
+}
+
+// DialHook implements goredis.Hook, logging failed dials.
+func (h *Hook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			h.l.Log(ctx, h.cfg.errorLevel, "redis dial failed", "addr", addr, "error", err)
+		}
+		return conn, err
+	}
+}
+
+// ProcessHook implements goredis.Hook, logging commands.
+func (h *Hook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		elapsed := time.Since(start)
+		failed := commandError(err)
+		if !h.logged(elapsed, failed) {
+			return err
+		}
+
+		keyVals := []interface{}{"command", cmd.FullName()}
+		if prefix := keyPrefix(cmd); prefix != "" {
+			keyVals = append(keyVals, "key_prefix", prefix)
+		}
+		h.log(ctx, "redis command", elapsed, failed, keyVals)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements goredis.Hook, logging pipelines as a single entry with the
+// names of their commands and the first error.
+func (h *Hook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		elapsed := time.Since(start)
+		failed := commandError(err)
+		for _, cmd := range cmds {
+			if failed != nil {
+				break
+			}
+			failed = commandError(cmd.Err())
+		}
+		if !h.logged(elapsed, failed) {
+			return err
+		}
+
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.FullName()
+		}
+		h.log(ctx, "redis pipeline", elapsed, failed, []interface{}{"commands", names})
+		return err
+	}
+}
+
+// logged reports whether a command that took elapsed and failed with err is logged.
+func (h *Hook) logged(elapsed time.Duration, err error) bool {
+	if err != nil {
+		return true
+	}
+	if elapsed < h.cfg.slowThreshold {
+		return false
+	}
+	return h.cfg.sampleRate >= 1 || rand.Float64() < h.cfg.sampleRate
+}
+
+// log writes the entry of a command or pipeline.
+func (h *Hook) log(ctx context.Context, msg string, elapsed time.Duration, err error, keyVals []interface{}) {
+	level := h.cfg.level
+	keyVals = append(keyVals, "duration_ms", float64(elapsed.Microseconds())/1000)
+	if err != nil {
+		level = h.cfg.errorLevel
+		keyVals = append(keyVals, "error", err)
+	}
+	h.l.Log(ctx, level, msg, keyVals...)
+}
+
+// commandError returns err, unless it reports a missing key, which is not a failure.
+func commandError(err error) error {
+	if errors.Is(err, goredis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// keylessCommands are the commands without keys.
+var keylessCommands = map[string]bool{
+	"auth": true, "client": true, "cluster": true, "command": true, "config": true, "dbsize": true,
+	"echo": true, "flushall": true, "flushdb": true, "hello": true, "info": true, "keys": true,
+	"memory": true, "ping": true, "publish": true, "psubscribe": true, "punsubscribe": true,
+	"quit": true, "scan": true, "script": true, "select": true, "slowlog": true, "subscribe": true,
+	"time": true, "unsubscribe": true,
+}
+
+// keyPrefix returns the part of the command's first key up to its first colon, or an empty
+// string if the command has no key or the key has no colon.
+func keyPrefix(cmd goredis.Cmder) string {
+	name := cmd.Name()
+	if keylessCommands[name] {
+		return ""
+	}
+	args := cmd.Args()
+	pos := 1
+	switch name {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		// The script or function and the number of keys precede the keys; without keys, the
+		// arguments follow.
+		if len(args) <= 2 {
+			return ""
+		}
+		if n, err := strconv.Atoi(fmt.Sprint(args[2])); err != nil || n <= 0 {
+			return ""
+		}
+		pos = 3
+	}
+	if len(args) <= pos {
+		return ""
+	}
+	key, ok := args[pos].(string)
+	if !ok {
+		return ""
+	}
+	prefix, _, found := strings.Cut(key, ":")
+	if !found {
+		return ""
+	}
+	return prefix
+}
@@ -0,0 +1,109 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/redis"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// succeed is a goredis.ProcessHook completing every command.
+func succeed(_ context.Context, _ goredis.Cmder) error {
+	return nil
+}
+
+func TestProcessHook(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	process := redis.NewHook(l).ProcessHook(succeed)
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+
+	require.NoError(t, process(ctx, goredis.NewStringCmd(ctx, "get", "user:42")))
+	require.NoError(t, process(ctx, goredis.NewStatusCmd(ctx, "ping")))
+
+	commands := entries.FilterMessage("redis command").All()
+	require.Len(t, commands, 2)
+	get := commands[0].ContextMap()
+	require.Equal(t, zapcore.DebugLevel, commands[0].Level)
+	require.Equal(t, "get", get["command"])
+	require.Equal(t, "user", get["key_prefix"])
+	require.Equal(t, "req-1", get["request_id"])
+	require.Contains(t, get, "duration_ms")
+	require.NotContains(t, commands[1].ContextMap(), "key_prefix")
+}
+
+func TestProcessHookScripts(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	process := redis.NewHook(l).ProcessHook(succeed)
+	ctx := context.Background()
+
+	require.NoError(t, process(ctx, goredis.NewCmd(ctx, "eval", "return 1", 1, "lock:orders", "token:secret")))
+	require.NoError(t, process(ctx, goredis.NewCmd(ctx, "evalsha", "abc123", 0, "user:42")))
+	require.NoError(t, process(ctx, goredis.NewCmd(ctx, "fcall", "release", "0")))
+
+	commands := entries.FilterMessage("redis command").All()
+	require.Len(t, commands, 3)
+	require.Equal(t, "lock", commands[0].ContextMap()["key_prefix"])
+	require.NotContains(t, commands[1].ContextMap(), "key_prefix", "the arguments of a script without keys are not keys")
+	require.NotContains(t, commands[2].ContextMap(), "key_prefix")
+}
+
+func TestProcessHookErrors(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	hook := redis.NewHook(l, redis.WithSlowThreshold(time.Hour))
+	ctx := context.Background()
+
+	missing := hook.ProcessHook(func(_ context.Context, _ goredis.Cmder) error { return goredis.Nil })
+	require.ErrorIs(t, missing(ctx, goredis.NewStringCmd(ctx, "get", "session:1")), goredis.Nil)
+	require.Zero(t, entries.Len(), "missing keys should not be logged as failures")
+
+	failing := hook.ProcessHook(func(_ context.Context, _ goredis.Cmder) error { return errors.New("READONLY") })
+	require.Error(t, failing(ctx, goredis.NewStatusCmd(ctx, "set", "session:1", "x")))
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "redis command",
+		"command", "set", "key_prefix", "session", "error", "READONLY")
+}
+
+func TestProcessHookSampling(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	process := redis.NewHook(l, redis.WithSampleRate(0)).ProcessHook(succeed)
+	ctx := context.Background()
+
+	for range 10 {
+		require.NoError(t, process(ctx, goredis.NewStringCmd(ctx, "get", "user:42")))
+	}
+	require.Zero(t, entries.Len())
+}
+
+func TestProcessPipelineHook(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	process := redis.NewHook(l, redis.WithLevel(zapcore.InfoLevel)).ProcessPipelineHook(
+		func(_ context.Context, cmds []goredis.Cmder) error {
+			cmds[1].SetErr(errors.New("WRONGTYPE"))
+			return nil
+		})
+	ctx := context.Background()
+
+	cmds := []goredis.Cmder{goredis.NewStringCmd(ctx, "get", "user:1"), goredis.NewIntCmd(ctx, "incr", "user:2")}
+	require.NoError(t, process(ctx, cmds))
+
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "redis pipeline",
+		"commands", []interface{}{"get", "incr"}, "error", "WRONGTYPE")
+}
+
+func TestDialHook(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	dial := redis.NewHook(l).DialHook(func(_ context.Context, _, _ string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	_, err := dial(context.Background(), "tcp", "localhost:6379")
+	require.Error(t, err)
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "redis dial failed", "addr", "localhost:6379")
+}
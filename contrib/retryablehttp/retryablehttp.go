@@ -0,0 +1,69 @@
+// Package retryablehttp writes the logs of HashiCorp's go-retryablehttp clients through a
+// Logger, so that they respect its level and sinks.
+//
+// Install sets the client's Logger to a LeveledLogger and adds a request hook logging every
+// retry through the request's context, so that retries carry the trace ID of the request:
+//
+//	client := goretryablehttp.NewClient()
+//	retryablehttp.Install(client, log)
+//
+// The messages of the client itself, such as "request failed", are logged without a context,
+// since go-retryablehttp passes none.
+package retryablehttp
+
+import (
+	"context"
+	"net/http"
+
+	goretryablehttp "github.com/hashicorp/go-retryablehttp"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// LeveledLogger is a goretryablehttp.LeveledLogger writing through a Logger.
+type LeveledLogger struct {
+	l *logger.Logger
+}
+
+// New returns a goretryablehttp.LeveledLogger writing through l.
+func New(l *logger.Logger) *LeveledLogger {
+	return &LeveledLogger{l: l}
+}
+
+// Error implements goretryablehttp.LeveledLogger.
+func (r *LeveledLogger) Error(msg string, keysAndValues ...interface{}) {
+	r.l.LogDepth(context.Background(), 1, zapcore.ErrorLevel, msg, keysAndValues...)
+}
+
+// Warn implements goretryablehttp.LeveledLogger.
+func (r *LeveledLogger) Warn(msg string, keysAndValues ...interface{}) {
+	r.l.LogDepth(context.Background(), 1, zapcore.WarnLevel, msg, keysAndValues...)
+}
+
+// Info implements goretryablehttp.LeveledLogger.
+func (r *LeveledLogger) Info(msg string, keysAndValues ...interface{}) {
+	r.l.LogDepth(context.Background(), 1, zapcore.InfoLevel, msg, keysAndValues...)
+}
+
+// Debug implements goretryablehttp.LeveledLogger.
+func (r *LeveledLogger) Debug(msg string, keysAndValues ...interface{}) {
+	r.l.LogDepth(context.Background(), 1, zapcore.DebugLevel, msg, keysAndValues...)
+}
+
+// Install sets the Logger of client to a LeveledLogger writing through l, and adds a
+// RequestLogHook logging every retry at WarnLevel, with the message "retrying http request",
+// the method, URL and attempt, through the request's context. A RequestLogHook set before is
+// still called.
+func Install(client *goretryablehttp.Client, l *logger.Logger) {
+	client.Logger = New(l)
+	next := client.RequestLogHook
+	client.RequestLogHook = func(log goretryablehttp.Logger, req *http.Request, attempt int) {
+		if attempt > 0 {
+			l.Log(req.Context(), zapcore.WarnLevel, "retrying http request",
+				"method", req.Method, "url", req.URL.Redacted(), "attempt", attempt)
+		}
+		if next != nil {
+			next(log, req, attempt)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package retryablehttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goretryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/janduursma/zap-logger-wrapper/contrib/retryablehttp"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ goretryablehttp.LeveledLogger = (*retryablehttp.LeveledLogger)(nil)
+
+func TestInstall(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	l, entries := loggertest.NewTestLogger(t)
+	client := goretryablehttp.NewClient()
+	client.RetryWaitMin, client.RetryWaitMax = time.Millisecond, time.Millisecond
+	hooked := 0
+	client.RequestLogHook = func(_ goretryablehttp.Logger, _ *http.Request, _ int) { hooked++ }
+	retryablehttp.Install(client, l)
+
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+	req, err := goretryablehttp.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/orders", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Equal(t, 2, hooked, "the previous hook should still be called")
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "performing request", "method", "GET")
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "retrying request", "remaining", 4)
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "retrying http request",
+		"url", srv.URL+"/orders", "attempt", 1, "request_id", "req-1")
+	for _, e := range entries.FilterLevel(zapcore.DebugLevel).All() {
+		require.Regexp(t, `/go-retryablehttp@[^/]+/client\.go:\d+$`, e.Caller.String(), "the client should be the caller")
+	}
+}
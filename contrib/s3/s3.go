@@ -0,0 +1,53 @@
+// Package s3 provides a logger.BlobStore that offloads large log values to Amazon S3.
+//
+//	store := s3.New(awss3.NewFromConfig(cfg), "my-log-payloads", "payloads/")
+//	log, err := logger.New("myServiceName", logger.WithOffload(store, 64<<10))
+//
+// Offloaded values are replaced in the entry by an s3://bucket/key reference.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+)
+
+// PutObjectAPI is the part of *s3.Client used by Store.
+type PutObjectAPI interface {
+	PutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.Options)) (*awss3.PutObjectOutput, error)
+}
+
+// Store is a logger.BlobStore writing payloads as objects to an S3 bucket.
+type Store struct {
+	client PutObjectAPI
+	bucket string
+	prefix string
+}
+
+var _ logger.BlobStore = (*Store)(nil)
+
+// New returns a Store writing to bucket through client. Objects are named after the
+// payload's hash, below prefix.
+func New(client PutObjectAPI, bucket, prefix string) *Store {
+	return &Store{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Put implements logger.BlobStore. It returns an s3:// URL.
+func (s *Store) Put(ctx context.Context, name string, data []byte) (string, error) {
+	key := path.Join(s.prefix, name)
+	_, err := s.client.PutObject(ctx, &awss3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "s3", Host: s.bucket, Path: "/" + key}).String(), nil
+}
@@ -0,0 +1,50 @@
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/janduursma/zap-logger-wrapper/contrib/s3"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient records the objects it is asked to put.
+type fakeClient struct {
+	objects map[string]string
+	err     error
+}
+
+// PutObject implements s3.PutObjectAPI.
+func (c *fakeClient) PutObject(_ context.Context, in *awss3.PutObjectInput, _ ...func(*awss3.Options)) (*awss3.PutObjectOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.objects[aws.ToString(in.Bucket)+"/"+aws.ToString(in.Key)] = string(data)
+	return &awss3.PutObjectOutput{}, nil
+}
+
+func TestStorePut(t *testing.T) {
+	client := &fakeClient{objects: map[string]string{}}
+	store := s3.New(client, "payloads", "logs/")
+
+	ref, err := store.Put(context.Background(), "abc123", []byte("large payload"))
+	require.NoError(t, err)
+	require.Equal(t, "s3://payloads/logs/abc123", ref)
+	require.Equal(t, "large payload", client.objects["payloads/logs/abc123"])
+}
+
+func TestStorePutError(t *testing.T) {
+	store := s3.New(&fakeClient{err: errors.New("access denied")}, "payloads", "")
+
+	_, err := store.Put(context.Background(), "abc123", []byte(strings.Repeat("x", 10)))
+	require.EqualError(t, err, "access denied")
+}
@@ -0,0 +1,188 @@
+// Package sentry forwards Error, Panic and Fatal entries to Sentry.
+//
+// The Core returned by New is added to a logger with logger.WithCore. Every entry at or above
+// the configured level becomes a Sentry event carrying the message, the entry's fields as extra
+// data, the trace_id as a tag and trace context, and a stacktrace. Syncing the logger flushes
+// buffered events:
+//
+//	core, err := sentry.New(sentry.Config{DSN: dsn, Environment: "production"})
+//	if err != nil {
+//		return err
+//	}
+//	log, err := logger.New("myServiceName", logger.WithCore(core))
+package sentry
+
+import (
+	"errors"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultFlushTimeout is used when Config.FlushTimeout is not set.
+	defaultFlushTimeout = 2 * time.Second
+	// maxErrorDepth limits how many wrapped errors are reported as exceptions.
+	maxErrorDepth = 10
+
+	traceIDKey = "trace_id"
+	serviceKey = "service"
+)
+
+// Config configures the Sentry integration.
+type Config struct {
+	// DSN is the Sentry project DSN. An empty DSN disables sending events.
+	DSN string
+	// Environment is reported with every event, e.g. "production".
+	Environment string
+	// Release is reported with every event, e.g. a version or commit hash.
+	Release string
+	// Level is the minimum level forwarded to Sentry. It defaults to ErrorLevel;
+	// lower levels are raised to ErrorLevel.
+	Level zapcore.Level
+	// FlushTimeout bounds how long Sync waits for buffered events. It defaults to two seconds.
+	FlushTimeout time.Duration
+	// Transport overrides how events are delivered. It is mainly useful in tests.
+	Transport sentrygo.Transport
+}
+
+// Core is a zapcore.Core that sends entries to Sentry.
+type Core struct {
+	client       *sentrygo.Client
+	level        zapcore.Level
+	flushTimeout time.Duration
+	fields       []zapcore.Field
+}
+
+// New creates a Core sending events with a new Sentry client built from cfg.
+func New(cfg Config) (*Core, error) {
+	client, err := sentrygo.NewClient(sentrygo.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: cfg.Environment,
+		Release:     cfg.Release,
+		Transport:   cfg.Transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewWithClient(client, cfg), nil
+}
+
+// NewWithClient creates a Core sending events through an existing Sentry client.
+// The DSN, Environment, Release and Transport settings of cfg are ignored.
+func NewWithClient(client *sentrygo.Client, cfg Config) *Core {
+	level := cfg.Level
+	if level < zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+	timeout := cfg.FlushTimeout
+	if timeout <= 0 {
+		timeout = defaultFlushTimeout
+	}
+	return &Core{client: client, level: level, flushTimeout: timeout}
+}
+
+// Enabled implements zapcore.Core.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return level >= c.level
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.client.CaptureEvent(c.event(ent, append(c.fields[:len(c.fields):len(c.fields)], fields...)), nil, nil)
+	if ent.Level > zapcore.ErrorLevel {
+		// Panic and Fatal entries end the goroutine or process, so flush right away.
+		return c.Sync()
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core, flushing buffered events.
+func (c *Core) Sync() error {
+	if !c.client.Flush(c.flushTimeout) {
+		return errors.New("sentry: timed out flushing events")
+	}
+	return nil
+}
+
+// event converts an entry into a Sentry event.
+func (c *Core) event(ent zapcore.Entry, fields []zapcore.Field) *sentrygo.Event {
+	event := sentrygo.NewEvent()
+	event.Level = level(ent.Level)
+	event.Message = ent.Message
+	event.Timestamp = ent.Time
+	event.Logger = ent.LoggerName
+
+	enc := zapcore.NewMapObjectEncoder()
+	var errs []error
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok {
+				errs = append(errs, err)
+			}
+		}
+		f.AddTo(enc)
+	}
+
+	for key, value := range enc.Fields {
+		switch key {
+		case traceIDKey, serviceKey:
+			if s, ok := value.(string); ok {
+				event.Tags[key] = s
+				continue
+			}
+		}
+		event.Extra[key] = value
+	}
+	if traceID := event.Tags[traceIDKey]; traceID != "" {
+		event.Contexts["trace"] = sentrygo.Context{"trace_id": traceID}
+	}
+	if ent.Caller.Defined {
+		event.Extra["caller"] = ent.Caller.TrimmedPath()
+	}
+	if ent.Stack != "" {
+		event.Extra["stacktrace"] = ent.Stack
+	}
+
+	if len(errs) > 0 {
+		event.SetException(errs[0], maxErrorDepth)
+	}
+	if len(event.Exception) == 0 {
+		event.Threads = []sentrygo.Thread{{Stacktrace: sentrygo.NewStacktrace(), Current: true}}
+	} else if last := &event.Exception[len(event.Exception)-1]; last.Stacktrace == nil {
+		last.Stacktrace = sentrygo.NewStacktrace()
+	}
+	return event
+}
+
+// level maps a zap level to the corresponding Sentry level.
+func level(l zapcore.Level) sentrygo.Level {
+	switch {
+	case l >= zapcore.DPanicLevel:
+		return sentrygo.LevelFatal
+	case l == zapcore.ErrorLevel:
+		return sentrygo.LevelError
+	case l == zapcore.WarnLevel:
+		return sentrygo.LevelWarning
+	case l == zapcore.InfoLevel:
+		return sentrygo.LevelInfo
+	default:
+		return sentrygo.LevelDebug
+	}
+}
@@ -0,0 +1,124 @@
+package sentry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/janduursma/zap-logger-wrapper/contrib/sentry"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// transport is a sentrygo.Transport recording sent events.
+type transport struct {
+	mu      sync.Mutex
+	events  []*sentrygo.Event
+	flushes int
+}
+
+func (t *transport) Configure(sentrygo.ClientOptions) {}
+
+func (t *transport) SendEvent(event *sentrygo.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *transport) Flush(time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushes++
+	return true
+}
+
+func (t *transport) FlushWithContext(context.Context) bool { return t.Flush(0) }
+
+func (t *transport) Close() {}
+
+// newLogger creates a logger forwarding to Sentry through a recording transport.
+func newLogger(t *testing.T) (*logger.Logger, *transport) {
+	t.Helper()
+
+	tr := &transport{}
+	core, err := sentry.New(sentry.Config{DSN: "https://key@sentry.example.com/1", Environment: "test", Transport: tr})
+	require.NoError(t, err)
+
+	traceFn := func(context.Context) string { return "trace-1" }
+	l, err := logger.New("checkout", logger.WithCore(core), logger.WithTraceID(traceFn), logger.WithOutputPaths(nil))
+	require.NoError(t, err)
+	return l, tr
+}
+
+func TestForwardsErrors(t *testing.T) {
+	l, tr := newLogger(t)
+	ctx := context.Background()
+
+	l.Info(ctx, "not forwarded")
+	l.With("order_id", 42).Error(ctx, "charge failed", "err", errors.New("card declined"))
+
+	require.Len(t, tr.events, 1, "only Error and above should be forwarded")
+	event := tr.events[0]
+	require.Equal(t, sentrygo.LevelError, event.Level)
+	require.Equal(t, "charge failed", event.Message)
+	require.Equal(t, "test", event.Environment)
+	require.Equal(t, "trace-1", event.Tags["trace_id"])
+	require.Equal(t, "checkout", event.Tags["service"])
+	require.Equal(t, sentrygo.Context{"trace_id": "trace-1"}, event.Contexts["trace"])
+	require.Equal(t, int64(42), event.Extra["order_id"])
+	require.NotEmpty(t, event.Exception, "errors should be reported as exceptions")
+	require.Equal(t, "card declined", event.Exception[len(event.Exception)-1].Value)
+	require.NotNil(t, event.Exception[len(event.Exception)-1].Stacktrace)
+}
+
+func TestScrubbedErrorsStayScrubbed(t *testing.T) {
+	tr := &transport{}
+	core, err := sentry.New(sentry.Config{DSN: "https://key@sentry.example.com/1", Transport: tr})
+	require.NoError(t, err)
+	l, err := logger.New("checkout",
+		logger.WithCore(core),
+		logger.WithOutputPaths(nil),
+		logger.WithScrubPatterns(regexp.MustCompile(`\d{4}-\d{4}`)),
+	)
+	require.NoError(t, err)
+
+	cause := errors.New("card 1234-5678 declined")
+	l.Error(context.Background(), "charge failed", "err", fmt.Errorf("charge: %w", cause))
+
+	require.Len(t, tr.events, 1)
+	require.NotEmpty(t, tr.events[0].Exception)
+	for _, exception := range tr.events[0].Exception {
+		require.NotContains(t, exception.Value, "1234-5678", "the original errors should not be reported")
+	}
+}
+
+func TestEventsWithoutErrorCarryStacktrace(t *testing.T) {
+	l, tr := newLogger(t)
+
+	l.Error(context.Background(), "something went wrong")
+
+	require.Len(t, tr.events, 1)
+	require.Len(t, tr.events[0].Threads, 1)
+	require.NotNil(t, tr.events[0].Threads[0].Stacktrace)
+}
+
+func TestSyncFlushes(t *testing.T) {
+	l, tr := newLogger(t)
+
+	require.NoError(t, l.Sync())
+	require.Equal(t, 1, tr.flushes)
+}
+
+func TestLevelIsAtLeastError(t *testing.T) {
+	core := sentry.NewWithClient(nil, sentry.Config{Level: zapcore.InfoLevel})
+
+	require.False(t, core.Enabled(zapcore.WarnLevel))
+	require.True(t, core.Enabled(zapcore.ErrorLevel))
+	require.True(t, core.Enabled(zapcore.FatalLevel))
+}
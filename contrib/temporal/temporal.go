@@ -0,0 +1,52 @@
+// Package temporal implements the Temporal Go SDK's log.Logger on top of a Logger, so that the
+// logs of workflow and activity workers carry the service's fields and go through its sinks:
+//
+//	c, err := client.Dial(client.Options{Logger: temporal.New(log)})
+//
+// The SDK adds the workflow ID, run ID and other tags of workflows and activities through With,
+// so they are written as fields. The SDK passes no context, so the entries carry no trace ID;
+// workflow and activity code can log through the Logger with their context instead.
+package temporal
+
+import (
+	"context"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	temporallog "go.temporal.io/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger is a temporallog.Logger writing through a Logger.
+type Logger struct {
+	l *logger.Logger
+}
+
+// New returns a temporallog.Logger writing through l.
+func New(l *logger.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+// Debug implements temporallog.Logger.
+func (t *Logger) Debug(msg string, keyvals ...interface{}) {
+	t.l.LogDepth(context.Background(), 1, zapcore.DebugLevel, msg, keyvals...)
+}
+
+// Info implements temporallog.Logger.
+func (t *Logger) Info(msg string, keyvals ...interface{}) {
+	t.l.LogDepth(context.Background(), 1, zapcore.InfoLevel, msg, keyvals...)
+}
+
+// Warn implements temporallog.Logger.
+func (t *Logger) Warn(msg string, keyvals ...interface{}) {
+	t.l.LogDepth(context.Background(), 1, zapcore.WarnLevel, msg, keyvals...)
+}
+
+// Error implements temporallog.Logger.
+func (t *Logger) Error(msg string, keyvals ...interface{}) {
+	t.l.LogDepth(context.Background(), 1, zapcore.ErrorLevel, msg, keyvals...)
+}
+
+// With implements temporallog.WithLogger, returning a Logger writing keyvals with every entry.
+func (t *Logger) With(keyvals ...interface{}) temporallog.Logger {
+	return &Logger{l: t.l.With(keyvals...)}
+}
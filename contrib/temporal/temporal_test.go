@@ -0,0 +1,34 @@
+package temporal_test
+
+import (
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/contrib/temporal"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	temporallog "go.temporal.io/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	_ temporallog.Logger     = (*temporal.Logger)(nil)
+	_ temporallog.WithLogger = (*temporal.Logger)(nil)
+)
+
+func TestLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	tl := temporal.New(l)
+
+	wf := temporallog.With(tl, "WorkflowID", "order-42", "RunID", "run-1")
+	wf.Warn("Activity failed", "Attempt", 2)
+	tl.Debug("Started Worker")
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "Activity failed",
+		"WorkflowID", "order-42", "RunID", "run-1", "Attempt", int64(2), "service", "test-service")
+	started := entries.FilterMessage("Started Worker").All()
+	require.Len(t, started, 1)
+	require.NotContains(t, started[0].ContextMap(), "WorkflowID", "With should not mutate the parent")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/temporal_test\.go:\d+$`, e.Caller.String())
+	}
+}
@@ -0,0 +1,67 @@
+// Package watermill implements Watermill's LoggerAdapter on top of a Logger, so that the logs
+// of routers, publishers, subscribers and their middleware are written as structured entries
+// through the service's pipeline:
+//
+//	router, err := message.NewRouter(message.RouterConfig{}, watermill.New(log))
+//
+// Trace entries are written at DebugLevel. Watermill passes no context, so the entries carry
+// no trace ID; handlers can log through the Logger with the context of their message instead.
+package watermill
+
+import (
+	"context"
+	"sort"
+
+	gowatermill "github.com/ThreeDotsLabs/watermill"
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger is a gowatermill.LoggerAdapter writing through a Logger.
+type Logger struct {
+	l *logger.Logger
+}
+
+// New returns a gowatermill.LoggerAdapter writing through l.
+func New(l *logger.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+// Error implements gowatermill.LoggerAdapter, writing err as the error field.
+func (w *Logger) Error(msg string, err error, fields gowatermill.LogFields) {
+	w.l.LogDepth(context.Background(), 1, zapcore.ErrorLevel, msg, append(keyVals(fields), "error", err)...)
+}
+
+// Info implements gowatermill.LoggerAdapter.
+func (w *Logger) Info(msg string, fields gowatermill.LogFields) {
+	w.l.LogDepth(context.Background(), 1, zapcore.InfoLevel, msg, keyVals(fields)...)
+}
+
+// Debug implements gowatermill.LoggerAdapter.
+func (w *Logger) Debug(msg string, fields gowatermill.LogFields) {
+	w.l.LogDepth(context.Background(), 1, zapcore.DebugLevel, msg, keyVals(fields)...)
+}
+
+// Trace implements gowatermill.LoggerAdapter, writing at DebugLevel.
+func (w *Logger) Trace(msg string, fields gowatermill.LogFields) {
+	w.l.LogDepth(context.Background(), 1, zapcore.DebugLevel, msg, keyVals(fields)...)
+}
+
+// With implements gowatermill.LoggerAdapter, returning a Logger writing fields with every entry.
+func (w *Logger) With(fields gowatermill.LogFields) gowatermill.LoggerAdapter {
+	return &Logger{l: w.l.With(keyVals(fields)...)}
+}
+
+// keyVals returns fields as key-value pairs, sorted by key so that entries are stable.
+func keyVals(fields gowatermill.LogFields) []interface{} {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	kv := make([]interface{}, 0, 2*len(keys)+2)
+	for _, key := range keys {
+		kv = append(kv, key, fields[key])
+	}
+	return kv
+}
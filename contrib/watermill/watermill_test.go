@@ -0,0 +1,34 @@
+package watermill_test
+
+import (
+	"errors"
+	"testing"
+
+	gowatermill "github.com/ThreeDotsLabs/watermill"
+	"github.com/janduursma/zap-logger-wrapper/contrib/watermill"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ gowatermill.LoggerAdapter = (*watermill.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	w := watermill.New(l).With(gowatermill.LogFields{"handler_name": "orders"})
+
+	w.Trace("Received message", gowatermill.LogFields{"message_uuid": "m-1"})
+	w.Error("Handler returned error", errors.New("boom"), gowatermill.LogFields{"topic": "orders.created"})
+	watermill.New(l).Info("Starting router", nil)
+
+	loggertest.AssertLogged(t, entries, zapcore.DebugLevel, "Received message",
+		"handler_name", "orders", "message_uuid", "m-1")
+	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "Handler returned error",
+		"handler_name", "orders", "topic", "orders.created", "error", "boom")
+	started := entries.FilterMessage("Starting router").All()
+	require.Len(t, started, 1)
+	require.NotContains(t, started[0].ContextMap(), "handler_name")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/watermill_test\.go:\d+$`, e.Caller.String())
+	}
+}
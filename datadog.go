@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithDatadogTags sets the env and version of Datadog's unified service tagging, added as the
+// dd.env and dd.version fields by FormatDatadog. They default to the DD_ENV and DD_VERSION
+// environment variables; empty tags are omitted.
+func WithDatadogTags(env, version string) Option {
+	return func(l *Logger) {
+		l.ddEnv, l.ddVersion = env, version
+	}
+}
+
+// applyDatadog configures the keys of the entry's level, time, message, logger name and stack
+// trace for FormatDatadog.
+func applyDatadog(enc *zapcore.EncoderConfig) {
+	enc.TimeKey = "timestamp"
+	enc.LevelKey = "status"
+	enc.MessageKey = "message"
+	enc.NameKey = "logger.name"
+	enc.StacktraceKey = "error.stack"
+	enc.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	enc.EncodeLevel = zapcore.LowercaseLevelEncoder
+}
+
+// datadogCore wraps core so that entries carry the fields Datadog correlates logs and traces by.
+func (l *Logger) datadogCore(core zapcore.Core) zapcore.Core {
+	env, version := l.ddEnv, l.ddVersion
+	if env == "" {
+		env = os.Getenv("DD_ENV")
+	}
+	if version == "" {
+		version = os.Getenv("DD_VERSION")
+	}
+	var tags []zapcore.Field
+	if env != "" {
+		tags = append(tags, zap.String("dd.env", env))
+	}
+	if version != "" {
+		tags = append(tags, zap.String("dd.version", version))
+	}
+	return &fieldMapCore{Core: core.With(tags), field: datadogField}
+}
+
+// datadogField maps a field to Datadog's attributes: trace_id and span_id become dd.trace_id
+// and dd.span_id in decimal, service is repeated as dd.service, and errors become
+// error.message and error.kind. Other fields are kept.
+func datadogField(f zapcore.Field) []zapcore.Field {
+	switch {
+	case f.Key == "service" && f.Type == zapcore.StringType:
+		return []zapcore.Field{f, zap.String("dd.service", f.String)}
+	case (f.Key == "trace_id" || f.Key == "span_id") && f.Type == zapcore.StringType:
+		return []zapcore.Field{zap.String("dd."+f.Key, datadogID(f.String))}
+	case f.Type == zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return []zapcore.Field{
+				zap.String("error.message", err.Error()),
+				zap.String("error.kind", errorType(err)),
+			}
+		}
+	}
+	return []zapcore.Field{f}
+}
+
+// datadogID converts an OpenTelemetry trace or span ID, in hex, to the decimal form Datadog
+// expects: the lower 64 bits of a 128-bit trace ID as an unsigned integer. IDs that are not
+// 16 or 32 hex digits are returned as is.
+func datadogID(id string) string {
+	if len(id) != 16 && len(id) != 32 {
+		return id
+	}
+	n, err := strconv.ParseUint(id[len(id)-16:], 16, 64)
+	if err != nil {
+		return id
+	}
+	return strconv.FormatUint(n, 10)
+}
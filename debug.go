@@ -0,0 +1,28 @@
+//go:build !logger_nodebug
+
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugEnabled reports whether Debug logging is compiled in. It is false in builds with the
+// logger_nodebug tag, in which the Debug methods are no-ops. As a constant, it lets debug-only
+// work, such as computing expensive arguments, be removed from such builds:
+//
+//	if logger.DebugEnabled {
+//		l.Debug(ctx, "state", "dump", expensiveDump())
+//	}
+const DebugEnabled = true
+
+// Debug logs a message at DebugLevel, automatically including trace_id if available.
+func (l *Logger) Debug(ctx context.Context, msg string, keyVals ...interface{}) {
+	l.log(ctx, zapcore.DebugLevel, msg, keyVals)
+}
+
+// Debug logs a message at DebugLevel using the captured fields.
+func (s Snapshot) Debug(msg string, keyVals ...interface{}) {
+	s.logger.write(zapcore.DebugLevel, msg, s.logger.groupKeyVals(keyVals))
+}
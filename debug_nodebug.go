@@ -0,0 +1,23 @@
+//go:build logger_nodebug
+
+package logger
+
+import (
+	"context"
+)
+
+// DebugEnabled reports whether Debug logging is compiled in. It is false in builds with the
+// logger_nodebug tag, in which the Debug methods are no-ops. As a constant, it lets debug-only
+// work, such as computing expensive arguments, be removed from such builds:
+//
+//	if logger.DebugEnabled {
+//		l.Debug(ctx, "state", "dump", expensiveDump())
+//	}
+const DebugEnabled = false
+
+// Debug does nothing; Debug logging is compiled out by the logger_nodebug build tag.
+// The arguments are still evaluated, unless the call is guarded by DebugEnabled.
+func (l *Logger) Debug(_ context.Context, _ string, _ ...interface{}) {}
+
+// Debug does nothing; Debug logging is compiled out by the logger_nodebug build tag.
+func (s Snapshot) Debug(_ string, _ ...interface{}) {}
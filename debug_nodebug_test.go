@@ -0,0 +1,27 @@
+//go:build logger_nodebug
+
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNoDebug(t *testing.T) {
+	require.False(t, logger.DebugEnabled)
+
+	l, sink := newMemoryLogger(t, logger.WithLevel(zapcore.DebugLevel))
+	ctx := context.Background()
+
+	l.Debug(ctx, "compiled out")
+	l.Snapshot(ctx).Debug("compiled out")
+	l.Info(ctx, "kept")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "kept", entries[0]["msg"])
+}
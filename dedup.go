@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// repeatCountKey holds the number of identical entries collapsed into a repeat entry.
+const repeatCountKey = "repeat_count"
+
+// WithDedup collapses consecutive identical entries, i.e. entries with the same level,
+// message and fields, logged within window of the first one. The first entry is written as
+// usual; the repeats are held back and, once a different entry is logged, the window ends
+// or the Logger is synced or closed, written as a single copy of the last repeat carrying a
+// repeat_count field with their number, like syslog's "last message repeated N times".
+func WithDedup(window time.Duration) Option {
+	return func(l *Logger) {
+		l.dedupWindow = window
+	}
+}
+
+// dedupState is the run of identical entries shared by a dedupCore and its children.
+type dedupState struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	id      string
+	start   time.Time
+	repeats int
+	last    zapcore.Entry
+	fields  []zapcore.Field
+	core    zapcore.Core // The core the repeats are written to.
+	timer   *time.Timer  // Flushes the repeats when the window ends.
+}
+
+// dedupCore is a zapcore.Core collapsing consecutive identical entries.
+type dedupCore struct {
+	zapcore.Core
+	state   *dedupState
+	context string // The encoded fields added through With.
+}
+
+// newDedupCore wraps core so that consecutive identical entries within window are collapsed.
+func newDedupCore(core zapcore.Core, state *dedupState) zapcore.Core {
+	return &dedupCore{Core: core, state: state}
+}
+
+// With implements zapcore.Core. Children share the run of their parent, since entries are
+// only consecutive in the stream of all entries.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state, context: c.context + encodeFields(fields)}
+}
+
+// Check implements zapcore.Core.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	id := fmt.Sprintf("%d\x00%s\x00%s\x00%s", ent.Level, ent.Message, c.context, encodeFields(fields))
+
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == s.id && ent.Time.Sub(s.start) < s.window {
+		s.repeats++
+		s.last, s.fields, s.core = ent, fields, c.Core
+		if s.timer == nil {
+			s.startTimer()
+		}
+		return nil
+	}
+
+	err := s.flush()
+	s.id, s.start = id, ent.Time
+	return errors.Join(err, c.Core.Write(ent, fields))
+}
+
+// Sync implements zapcore.Core.
+func (c *dedupCore) Sync() error {
+	return errors.Join(c.state.stop(), c.Core.Sync())
+}
+
+// startTimer flushes the held back repeats once the window of the run ends. s.mu must be held.
+func (s *dedupState) startTimer() {
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(s.start.Add(s.window)), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// The run was flushed already if the timer was replaced.
+		if s.timer == timer {
+			_ = s.flush()
+		}
+	})
+	s.timer = timer
+}
+
+// stop writes the held back repeats, if any, and ends the run.
+func (s *dedupState) stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// flush writes the held back repeats, if any, and ends the run. s.mu must be held.
+func (s *dedupState) flush() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	repeats := s.repeats
+	s.id, s.repeats = "", 0
+	if repeats == 0 {
+		return nil
+	}
+	fields := append(s.fields[:len(s.fields):len(s.fields)], zap.Int(repeatCountKey, repeats))
+	return s.core.Write(s.last, fields)
+}
+
+// encodeFields returns a representation of fields that is equal for equal fields.
+func encodeFields(fields []zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	// fmt prints maps sorted by key.
+	return fmt.Sprint(enc.Fields)
+}
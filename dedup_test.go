@@ -0,0 +1,95 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithDedup(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithDedup(time.Minute), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	for range 5 {
+		l.Error(ctx, "disk full", "volume", "/data")
+	}
+	l.Error(ctx, "disk full", "volume", "/logs")
+	l.With("component", "x").Error(ctx, "disk full", "volume", "/logs")
+	l.Info(ctx, "recovered")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 5)
+	require.Equal(t, "/data", entries[0]["volume"])
+	require.NotContains(t, entries[0], "repeat_count")
+	require.Equal(t, "/data", entries[1]["volume"])
+	require.EqualValues(t, 4, entries[1]["repeat_count"], "repeats should be collapsed into one entry")
+	require.Equal(t, "/logs", entries[2]["volume"], "different fields should not be collapsed")
+	require.Equal(t, "x", entries[3]["component"], "different inherited fields should not be collapsed")
+	require.Equal(t, "recovered", entries[4]["msg"])
+}
+
+func TestWithDedupFlushesOnSync(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithDedup(time.Minute), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	l.Info(ctx, "tick")
+	l.Info(ctx, "tick")
+	require.Len(t, decodeLines(t, sink), 1)
+
+	require.NoError(t, l.Sync())
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.EqualValues(t, 1, entries[1]["repeat_count"])
+}
+
+func TestWithDedupWindow(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithDedup(time.Nanosecond), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	l.Info(ctx, "tick")
+	time.Sleep(time.Millisecond)
+	l.Info(ctx, "tick")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2, "entries outside the window should not be collapsed")
+	require.NotContains(t, entries[1], "repeat_count")
+}
+
+func TestWithDedupFlushesWhenWindowEnds(t *testing.T) {
+	// The observer is safe for the concurrent write of the timer, unlike the memory sink.
+	core, observed := observer.New(zapcore.InfoLevel)
+	l, _ := newMemoryLogger(t, logger.WithCore(core), logger.WithDedup(10*time.Millisecond), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	l.Info(ctx, "tick")
+	l.Info(ctx, "tick")
+	l.Info(ctx, "tick")
+
+	require.Eventually(t, func() bool {
+		return observed.Len() == 2
+	}, time.Second, time.Millisecond, "the repeats should be written without a further entry")
+	require.EqualValues(t, 2, observed.All()[1].ContextMap()["repeat_count"])
+}
+
+func TestWithDedupFlushesOnClose(t *testing.T) {
+	// Close does not sync the cores when the writes are buffered.
+	l, sink := newMemoryLogger(t,
+		logger.WithDedup(time.Minute),
+		logger.WithBufferedWrites(1<<10, time.Hour),
+		logger.WithSampling(0, 0, 0),
+	)
+	ctx := context.Background()
+
+	l.Info(ctx, "tick")
+	l.Info(ctx, "tick")
+	require.NoError(t, l.Close(ctx))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.EqualValues(t, 1, entries[1]["repeat_count"])
+}
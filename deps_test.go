@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// allowedDependencies are the only direct dependencies the core module may have.
+// Integrations with other dependencies belong in a module under contrib/.
+var allowedDependencies = map[string]bool{
+	"github.com/stretchr/testify": true,
+	"go.uber.org/zap":             true,
+}
+
+func TestCoreModuleDependencies(t *testing.T) {
+	data, err := os.ReadFile("go.mod")
+	require.NoError(t, err, "failed to read go.mod")
+
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "require (":
+			inRequire = true
+			continue
+		case line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inRequire:
+			continue
+		}
+
+		if line == "" || strings.HasSuffix(line, "// indirect") {
+			continue
+		}
+		module := strings.Fields(line)[0]
+		require.True(t, allowedDependencies[module], "core module must not depend on %s; move the integration to contrib/", module)
+	}
+}
+
+func TestCoreDoesNotImportTesting(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		require.NoError(t, err)
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			require.NoError(t, err)
+			require.NotEqual(t, "testing", path, "%s: testing helpers belong in loggertest", file)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"context"
+)
+
+// DetachContext returns a context for fire-and-forget work spawned from a request. It keeps
+// the values of ctx, so entries logged with it still carry the trace, request, tenant and user
+// IDs of the request, but it is never canceled and has no deadline, so the work can outlive
+// the request:
+//
+//	detached := logger.DetachContext(ctx)
+//	go func() {
+//		if err := sendReceipt(detached, order); err != nil {
+//			log.Error(detached, "sending receipt failed", "err", err)
+//		}
+//	}()
+func DetachContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
@@ -0,0 +1,36 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type traceIDKey struct{}
+
+func TestDetachContext(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(func(ctx context.Context) string {
+		id, _ := ctx.Value(traceIDKey{}).(string)
+		return id
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	ctx = context.WithValue(ctx, traceIDKey{}, "trace-1")
+	ctx = logger.ContextWithRequestID(ctx, "req-1")
+	detached := logger.DetachContext(ctx)
+	cancel()
+
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.NoError(t, detached.Err(), "the detached context should outlive the request")
+	_, ok := detached.Deadline()
+	require.False(t, ok, "the detached context should have no deadline")
+
+	l.Info(detached, "receipt sent")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "trace-1", entry["trace_id"])
+	require.Equal(t, "req-1", entry["request_id"])
+}
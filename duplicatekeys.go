@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DuplicateKeyPolicy selects what happens when an entry holds several fields with the same
+// key. Fields are considered in this order: the fields of New, such as service, the fields
+// added through With, from parent to child, and the key-value pairs of the logging call,
+// followed by the fields derived from the context, such as trace_id.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysAllow writes every field, so duplicate keys appear several times in the
+	// output, which most JSON parsers resolve by keeping the last. This is the default.
+	DuplicateKeysAllow DuplicateKeyPolicy = iota
+	// DuplicateKeysFirstWins keeps the first field with a key, so inherited fields can not
+	// be overridden by a child or a logging call.
+	DuplicateKeysFirstWins
+	// DuplicateKeysLastWins keeps the last field with a key, so children and logging calls
+	// override inherited fields.
+	DuplicateKeysLastWins
+	// DuplicateKeysError keeps the last field with a key like DuplicateKeysLastWins, and
+	// reports the duplicates as a write error, which zap prints to its error output.
+	// It is meant for development and tests, to catch accidental overrides.
+	DuplicateKeysError
+)
+
+// WithDuplicateKeys sets the policy for fields with duplicate keys. Policies other than
+// DuplicateKeysAllow resolve duplicates when an entry is written, at the cost of encoding
+// the inherited fields for every entry instead of once per With.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return func(l *Logger) {
+		l.duplicateKeys = policy
+	}
+}
+
+// duplicateKeysCore is a zapcore.Core resolving duplicate keys. It holds on to the fields
+// added through With, instead of passing them to the wrapped core, so they can be resolved
+// together with the fields of each entry.
+type duplicateKeysCore struct {
+	zapcore.Core
+	policy DuplicateKeyPolicy
+	fields []zapcore.Field
+}
+
+// With implements zapcore.Core.
+func (c *duplicateKeysCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *duplicateKeysCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *duplicateKeysCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	resolved, duplicates := resolveDuplicateKeys(all, c.policy == DuplicateKeysFirstWins)
+
+	err := c.Core.Write(ent, resolved)
+	if err == nil && c.policy == DuplicateKeysError && len(duplicates) > 0 {
+		err = fmt.Errorf("duplicate keys in entry %q: %s", ent.Message, strings.Join(duplicates, ", "))
+	}
+	return err
+}
+
+// resolveDuplicateKeys keeps a single field per key, either the first or the last, at its
+// own position. Keys are scoped by the namespaces opened before them. Fields that are never
+// encoded, such as the markers of Privacy and RateLimitKey, are always kept. It also returns
+// the duplicate keys, in order of first appearance.
+func resolveDuplicateKeys(fields []zapcore.Field, firstWins bool) ([]zapcore.Field, []string) {
+	scoped := make([]string, len(fields))
+	count := make(map[string]int, len(fields))
+	var (
+		scope      string
+		duplicates []string
+	)
+	for i, f := range fields {
+		if f.Type == zapcore.SkipType {
+			continue
+		}
+		scoped[i] = scope + f.Key
+		if f.Type == zapcore.NamespaceType {
+			scope += f.Key + "."
+		}
+		if count[scoped[i]]++; count[scoped[i]] == 2 {
+			duplicates = append(duplicates, scoped[i])
+		}
+	}
+	if len(duplicates) == 0 {
+		return fields, nil
+	}
+
+	seen := make(map[string]int, len(count))
+	out := make([]zapcore.Field, 0, len(fields))
+	for i, f := range fields {
+		if f.Type != zapcore.SkipType {
+			seen[scoped[i]]++
+			if firstWins && seen[scoped[i]] > 1 || !firstWins && seen[scoped[i]] < count[scoped[i]] {
+				continue
+			}
+		}
+		out = append(out, f)
+	}
+	return out, duplicates
+}
@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithDuplicateKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		policy   logger.DuplicateKeyPolicy
+		service  string
+		user     string
+		requests int
+	}{
+		{name: "allow", policy: logger.DuplicateKeysAllow, service: "override", user: "call", requests: 3},
+		{name: "first wins", policy: logger.DuplicateKeysFirstWins, service: "test-service", user: "parent", requests: 1},
+		{name: "last wins", policy: logger.DuplicateKeysLastWins, service: "override", user: "call", requests: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l, sink := newMemoryLogger(t, logger.WithDuplicateKeys(tc.policy))
+
+			l.With("user", "parent").With("user", "child").Info(context.Background(), "entry", "user", "call", "service", "override")
+
+			out := sink.logs.String()
+			require.Equal(t, tc.requests, strings.Count(out, `"user":`))
+			entries := decodeLines(t, sink)
+			// encoding/json keeps the last of duplicate keys.
+			require.Equal(t, tc.service, entries[0]["service"])
+			require.Equal(t, tc.user, entries[0]["user"])
+		})
+	}
+}
+
+func TestWithDuplicateKeysNamespaces(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithDuplicateKeys(logger.DuplicateKeysLastWins))
+
+	l.With("id", 1, zap.Namespace("request")).Info(context.Background(), "entry", "id", 2)
+
+	entries := decodeLines(t, sink)
+	require.EqualValues(t, 1, entries[0]["id"], "keys in different namespaces are not duplicates")
+	require.EqualValues(t, 2, entries[0]["request"].(map[string]any)["id"])
+}
+
+func TestWithDuplicateKeysError(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w // zap's error output is opened by New.
+	l, sink := newMemoryLogger(t, logger.WithDuplicateKeys(logger.DuplicateKeysError))
+	os.Stderr = stderr
+
+	l.With("user", "parent").Info(context.Background(), "entry", "user", "call")
+	l.Info(context.Background(), "no duplicates", "user", "call")
+
+	require.NoError(t, w.Close())
+	errOut, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Contains(t, string(errOut), `duplicate keys in entry "entry": user`)
+	require.NotContains(t, string(errOut), "no duplicates")
+
+	require.Len(t, decodeLines(t, sink), 2, "entries with duplicates should still be written")
+	require.Equal(t, 2, strings.Count(sink.logs.String(), `"user":"call"`))
+	require.NotContains(t, sink.logs.String(), "parent")
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// dynamicField is a field whose value is computed when an entry is logged.
+type dynamicField struct {
+	key      string
+	fn       func() interface{}
+	interval time.Duration
+
+	mu      sync.Mutex
+	value   interface{}
+	updated time.Time
+}
+
+// DynamicFieldOption defines a functional option for configuring a dynamic field.
+type DynamicFieldOption func(f *dynamicField)
+
+// WithRefreshInterval throttles a dynamic field: its function is called at most once per
+// interval, and the entries logged in between reuse the last value. It suits functions too
+// expensive to call for every entry, such as ones reading runtime/metrics.
+func WithRefreshInterval(interval time.Duration) DynamicFieldOption {
+	return func(f *dynamicField) {
+		f.interval = interval
+	}
+}
+
+// WithDynamicField adds a field to every entry whose value is returned by fn when the entry is
+// logged, so that runtime health indicators ride along without being passed at every call
+// site:
+//
+//	logger.WithDynamicField("goroutines", func() interface{} { return runtime.NumGoroutine() })
+//
+// fn is only called for entries enabled by the level, and may be called concurrently unless
+// throttled through WithRefreshInterval.
+func WithDynamicField(key string, fn func() interface{}, opts ...DynamicFieldOption) Option {
+	f := &dynamicField{key: key, fn: fn}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return func(l *Logger) {
+		l.dynamicFields = append(l.dynamicFields, f)
+	}
+}
+
+// dynamicFieldValues returns the dynamic fields of an entry at lvl, or nil when the entry is
+// disabled.
+func (l *Logger) dynamicFieldValues(lvl zapcore.Level) []interface{} {
+	if len(l.dynamicFields) == 0 || !l.zapLogger.Desugar().Core().Enabled(lvl) {
+		return nil
+	}
+	keyVals := make([]interface{}, 0, 2*len(l.dynamicFields))
+	for _, f := range l.dynamicFields {
+		keyVals = append(keyVals, f.key, f.get())
+	}
+	return keyVals
+}
+
+// get returns the current value of the field.
+func (f *dynamicField) get() interface{} {
+	if f.interval <= 0 {
+		return f.fn()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if now := time.Now(); f.updated.IsZero() || now.Sub(f.updated) >= f.interval {
+		f.value, f.updated = f.fn(), now
+	}
+	return f.value
+}
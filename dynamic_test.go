@@ -0,0 +1,45 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDynamicField(t *testing.T) {
+	calls := 0
+	l, sink := newMemoryLogger(t, logger.WithDynamicField("queue_depth", func() interface{} {
+		calls++
+		return calls * 10
+	}))
+
+	l.Info(context.Background(), "first")
+	l.Debug(context.Background(), "disabled")
+	l.With("worker", 1).Info(context.Background(), "second")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.EqualValues(t, 10, entries[0]["queue_depth"])
+	require.EqualValues(t, 20, entries[1]["queue_depth"])
+	require.Equal(t, 2, calls, "fn should not be called for disabled entries")
+}
+
+func TestWithDynamicFieldRefreshInterval(t *testing.T) {
+	calls := 0
+	l, sink := newMemoryLogger(t, logger.WithDynamicField("heap_mb", func() interface{} {
+		calls++
+		return calls
+	}, logger.WithRefreshInterval(time.Hour)))
+
+	for range 3 {
+		l.Info(context.Background(), "tick")
+	}
+
+	for _, entry := range decodeLines(t, sink) {
+		require.EqualValues(t, 1, entry["heap_mb"])
+	}
+	require.Equal(t, 1, calls)
+}
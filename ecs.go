@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ecsVersion is the version of the Elastic Common Schema followed by FormatECS.
+const ecsVersion = "8.11.0"
+
+// ecsKeys maps the keys of the fields added by the Logger to their ECS names.
+var ecsKeys = map[string]string{
+	"service":    "service.name",
+	"trace_id":   "trace.id",
+	"span_id":    "span.id",
+	RequestIDKey: "http.request.id",
+	UserIDKey:    "user.id",
+}
+
+// applyECS configures the keys of the entry's level, time, message, logger name and stack
+// trace for FormatECS. The caller is added as log.origin fields by ecsEntryFields instead.
+func applyECS(enc *zapcore.EncoderConfig) {
+	enc.TimeKey = "@timestamp"
+	enc.LevelKey = "log.level"
+	enc.MessageKey = "message"
+	enc.NameKey = "log.logger"
+	enc.StacktraceKey = "error.stack_trace"
+	enc.CallerKey = zapcore.OmitKey
+	enc.FunctionKey = zapcore.OmitKey
+	enc.EncodeLevel = zapcore.LowercaseLevelEncoder
+}
+
+// ecsField maps a field to ECS: the fields added by the Logger, such as service and trace_id,
+// are renamed, and errors become error.message and error.type. Other fields are kept.
+func ecsField(f zapcore.Field) []zapcore.Field {
+	if f.Type == zapcore.ErrorType {
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return []zapcore.Field{
+				zap.String("error.message", err.Error()),
+				zap.String("error.type", errorType(err)),
+			}
+		}
+	}
+	if key, ok := ecsKeys[f.Key]; ok {
+		f.Key = key
+	}
+	return []zapcore.Field{f}
+}
+
+// ecsEntryFields returns the ECS version and the log.origin fields describing the caller.
+func ecsEntryFields(ent zapcore.Entry) []zapcore.Field {
+	fields := []zapcore.Field{zap.String("ecs.version", ecsVersion)}
+	if ent.Caller.Defined {
+		fields = append(fields,
+			zap.String("log.origin.file.name", trimCallerPath(ent.Caller.File)),
+			zap.Int("log.origin.file.line", ent.Caller.Line),
+		)
+		if ent.Caller.Function != "" {
+			fields = append(fields, zap.String("log.origin.function", ent.Caller.Function))
+		}
+	}
+	return fields
+}
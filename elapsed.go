@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// elapsedKey is the key of the field holding the time since the start of the request.
+const elapsedKey = "elapsed_ms"
+
+// WithElapsedSince adds an elapsed_ms field to every entry whose context carries a
+// time.Time under ctxKey, typically the start time of the request stored by a middleware.
+// The field holds the milliseconds elapsed since that time, with sub-millisecond precision,
+// so the timeline within a request can be read directly from the logs.
+func WithElapsedSince(ctxKey interface{}) Option {
+	return func(l *Logger) {
+		l.elapsedKey = ctxKey
+	}
+}
+
+// elapsedFields returns the elapsed_ms field for ctx, if it carries a start time.
+func (l *Logger) elapsedFields(ctx context.Context) []interface{} {
+	if l.elapsedKey == nil || ctx == nil {
+		return nil
+	}
+	start, ok := ctx.Value(l.elapsedKey).(time.Time)
+	if !ok {
+		return nil
+	}
+	return []interface{}{elapsedKey, float64(time.Since(start).Microseconds()) / 1000}
+}
@@ -0,0 +1,29 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// startKey is the context key under which the tests store the request start time.
+type startKey struct{}
+
+func TestWithElapsedSince(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithElapsedSince(startKey{}))
+
+	ctx := context.WithValue(context.Background(), startKey{}, time.Now().Add(-250*time.Millisecond))
+	l.Info(ctx, "handled")
+	l.Info(context.Background(), "outside a request")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	elapsed, ok := entries[0]["elapsed_ms"].(float64)
+	require.True(t, ok)
+	require.GreaterOrEqual(t, elapsed, 250.0)
+	require.Less(t, elapsed, 10000.0)
+	require.NotContains(t, entries[1], "elapsed_ms")
+}
@@ -0,0 +1,12 @@
+package logger
+
+// Internal functions exported for tests in package logger_test.
+var (
+	ColorEnabled          = colorEnabled
+	EnableVirtualTerminal = enableVirtualTerminal
+	TrimCallerPath        = trimCallerPath
+	MetadataEndpoint      = &metadataEndpoint
+	CgroupPath            = &cgroupPath
+	MountinfoPath         = &mountinfoPath
+	ReadBuildInfo         = &readBuildInfo
+)
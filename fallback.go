@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fallbackMessage is the message of the entry describing a failed write.
+const fallbackMessage = "write to output failed, entry written to the fallback output"
+
+// WithFallbackOutput writes the entries that an output path fails to write to the fallback
+// output path instead, typically "stderr", so that entries are not silently lost when a disk
+// fills up or a collector is down. Every failure is preceded by an Error entry describing it,
+// with the failed output path, its credentials masked, and the error. The fallback is opened through zap.Open, so it
+// must not use the scheme of a SinkFactory. Writes rescued by the fallback are not write errors;
+// the hooks registered through WithWriteErrorHook are only called when the fallback fails too.
+func WithFallbackOutput(path string) Option {
+	return func(l *Logger) {
+		l.fallbackPath = path
+	}
+}
+
+// fallbackOutput is the output receiving the failed writes, shared by the output paths.
+type fallbackOutput struct {
+	service string
+	encoder zapcore.Encoder
+
+	mu sync.Mutex
+	ws zapcore.WriteSyncer
+}
+
+// openFallback opens the fallback output at path, describing the failures with encoder.
+func openFallback(path, service string, encoder zapcore.Encoder) (*fallbackOutput, func(), error) {
+	ws, closeSink, err := zap.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &fallbackOutput{service: service, encoder: encoder, ws: ws}, closeSink, nil
+}
+
+// write writes the description of the failure to write p to path, followed by p.
+func (f *fallbackOutput) write(path string, failure error, p []byte) error {
+	buf, err := f.encoder.EncodeEntry(zapcore.Entry{
+		Level:   zapcore.ErrorLevel,
+		Time:    time.Now(),
+		Message: fallbackMessage,
+	}, []zapcore.Field{
+		zap.String("service", f.service),
+		zap.String("output", redactPath(path)),
+		zap.Error(failure),
+	})
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.ws.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err = f.ws.Write(p)
+	return err
+}
+
+// fallbackSink is a zapcore.WriteSyncer writing to its fallback when a write fails.
+type fallbackSink struct {
+	zapcore.WriteSyncer
+	path     string
+	fallback *fallbackOutput
+}
+
+// Write implements io.Writer.
+func (s *fallbackSink) Write(p []byte) (int, error) {
+	n, err := s.WriteSyncer.Write(p)
+	if err == nil {
+		return n, nil
+	}
+	if ferr := s.fallback.write(s.path, err, p); ferr != nil {
+		return n, errors.Join(err, ferr)
+	}
+	return len(p), nil
+}
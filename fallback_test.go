@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithFallbackOutput(t *testing.T) {
+	sink := &flakySink{down: true}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+	fallback := filepath.Join(t.TempDir(), "fallback.log")
+
+	hookCalled := false
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{scheme + "://"}),
+		logger.WithFallbackOutput(fallback),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, _ error) { hookCalled = true }),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	l.Info(ctx, "lost without a fallback")
+	sink.setDown(false)
+	l.Info(ctx, "written to the output")
+
+	data, err := os.ReadFile(fallback)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var failure, entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &failure))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	require.Equal(t, "error", failure["level"])
+	require.Equal(t, scheme+"://", failure["output"])
+	require.Equal(t, "unavailable", failure["error"])
+	require.Equal(t, "test-service", failure["service"])
+	require.Equal(t, "lost without a fallback", entry["msg"])
+
+	require.Contains(t, sink.logs.String(), "written to the output")
+	require.False(t, hookCalled, "rescued writes are not write errors")
+}
+
+func TestWithFallbackOutputInvalidPath(t *testing.T) {
+	_, err := logger.New("test-service", logger.WithFallbackOutput(filepath.Join(t.TempDir(), "missing", "fallback.log")))
+	require.Error(t, err)
+}
+
+func TestWithFallbackOutputRedactsPath(t *testing.T) {
+	sink := &flakySink{down: true}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+	fallback := filepath.Join(t.TempDir(), "fallback.log")
+	path := scheme + "://user:hunter2@collector:8088?token=s3cret&bearer=b3arer&index=main"
+
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{path}), logger.WithFallbackOutput(fallback))
+	require.NoError(t, err)
+	l.Info(context.Background(), "lost without a fallback")
+
+	data, err := os.ReadFile(fallback)
+	require.NoError(t, err)
+	var failure map[string]any
+	require.NoError(t, json.Unmarshal([]byte(strings.Split(string(data), "\n")[0]), &failure))
+	require.Equal(t, scheme+"://xxxxx@collector:8088?bearer=xxxxx&index=main&token=xxxxx", failure["output"])
+	for _, secret := range []string{"hunter2", "s3cret", "b3arer"} {
+		require.NotContains(t, string(data), secret)
+	}
+}
@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// FieldSource identifies a layer of initial fields. When several layers set the same key, the
+// value of the later source in the order below wins.
+type FieldSource int
+
+const (
+	// FieldsFromDefaults are the fields set through WithDefaultFields, e.g. by a platform library.
+	FieldsFromDefaults FieldSource = iota
+	// FieldsFromPlatform are the fields describing the binary and the platform it runs on, see
+	// WithBuildInfo, WithCloudMetadata and WithKubernetesFields.
+	FieldsFromPlatform
+	// FieldsFromEnv are the fields read from the environment, see WithEnvFields.
+	FieldsFromEnv
+	// FieldsFromFile are the fields read from files, see WithFieldsFile.
+	FieldsFromFile
+	// FieldsFromCode are the fields set through WithFields.
+	FieldsFromCode
+)
+
+// String implements fmt.Stringer.
+func (s FieldSource) String() string {
+	switch s {
+	case FieldsFromDefaults:
+		return "defaults"
+	case FieldsFromPlatform:
+		return "platform"
+	case FieldsFromEnv:
+		return "env"
+	case FieldsFromFile:
+		return "file"
+	case FieldsFromCode:
+		return "code"
+	default:
+		return fmt.Sprintf("FieldSource(%d)", int(s))
+	}
+}
+
+// FieldConflict reports an initial field whose value was overridden by a later layer.
+type FieldConflict struct {
+	// Key is the key of the field.
+	Key string
+	// Value and Source are the value the field ends up with and where it was set.
+	Value  interface{}
+	Source FieldSource
+	// Overridden and OverriddenSource are the value that was replaced and where it was set.
+	Overridden       interface{}
+	OverriddenSource FieldSource
+}
+
+// String implements fmt.Stringer.
+func (c FieldConflict) String() string {
+	return fmt.Sprintf("field %q: %v from %s overrides %v from %s",
+		c.Key, c.Value, c.Source, c.Overridden, c.OverriddenSource)
+}
+
+// fieldLayer is a source of initial fields, loaded when the Logger is created.
+type fieldLayer struct {
+	source FieldSource
+	load   func() ([]keyVal, error)
+}
+
+// WithDefaultFields adds base fields to every entry, with the lowest precedence. It lets
+// platform libraries provide fields, such as the region or cluster, that services can override
+// from the environment, a file or code.
+func WithDefaultFields(fields map[string]interface{}) Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromDefaults,
+			load:   func() ([]keyVal, error) { return mapFields(fields), nil },
+		})
+	}
+}
+
+// WithEnvFields adds a field to every entry for each environment variable starting with
+// prefix, e.g. "LOG_FIELD_". The key is the lowercased remainder of the variable's name, so
+// LOG_FIELD_REGION=eu-west-1 adds region=eu-west-1. The environment is read when the Logger
+// is created; its fields override the defaults.
+func WithEnvFields(prefix string) Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromEnv,
+			load: func() ([]keyVal, error) {
+				fields := map[string]interface{}{}
+				for _, kv := range os.Environ() {
+					name, value, _ := strings.Cut(kv, "=")
+					if key, ok := strings.CutPrefix(name, prefix); ok && key != "" {
+						fields[strings.ToLower(key)] = value
+					}
+				}
+				return mapFields(fields), nil
+			},
+		})
+	}
+}
+
+// WithFieldsFile adds the fields of a JSON file, holding a single object, to every entry. The
+// file is read when the Logger is created, and New fails when it cannot be read; its fields
+// override the defaults and the environment.
+func WithFieldsFile(path string) Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromFile,
+			load: func() ([]keyVal, error) {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("read fields file: %w", err)
+				}
+				dec := json.NewDecoder(bytes.NewReader(data))
+				dec.UseNumber()
+				var fields map[string]interface{}
+				if err := dec.Decode(&fields); err != nil {
+					return nil, fmt.Errorf("decode fields file %s: %w", path, err)
+				}
+				for k, v := range fields {
+					fields[k] = jsonNumber(v)
+				}
+				return mapFields(fields), nil
+			},
+		})
+	}
+}
+
+// WithFields adds key-value pairs to every entry, with the highest precedence among the
+// initial fields.
+func WithFields(keyVals ...interface{}) Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromCode,
+			load:   func() ([]keyVal, error) { return splitKeyVals(keyVals), nil },
+		})
+	}
+}
+
+// jsonNumber converts a decoded json.Number to an int64, or a float64 if it is not an integer,
+// so that it is encoded as a number. Other values are returned as is.
+func jsonNumber(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// mapFields converts fields to key-value pairs, sorted by key.
+func mapFields(fields map[string]interface{}) []keyVal {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]keyVal, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, keyVal{key: k, items: []interface{}{k, fields[k]}})
+	}
+	return kvs
+}
+
+// FieldConflicts returns the initial fields that were set by more than one layer, with
+// different values, in the order they were resolved.
+func (l *Logger) FieldConflicts() []FieldConflict {
+	return l.fieldConflicts
+}
+
+// resolveFields loads the field layers in order of precedence and merges them. It returns
+// the resulting fields and records the conflicts.
+func (l *Logger) resolveFields() ([]zap.Field, error) {
+	layers := append([]fieldLayer(nil), l.fieldLayers...)
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].source < layers[j].source })
+
+	type resolved struct {
+		field  zap.Field
+		value  interface{}
+		source FieldSource
+	}
+	var (
+		order []string
+		byKey = map[string]resolved{}
+	)
+	for _, layer := range layers {
+		kvs, err := layer.load()
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range kvs {
+			var r resolved
+			switch len(kv.items) {
+			case 1:
+				f, ok := kv.items[0].(zap.Field)
+				if !ok {
+					return nil, fmt.Errorf("invalid initial field %v", kv.items[0])
+				}
+				r = resolved{field: f, value: f, source: layer.source}
+			case 2:
+				if kv.key == "" {
+					return nil, fmt.Errorf("invalid initial field key %v", kv.items[0])
+				}
+				r = resolved{field: zap.Any(kv.key, kv.items[1]), value: kv.items[1], source: layer.source}
+			}
+
+			prev, ok := byKey[kv.key]
+			if !ok {
+				order = append(order, kv.key)
+			} else if fmt.Sprint(prev.value) != fmt.Sprint(r.value) {
+				l.fieldConflicts = append(l.fieldConflicts, FieldConflict{
+					Key:              kv.key,
+					Value:            r.value,
+					Source:           r.source,
+					Overridden:       prev.value,
+					OverriddenSource: prev.source,
+				})
+			}
+			byKey[kv.key] = r
+		}
+	}
+
+	fields := make([]zap.Field, 0, len(order))
+	for _, k := range order {
+		fields = append(fields, byKey[k].field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,67 @@
+// Package fields provides typed constructors for the fields of log entries, so that common
+// value types are checked by the compiler instead of relying on alternating keys and values:
+//
+//	l.Info(ctx, "order placed", fields.String("order_id", id), fields.Dur("latency", d))
+//
+// The fields are zap.Field values, so they can be mixed with key-value pairs in the calls of
+// a Logger, which passes them to zap's sugared logger as they are, and passed to a zap.Logger
+// directly, e.g. the one given to logger.WithExistingZap.
+package fields
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// String returns a field holding a string.
+func String(key, value string) zap.Field {
+	return zap.String(key, value)
+}
+
+// Strings returns a field holding a slice of strings, encoded as an array.
+func Strings(key string, values []string) zap.Field {
+	return zap.Strings(key, values)
+}
+
+// Int returns a field holding an int.
+func Int(key string, value int) zap.Field {
+	return zap.Int(key, value)
+}
+
+// Bool returns a field holding a bool.
+func Bool(key string, value bool) zap.Field {
+	return zap.Bool(key, value)
+}
+
+// Dur returns a field holding a duration, encoded by the encoder of the Logger: as seconds
+// with the default JSON format.
+func Dur(key string, value time.Duration) zap.Field {
+	return zap.Duration(key, value)
+}
+
+// Time returns a field holding a time, encoded like the timestamps of the entries.
+func Time(key string, value time.Time) zap.Field {
+	return zap.Time(key, value)
+}
+
+// Err returns the error field of an entry, keyed error. A nil error adds no field.
+func Err(err error) zap.Field {
+	return zap.Error(err)
+}
+
+// Any returns a field holding value, choosing the most efficient encoding for its type, and
+// falling back to reflection.
+func Any(key string, value interface{}) zap.Field {
+	return zap.Any(key, value)
+}
+
+// JSON returns a field embedding raw, a JSON document, as is instead of as an escaped string,
+// e.g. a request body or an upstream response. Invalid JSON is written as a string.
+func JSON(key string, raw []byte) zap.Field {
+	if !json.Valid(raw) {
+		return zap.ByteString(key, raw)
+	}
+	return zap.Reflect(key, json.RawMessage(raw))
+}
@@ -0,0 +1,52 @@
+package fields_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/janduursma/zap-logger-wrapper/v2/fields"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFieldsWithLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	l.Info(context.Background(), "order placed",
+		fields.String("order_id", "o-1"),
+		"items", 3,
+		fields.Strings("tags", []string{"gift", "express"}),
+		fields.Int("quantity", 2),
+		fields.Bool("paid", true),
+		fields.Dur("latency", 250*time.Millisecond),
+		fields.Time("placed_at", at),
+		fields.Err(errors.New("card declined")),
+		fields.Any("amount", 12.5),
+	)
+
+	loggertest.AssertLogged(t, entries, zapcore.InfoLevel, "order placed",
+		"order_id", "o-1", "items", int64(3), "quantity", int64(2), "paid", true,
+		"latency", 250*time.Millisecond, "placed_at", at, "error", "card declined", "amount", 12.5)
+	require.Equal(t, []interface{}{"gift", "express"}, entries.All()[0].ContextMap()["tags"])
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	zap.New(core).Info("upstream response",
+		fields.JSON("body", []byte(`{"status":"ok","items":[1,2]}`)),
+		fields.JSON("invalid", []byte(`{"status":`)),
+	)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, map[string]any{"status": "ok", "items": []any{1.0, 2.0}}, entry["body"])
+	require.Equal(t, `{"status":`, entry["invalid"])
+}
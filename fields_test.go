@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInitialFieldLayers(t *testing.T) {
+	t.Setenv("TEST_FIELD_REGION", "eu-west-1")
+	t.Setenv("TEST_FIELD_CLUSTER", "blue")
+
+	file := filepath.Join(t.TempDir(), "fields.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"cluster": "green", "replicas": 3}`), 0o600))
+
+	// The options are passed out of order; the layers are still applied by precedence.
+	l, sink := newMemoryLogger(t,
+		logger.WithFields("cluster", "red", zap.Bool("canary", true)),
+		logger.WithFieldsFile(file),
+		logger.WithEnvFields("TEST_FIELD_"),
+		logger.WithDefaultFields(map[string]interface{}{"region": "us-east-1", "team": "payments"}),
+	)
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "payments", entry["team"])
+	require.Equal(t, "eu-west-1", entry["region"], "env should override the defaults")
+	require.Equal(t, "red", entry["cluster"], "code should override the file and env")
+	require.EqualValues(t, 3, entry["replicas"])
+	require.Equal(t, true, entry["canary"])
+
+	require.Equal(t, []logger.FieldConflict{
+		{Key: "region", Value: "eu-west-1", Source: logger.FieldsFromEnv, Overridden: "us-east-1", OverriddenSource: logger.FieldsFromDefaults},
+		{Key: "cluster", Value: "green", Source: logger.FieldsFromFile, Overridden: "blue", OverriddenSource: logger.FieldsFromEnv},
+		{Key: "cluster", Value: "red", Source: logger.FieldsFromCode, Overridden: "green", OverriddenSource: logger.FieldsFromFile},
+	}, l.FieldConflicts())
+}
+
+func TestInitialFieldsWithoutConflicts(t *testing.T) {
+	l, _ := newMemoryLogger(t,
+		logger.WithDefaultFields(map[string]interface{}{"region": "eu-west-1"}),
+		logger.WithFields("region", "eu-west-1"),
+	)
+	require.Empty(t, l.FieldConflicts(), "equal values should not be reported")
+}
+
+func TestInitialFieldsFileErrors(t *testing.T) {
+	_, err := logger.New("test-service", logger.WithFieldsFile(filepath.Join(t.TempDir(), "missing.json")))
+	require.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "fields.json")
+	require.NoError(t, os.WriteFile(file, []byte(`["not", "an", "object"]`), 0o600))
+	_, err = logger.New("test-service", logger.WithFieldsFile(file))
+	require.Error(t, err)
+}
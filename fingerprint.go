@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fingerprintKey is the field under which the error fingerprint is emitted.
+const fingerprintKey = "error_fingerprint"
+
+// volatileTokens matches message fragments that typically differ between
+// otherwise identical failures, such as UUIDs, hex addresses and numbers.
+var volatileTokens = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0x[0-9a-fA-F]+|\d+`)
+
+// WithErrorFingerprint enables a stable error_fingerprint field on Error-level (and above) entries.
+// The fingerprint is a hash of the error's type chain and its normalized message, so identical
+// failures can be grouped across services regardless of the log message used.
+func WithErrorFingerprint() Option {
+	return func(l *Logger) {
+		l.errorFingerprint = true
+	}
+}
+
+// normalizeMessage replaces volatile tokens in msg, so that messages which only differ in
+// IDs, addresses or counters produce the same fingerprint.
+func normalizeMessage(msg string) string {
+	return strings.TrimSpace(volatileTokens.ReplaceAllString(msg, "#"))
+}
+
+// fingerprint computes the fingerprint for an entry with the given message and error.
+// When err is nil, the normalized message alone is hashed. Errors scrubbed by
+// WithScrubPatterns are fingerprinted as the original error, like LastError does.
+func fingerprint(msg string, err error) string {
+	if scrubbed, ok := err.(*scrubbedError); ok {
+		err = scrubbed.err
+	}
+	h := sha256.New()
+	if err == nil {
+		h.Write([]byte(normalizeMessage(msg)))
+		return hex.EncodeToString(h.Sum(nil))[:16]
+	}
+
+	var types []string
+	queue := []error{err}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		types = append(types, fmt.Sprintf("%T", e))
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			queue = append(queue, u.Unwrap()...)
+		default:
+			if next := errors.Unwrap(e); next != nil {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	h.Write([]byte(strings.Join(types, ">")))
+	h.Write([]byte{'|'})
+	h.Write([]byte(normalizeMessage(err.Error())))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// fingerprintCore is a zapcore.Core that adds an error fingerprint to Error-level entries.
+type fingerprintCore struct {
+	zapcore.Core
+	err error
+}
+
+// newFingerprintCore wraps core so that Error-level entries carry an error fingerprint.
+func newFingerprintCore(core zapcore.Core) zapcore.Core {
+	return &fingerprintCore{Core: core}
+}
+
+// With implements zapcore.Core, remembering any error added as a context field.
+func (c *fingerprintCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &fingerprintCore{Core: c.Core.With(fields), err: c.err}
+	if err := findError(fields); err != nil {
+		clone.err = err
+	}
+	return clone
+}
+
+// Check implements zapcore.Core.
+func (c *fingerprintCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *fingerprintCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= zapcore.ErrorLevel {
+		err := findError(fields)
+		if err == nil {
+			err = c.err
+		}
+		fields = append(fields, zap.String(fingerprintKey, fingerprint(ent.Message, err)))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// findError returns the first error carried by fields, if any.
+func findError(fields []zapcore.Field) error {
+	for _, f := range fields {
+		if f.Type != zapcore.ErrorType {
+			continue
+		}
+		if err, ok := f.Interface.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
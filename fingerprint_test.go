@@ -0,0 +1,92 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeLines parses each JSON log line written to the sink.
+func decodeLines(t *testing.T, sink *memorySink) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(sink.logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "log line should be valid JSON")
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+type notFoundError struct{ id int }
+
+func (e *notFoundError) Error() string { return fmt.Sprintf("record %d not found", e.id) }
+
+func TestErrorFingerprint(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithErrorFingerprint())
+	ctx := context.Background()
+
+	l.Error(ctx, "lookup failed", "err", fmt.Errorf("query: %w", &notFoundError{id: 1}))
+	l.Error(ctx, "fetching user", "err", fmt.Errorf("query: %w", &notFoundError{id: 42}))
+	l.Error(ctx, "lookup failed", "err", errors.New("record 1 not found"))
+	l.Info(ctx, "not an error")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 4)
+
+	first, ok := entries[0]["error_fingerprint"].(string)
+	require.True(t, ok, "error entries should carry a fingerprint")
+	require.NotEmpty(t, first)
+
+	require.Equal(t, first, entries[1]["error_fingerprint"], "same error chain should share a fingerprint")
+	require.NotEqual(t, first, entries[2]["error_fingerprint"], "different error types should not share a fingerprint")
+	require.NotContains(t, entries[3], "error_fingerprint", "info entries should not carry a fingerprint")
+}
+
+func TestErrorFingerprintFromWithFields(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithErrorFingerprint())
+	ctx := context.Background()
+
+	l.With("err", errors.New("timeout after 30s")).Error(ctx, "call failed")
+	l.Error(ctx, "call failed", "err", errors.New("timeout after 5s"))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, entries[0]["error_fingerprint"], entries[1]["error_fingerprint"])
+}
+
+func TestErrorFingerprintDisabledByDefault(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	l.Error(context.Background(), "failed", "err", errors.New("boom"))
+
+	require.NotContains(t, sink.logs.String(), "error_fingerprint")
+}
+
+func TestErrorFingerprintWithScrubPatterns(t *testing.T) {
+	email := regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+	l, sink := newMemoryLogger(t, logger.WithErrorFingerprint(), logger.WithScrubPatterns(email))
+	ctx := logger.ContextWithLastError(context.Background())
+
+	l.Error(ctx, "lookup failed", "err", fmt.Errorf("user a@b.io: %w", &notFoundError{id: 1}))
+	last, ok := l.LastError(ctx)
+	require.True(t, ok)
+	l.Error(ctx, "lookup failed", "err", errors.New("user a@b.io: record 1 not found"))
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "user [REDACTED]: record 1 not found", entries[0]["err"])
+	require.Equal(t, last.Fingerprint, entries[0]["error_fingerprint"], "the fingerprint should match LastError's")
+	require.NotEqual(t, entries[0]["error_fingerprint"], entries[1]["error_fingerprint"],
+		"different error types should not share a fingerprint")
+}
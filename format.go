@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Format selects how entries are encoded.
+type Format string
+
+const (
+	// FormatJSON encodes entries as JSON objects, one per line. This is the default.
+	FormatJSON Format = "json"
+	// FormatConsole encodes entries in a human-friendly, tab-separated form for local development.
+	// Levels are colored when writing to a terminal that supports it.
+	FormatConsole Format = "console"
+	// FormatECS encodes entries as JSON objects following the Elastic Common Schema, so that
+	// Kibana and Elastic APM can correlate them: e.g. @timestamp, log.level, message,
+	// service.name, trace.id and error.message.
+	FormatECS Format = "ecs"
+	// FormatGCP encodes entries as JSON objects following the structured logging conventions
+	// of Google Cloud Logging, as read from the standard output of Cloud Run and GKE: severity,
+	// time, message, and the logging.googleapis.com/ trace, sourceLocation and labels fields.
+	// See WithGCPProject and WithGCPLabels.
+	FormatGCP Format = "gcp"
+	// FormatDatadog encodes entries as JSON objects with Datadog's standard attributes, so that
+	// Datadog correlates them with traces: status, message, dd.trace_id and dd.span_id in
+	// decimal, and dd.service, dd.env and dd.version. See WithDatadogTags.
+	FormatDatadog Format = "datadog"
+	// FormatCEF encodes entries as ArcSight Common Event Format events, one per line, for SIEM
+	// ingestion. Fields become extensions, see WithSIEMFieldMap, WithSIEMDevice and
+	// SIEMEventIDKey. The sinks under sinks/ expect JSON and cannot be used with it.
+	FormatCEF Format = "cef"
+	// FormatLEEF encodes entries as IBM QRadar Log Event Extended Format 1.0 events, one per
+	// line, with tab-delimited attributes. It is configured like FormatCEF.
+	FormatLEEF Format = "leef"
+)
+
+// WithFormat allows a custom encoding format to be set.
+func WithFormat(format Format) Option {
+	return func(l *Logger) {
+		l.format = format
+	}
+}
+
+// applyFormat configures config for the selected format.
+func (l *Logger) applyFormat(config *zap.Config) error {
+	config.EncoderConfig.EncodeCaller = trimmedCallerEncoder
+
+	switch l.format {
+	case FormatJSON:
+		if l.lambdaMode {
+			applyLambda(&config.EncoderConfig)
+		}
+	case FormatConsole:
+		config.Encoding = "console"
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		if colorEnabled(l.colorMode, l.outputPaths, os.Getenv) {
+			config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+	case FormatECS:
+		applyECS(&config.EncoderConfig)
+	case FormatGCP:
+		applyGCP(&config.EncoderConfig)
+	case FormatDatadog:
+		applyDatadog(&config.EncoderConfig)
+	case FormatCEF, FormatLEEF:
+		config.Encoding = string(l.format)
+	default:
+		return fmt.Errorf("unknown log format %q", l.format)
+	}
+	return nil
+}
+
+// newEncoder returns the encoder of the selected format, configured by applyFormat.
+func (l *Logger) newEncoder(service string, config zap.Config) zapcore.Encoder {
+	switch config.Encoding {
+	case "console":
+		return zapcore.NewConsoleEncoder(config.EncoderConfig)
+	case string(FormatCEF), string(FormatLEEF):
+		return l.newSIEMEncoder(service)
+	default:
+		return zapcore.NewJSONEncoder(config.EncoderConfig)
+	}
+}
+
+// formatCore wraps core, writing to the output paths, so that the fields follow the
+// conventions of the selected format.
+func (l *Logger) formatCore(core zapcore.Core) zapcore.Core {
+	switch l.format {
+	case FormatECS:
+		return &fieldMapCore{Core: core, field: ecsField, entry: ecsEntryFields}
+	case FormatGCP:
+		return l.gcpCore(core)
+	case FormatDatadog:
+		return l.datadogCore(core)
+	default:
+		return core
+	}
+}
+
+// fieldMapCore is a zapcore.Core mapping the fields of the entries to those of a format.
+type fieldMapCore struct {
+	zapcore.Core
+	// field returns the fields replacing f.
+	field func(f zapcore.Field) []zapcore.Field
+	// entry returns the fields derived from the entry itself, if set.
+	entry func(ent zapcore.Entry) []zapcore.Field
+	// label reports whether a field is moved into the object under labelsKey, if set.
+	label     func(f zapcore.Field) bool
+	labelsKey string
+	labels    []zapcore.Field // The labels added through With.
+}
+
+// With implements zapcore.Core.
+func (c *fieldMapCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	labels, fields := c.splitLabels(fields)
+	clone.labels = append(c.labels[:len(c.labels):len(c.labels)], labels...)
+	clone.Core = c.Core.With(c.mapFields(fields))
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *fieldMapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *fieldMapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	labels, fields := c.splitLabels(fields)
+	fields = c.mapFields(fields)
+	if c.entry != nil {
+		fields = append(fields, c.entry(ent)...)
+	}
+	if labels = append(c.labels[:len(c.labels):len(c.labels)], labels...); len(labels) > 0 {
+		fields = append(fields, zap.Object(c.labelsKey, stringFields(labels)))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// mapFields returns the mapped fields, leaving the caller's slice untouched.
+func (c *fieldMapCore) mapFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, c.field(f)...)
+	}
+	return out
+}
+
+// splitLabels separates the fields that are labels from the others.
+func (c *fieldMapCore) splitLabels(fields []zapcore.Field) (labels, others []zapcore.Field) {
+	if c.label == nil {
+		return nil, fields
+	}
+	for _, f := range fields {
+		if c.label(f) {
+			labels = append(labels, f)
+		} else {
+			others = append(others, f)
+		}
+	}
+	return labels, others
+}
+
+// stringFields is a zapcore.ObjectMarshaler encoding fields with their values as strings.
+type stringFields []zapcore.Field
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (fs stringFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range fs {
+		if f.Type == zapcore.StringType {
+			enc.AddString(f.Key, f.String)
+			continue
+		}
+		m := zapcore.NewMapObjectEncoder()
+		f.AddTo(m)
+		enc.AddString(f.Key, fmt.Sprint(m.Fields[f.Key]))
+	}
+	return nil
+}
+
+// trimmedCallerEncoder encodes the caller as package/file:line, regardless of whether
+// the path uses forward or backward slashes.
+func trimmedCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	if !caller.Defined {
+		enc.AppendString("undefined")
+		return
+	}
+	enc.AppendString(fmt.Sprintf("%s:%d", trimCallerPath(caller.File), caller.Line))
+}
+
+// trimCallerPath keeps the last directory and the file name of path.
+func trimCallerPath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	idx := strings.LastIndexByte(path, '/')
+	if idx == -1 {
+		return path
+	}
+	idx = strings.LastIndexByte(path[:idx], '/')
+	if idx == -1 {
+		return path
+	}
+	return path[idx+1:]
+}
@@ -0,0 +1,189 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatConsole(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatConsole))
+
+	l.Info(context.Background(), "hello", "key", "value")
+
+	logs := sink.logs.String()
+	require.Contains(t, logs, "\tINFO\t", "console format should use capital levels")
+	require.Contains(t, logs, "\thello\t")
+	require.Contains(t, logs, `"key": "value"`)
+	require.NotContains(t, logs, "\x1b[", "non-terminal outputs should not be colored")
+}
+
+func TestFormatECS(t *testing.T) {
+	traceFn := func(_ context.Context) string { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatECS), logger.WithTraceID(traceFn))
+
+	l.With("component", "checkout").Error(context.Background(), "payment failed", "err", errors.New("card declined"))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.NotEmpty(t, entry["@timestamp"])
+	require.Equal(t, "error", entry["log.level"])
+	require.Equal(t, "payment failed", entry["message"])
+	require.Equal(t, "test-service", entry["service.name"])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace.id"])
+	require.Equal(t, "card declined", entry["error.message"])
+	require.Equal(t, "*errors.errorString", entry["error.type"])
+	require.Equal(t, "checkout", entry["component"])
+	require.Equal(t, "8.11.0", entry["ecs.version"])
+	require.Contains(t, entry["log.origin.file.name"], "format_test.go")
+	require.NotZero(t, entry["log.origin.file.line"])
+	for _, key := range []string{"ts", "level", "msg", "service", "trace_id", "err", "caller"} {
+		require.NotContains(t, entry, key)
+	}
+}
+
+func TestFormatGCP(t *testing.T) {
+	traceFn := func(_ context.Context) string { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+	l, sink := newMemoryLogger(t,
+		logger.WithFormat(logger.FormatGCP),
+		logger.WithGCPProject("my-project"),
+		logger.WithGCPLabels("region"),
+		logger.WithTraceID(traceFn),
+	)
+
+	l.With("region", "eu", "attempt", 2).Error(context.Background(), "payment failed", "component", "checkout")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.NotEmpty(t, entry["time"])
+	require.Equal(t, "ERROR", entry["severity"])
+	require.Equal(t, "payment failed", entry["message"])
+	require.Equal(t, "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736", entry["logging.googleapis.com/trace"])
+	require.Equal(t, map[string]interface{}{"service": "test-service", "region": "eu"}, entry["logging.googleapis.com/labels"])
+	location, ok := entry["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	require.True(t, ok)
+	require.Contains(t, location["file"], "format_test.go")
+	require.NotZero(t, location["line"])
+	require.Equal(t, "checkout", entry["component"])
+	require.EqualValues(t, 2, entry["attempt"])
+	for _, key := range []string{"ts", "level", "msg", "service", "trace_id", "region", "caller"} {
+		require.NotContains(t, entry, key)
+	}
+}
+
+func TestFormatGCPWithoutProject(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	traceFn := func(_ context.Context) string { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatGCP), logger.WithTraceID(traceFn))
+
+	l.Info(context.Background(), "ready")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "INFO", entries[0]["severity"])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["logging.googleapis.com/trace"])
+}
+
+func TestFormatDatadog(t *testing.T) {
+	traceFn := func(_ context.Context) string { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+	l, sink := newMemoryLogger(t,
+		logger.WithFormat(logger.FormatDatadog),
+		logger.WithDatadogTags("prod", "1.4.2"),
+		logger.WithTraceID(traceFn),
+	)
+
+	l.Error(context.Background(), "payment failed", "span_id", "00f067aa0ba902b7", "err", errors.New("card declined"))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.NotEmpty(t, entry["timestamp"])
+	require.Equal(t, "error", entry["status"])
+	require.Equal(t, "payment failed", entry["message"])
+	require.Equal(t, "11803532876627986230", entry["dd.trace_id"])
+	require.Equal(t, "67667974448284343", entry["dd.span_id"])
+	require.Equal(t, "test-service", entry["service"])
+	require.Equal(t, "test-service", entry["dd.service"])
+	require.Equal(t, "prod", entry["dd.env"])
+	require.Equal(t, "1.4.2", entry["dd.version"])
+	require.Equal(t, "card declined", entry["error.message"])
+	require.Equal(t, "*errors.errorString", entry["error.kind"])
+	for _, key := range []string{"ts", "level", "msg", "trace_id", "span_id", "err"} {
+		require.NotContains(t, entry, key)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := logger.New("test-service", logger.WithFormat("xml"))
+	require.ErrorContains(t, err, `unknown log format "xml"`)
+}
+
+func TestTrimCallerPath(t *testing.T) {
+	tests := map[string]string{
+		"/home/dev/app/internal/handler.go":    "internal/handler.go",
+		`C:\Users\dev\app\internal\handler.go`: "internal/handler.go",
+		`C:/Users/dev\app/internal\handler.go`: "internal/handler.go",
+		"app/handler.go":                       "app/handler.go",
+		"handler.go":                           "handler.go",
+	}
+	for path, want := range tests {
+		require.Equal(t, want, logger.TrimCallerPath(path), path)
+	}
+}
+
+func TestColorDisabledForNonTerminalOutputs(t *testing.T) {
+	require.False(t, logger.ColorEnabled(logger.ColorAuto, []string{"/var/log/app.log"}, noEnv))
+	require.False(t, logger.ColorEnabled(logger.ColorAuto, []string{"stdout", "/var/log/app.log"}, noEnv))
+	require.False(t, logger.ColorEnabled(logger.ColorAuto, nil, noEnv))
+}
+
+// noEnv is a getenv function for an empty environment.
+func noEnv(string) string { return "" }
+
+func TestColorEnvironmentConventions(t *testing.T) {
+	file := []string{"/var/log/app.log"}
+	tests := []struct {
+		name string
+		mode logger.ColorMode
+		env  map[string]string
+		want bool
+	}{
+		{name: "auto without env", mode: logger.ColorAuto, want: false},
+		{name: "NO_COLOR", mode: logger.ColorAuto, env: map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, want: false},
+		{name: "FORCE_COLOR", mode: logger.ColorAuto, env: map[string]string{"FORCE_COLOR": "1"}, want: true},
+		{name: "FORCE_COLOR=0", mode: logger.ColorAuto, env: map[string]string{"FORCE_COLOR": "0", "CLICOLOR_FORCE": "1"}, want: false},
+		{name: "CLICOLOR_FORCE", mode: logger.ColorAuto, env: map[string]string{"CLICOLOR_FORCE": "1"}, want: true},
+		{name: "CLICOLOR=0", mode: logger.ColorAuto, env: map[string]string{"CLICOLOR": "0"}, want: false},
+		{name: "always overrides NO_COLOR", mode: logger.ColorAlways, env: map[string]string{"NO_COLOR": "1"}, want: true},
+		{name: "never overrides FORCE_COLOR", mode: logger.ColorNever, env: map[string]string{"FORCE_COLOR": "1"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			require.Equal(t, tt.want, logger.ColorEnabled(tt.mode, file, getenv))
+		})
+	}
+}
+
+func TestWithColorAlways(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatConsole), logger.WithColor(logger.ColorAlways))
+
+	l.Info(context.Background(), "colored")
+
+	require.Contains(t, sink.logs.String(), "\x1b[", "forced colors should emit escape codes")
+}
+
+func TestNoColorEnvironment(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("FORCE_COLOR", "")
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatConsole))
+
+	l.Info(context.Background(), "plain")
+
+	require.NotContains(t, sink.logs.String(), "\x1b[")
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// The special fields of Cloud Logging's structured logs.
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanKey           = "logging.googleapis.com/spanId"
+	gcpTraceSampledKey   = "logging.googleapis.com/trace_sampled"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	gcpLabelsKey         = "logging.googleapis.com/labels"
+)
+
+// gcpSeverities maps levels to Cloud Logging severities.
+var gcpSeverities = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "DEBUG",
+	zapcore.InfoLevel:   "INFO",
+	zapcore.WarnLevel:   "WARNING",
+	zapcore.ErrorLevel:  "ERROR",
+	zapcore.DPanicLevel: "CRITICAL",
+	zapcore.PanicLevel:  "ALERT",
+	zapcore.FatalLevel:  "EMERGENCY",
+}
+
+// WithGCPProject sets the Google Cloud project that FormatGCP qualifies trace IDs with, as
+// projects/<project>/traces/<trace ID>, so that Cloud Logging links the entries to their
+// trace. It defaults to the GOOGLE_CLOUD_PROJECT environment variable; without a project,
+// trace IDs are written as is.
+func WithGCPProject(project string) Option {
+	return func(l *Logger) {
+		l.gcpProject = project
+	}
+}
+
+// WithGCPLabels moves the fields with the given keys into the labels of the entries written
+// in FormatGCP, which Cloud Logging indexes for filtering. The service field is always a label.
+// Label values are written as strings.
+func WithGCPLabels(keys ...string) Option {
+	return func(l *Logger) {
+		l.gcpLabels = append(l.gcpLabels, keys...)
+	}
+}
+
+// applyGCP configures the keys of the entry's level, time and message for FormatGCP. The
+// caller is added as the sourceLocation field by gcpEntryFields instead.
+func applyGCP(enc *zapcore.EncoderConfig) {
+	enc.TimeKey = "time"
+	enc.LevelKey = "severity"
+	enc.MessageKey = "message"
+	enc.CallerKey = zapcore.OmitKey
+	enc.FunctionKey = zapcore.OmitKey
+	enc.StacktraceKey = "stack_trace"
+	enc.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	enc.EncodeLevel = func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		severity, ok := gcpSeverities[level]
+		if !ok {
+			severity = "DEFAULT"
+		}
+		enc.AppendString(severity)
+	}
+}
+
+// gcpCore wraps core so that the trace, span, labels and caller fields follow Cloud Logging's
+// conventions.
+func (l *Logger) gcpCore(core zapcore.Core) zapcore.Core {
+	project := l.gcpProject
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	labels := map[string]bool{"service": true}
+	for _, key := range l.gcpLabels {
+		labels[key] = true
+	}
+
+	return &fieldMapCore{
+		Core: core,
+		field: func(f zapcore.Field) []zapcore.Field {
+			switch {
+			case f.Key == "trace_id" && f.Type == zapcore.StringType:
+				trace := f.String
+				if project != "" {
+					trace = "projects/" + project + "/traces/" + trace
+				}
+				return []zapcore.Field{zap.String(gcpTraceKey, trace)}
+			case f.Key == spanIDKey:
+				f.Key = gcpSpanKey
+			case f.Key == traceSampledKey:
+				f.Key = gcpTraceSampledKey
+			}
+			return []zapcore.Field{f}
+		},
+		entry:     gcpEntryFields,
+		label:     func(f zapcore.Field) bool { return labels[f.Key] },
+		labelsKey: gcpLabelsKey,
+	}
+}
+
+// gcpEntryFields returns the sourceLocation field describing the caller.
+func gcpEntryFields(ent zapcore.Entry) []zapcore.Field {
+	if !ent.Caller.Defined {
+		return nil
+	}
+	return []zapcore.Field{zap.Object(gcpSourceLocationKey, gcpSourceLocation(ent.Caller))}
+}
+
+// gcpSourceLocation is the sourceLocation field of an entry.
+type gcpSourceLocation zapcore.EntryCaller
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (c gcpSourceLocation) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("file", trimCallerPath(c.File))
+	enc.AddInt("line", c.Line)
+	if c.Function != "" {
+		enc.AddString("function", c.Function)
+	}
+	return nil
+}
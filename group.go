@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldGroup is a group opened through WithGroup, holding the key-value pairs added to it
+// through With.
+type fieldGroup struct {
+	name    string
+	keyVals []interface{}
+	parent  *fieldGroup
+}
+
+// Group returns a field nesting key-value pairs, and strongly typed zap fields, under key, as
+// a JSON object:
+//
+//	l.Info(ctx, "request", logger.Group("http", "method", r.Method, "status", 200))
+//
+// writes "http":{"method":"GET","status":200}. Pairs with a missing value or a non-string key
+// are dropped.
+func Group(key string, keyVals ...interface{}) zap.Field {
+	return zap.Object(key, keyValsObject(keyVals))
+}
+
+// WithGroup returns a child Logger nesting the key-value pairs of its logging calls and of
+// With under name, as slog's groups do, so that related fields do not pollute the top level:
+//
+//	httpLog := l.WithGroup("http").With("method", r.Method)
+//	httpLog.Info(ctx, "request", "status", 200) // "http":{"method":"GET","status":200}
+//
+// Groups nest when WithGroup is called again. The fields inherited from the parent, those
+// derived from the context, such as trace_id, errors and markers such as Privacy stay at the
+// top level, and a group without fields is omitted. The fields of a group are encoded for every
+// entry instead of once per With. An empty name returns l.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	child := *l
+	child.group = &fieldGroup{name: name, parent: l.group}
+	return &child
+}
+
+// without returns a copy of g and its parents without the fields whose keys are in drop.
+func (g *fieldGroup) without(drop map[string]struct{}) *fieldGroup {
+	if g == nil {
+		return nil
+	}
+	return &fieldGroup{name: g.name, keyVals: dropKeyVals(g.keyVals, drop), parent: g.parent.without(drop)}
+}
+
+// groupKeyVals nests keyVals, and the pairs added through With, under the groups of l. The
+// markers read by the cores, such as Privacy, and errors, which the error fingerprint and
+// LastError look for, stay at the top level.
+func (l *Logger) groupKeyVals(keyVals []interface{}) []interface{} {
+	if l.group == nil {
+		return keyVals
+	}
+	var top []interface{}
+	for g := l.group; g != nil; g = g.parent {
+		var nested []interface{}
+		for _, f := range splitKeyVals(append(g.keyVals[:len(g.keyVals):len(g.keyVals)], keyVals...)) {
+			if isTopLevel(f) {
+				top = append(top, f.items...)
+			} else {
+				nested = append(nested, f.items...)
+			}
+		}
+		keyVals = nil
+		if len(nested) > 0 {
+			keyVals = []interface{}{Group(g.name, nested...)}
+		}
+	}
+	return append(keyVals, top...)
+}
+
+// isTopLevel reports whether f is kept out of groups: a marker field or an error.
+func isTopLevel(f keyVal) bool {
+	switch len(f.items) {
+	case 1:
+		field, ok := f.items[0].(zapcore.Field)
+		return ok && (field.Type == zapcore.SkipType || field.Type == zapcore.ErrorType)
+	case 2:
+		_, ok := f.items[1].(error)
+		return ok
+	default:
+		return false
+	}
+}
+
+// keyValsObject encodes key-value pairs, and strongly typed zap fields, as an object.
+type keyValsObject []interface{}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (kv keyValsObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range splitKeyVals(kv) {
+		switch len(f.items) {
+		case 1:
+			if field, ok := f.items[0].(zapcore.Field); ok {
+				field.AddTo(enc)
+			}
+		case 2:
+			if f.key != "" {
+				zap.Any(f.key, f.items[1]).AddTo(enc)
+			}
+		}
+	}
+	return nil
+}
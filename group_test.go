@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithGroup(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	ctx := context.WithValue(context.Background(), traceKey{}, "abc123")
+
+	httpLog := l.With("component", "api").WithGroup("http").With("method", "GET")
+	httpLog.WithGroup("response").With("status", 200).Info(ctx, "request", "bytes", 512, zap.Bool("cached", true))
+	httpLog.Info(ctx, "started")
+	l.WithGroup("empty").Info(ctx, "no fields")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 3)
+	require.Equal(t, "api", entries[0]["component"])
+	require.Equal(t, "abc123", entries[0]["trace_id"], "context fields should stay at the top level")
+	require.Equal(t, map[string]any{
+		"method":   "GET",
+		"response": map[string]any{"status": 200.0, "bytes": 512.0, "cached": true},
+	}, entries[0]["http"])
+	require.Equal(t, map[string]any{"method": "GET"}, entries[1]["http"])
+	require.NotContains(t, entries[2], "empty", "groups without fields should be omitted")
+}
+
+func TestGroup(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRedactKeys("token"))
+
+	l.Info(context.Background(), "login", logger.Group("auth", "user", "alice", "token", "secret", 42, "ignored"))
+
+	require.Equal(t, map[string]any{"user": "alice", "token": "[REDACTED]"}, decodeLines(t, sink)[0]["auth"])
+}
+
+func TestWithGroupTopLevelFields(t *testing.T) {
+	public, publicLogs := observer.New(zapcore.InfoLevel)
+	l, sink := newMemoryLogger(t,
+		logger.WithClearance(logger.Public, public),
+		logger.WithErrorFingerprint(),
+		logger.WithHashFields([]byte("key"), "user_id"),
+		logger.WithRedactKeys("token"),
+	)
+	ctx := logger.ContextWithLastError(context.Background())
+
+	grouped := l.WithGroup("auth").With(logger.Privacy(logger.Restricted))
+	grouped.Error(ctx, "login failed", "user_id", "alice", "token", "secret", "err", errors.New("denied"))
+
+	require.Zero(t, publicLogs.Len(), "the Privacy marker should stay at the top level")
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "denied", entry["err"], "errors should stay at the top level")
+	require.NotEmpty(t, entry["error_fingerprint"])
+	last, ok := l.LastError(ctx)
+	require.True(t, ok)
+	require.Equal(t, last.Fingerprint, entry["error_fingerprint"])
+	auth := entry["auth"].(map[string]any)
+	require.Equal(t, "[REDACTED]", auth["token"])
+	require.Len(t, auth["user_id"], 32, "nested keys should be hashed")
+	require.NotContains(t, sink.logs.String(), "alice")
+}
+
+func TestWithoutGroup(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	grouped := l.With("tenant", "acme").WithGroup("http").With("method", "GET", "tenant", "acme")
+	grouped.Without("tenant").Info(context.Background(), "request")
+	grouped.WithReplace("method", "POST").Info(context.Background(), "replaced")
+
+	entries := decodeLines(t, sink)
+	require.NotContains(t, entries[0], "tenant")
+	require.Equal(t, map[string]any{"method": "GET"}, entries[0]["http"])
+	require.Equal(t, map[string]any{"method": "POST", "tenant": "acme"}, entries[1]["http"])
+}
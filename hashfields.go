@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// hashedBytes is the number of HMAC bytes kept in pseudonymized values.
+const hashedBytes = 16
+
+// WithHashFields replaces the values of the fields with the given keys, also in nested objects,
+// by a keyed HMAC-SHA256 hash, hex encoded and truncated to 128 bits. Entries about the same user
+// remain correlatable through the hash, without the raw identifier being stored.
+// The key must be kept secret and stable; rotating it breaks correlation with older entries.
+func WithHashFields(key []byte, fields ...string) Option {
+	return func(l *Logger) {
+		l.hashKey = key
+		l.hashFields = append(l.hashFields, fields...)
+	}
+}
+
+// hashTransform returns a fieldTransform pseudonymizing the values of the given keys, matched
+// as passed or as normalized by n, including the keys of nested objects, such as groups.
+func hashTransform(key []byte, keys []string, n *keyNormalizer) fieldTransform {
+	hashed := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		hashed[k] = struct{}{}
+	}
+	matches := func(k string) bool {
+		if _, ok := hashed[k]; ok {
+			return true
+		}
+		_, ok := hashed[n.normalize(k)]
+		return ok
+	}
+	hash := func(s string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(s))
+		return hex.EncodeToString(mac.Sum(nil)[:hashedBytes])
+	}
+	hashNested := func(v interface{}) interface{} {
+		return walkValue(v, func(k string, value interface{}) interface{} {
+			if matches(k) {
+				return hash(fmt.Sprint(value))
+			}
+			return value
+		})
+	}
+
+	return func(f zapcore.Field) zapcore.Field {
+		if !matches(f.Key) {
+			return mapNested(f, hashNested)
+		}
+		return zap.String(f.Key, hash(fieldString(f)))
+	}
+}
+
+// fieldString returns the string representation of a field's value.
+func fieldString(f zapcore.Field) string {
+	if f.Type == zapcore.StringType {
+		return f.String
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return fmt.Sprint(enc.Fields[f.Key])
+}
@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithHashFields(t *testing.T) {
+	key := []byte("secret")
+	l, sink := newMemoryLogger(t, logger.WithHashFields(key, "user_id", "email"))
+	ctx := context.Background()
+
+	l.With("email", "jane@example.com").Info(ctx, "login", "user_id", 42)
+	l.Info(ctx, "logout", zap.Int("user_id", 42), "plan", "pro")
+	l.Info(ctx, "login", "user_id", 43)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 3)
+
+	hash := entries[0]["user_id"].(string)
+	require.Len(t, hash, 32)
+	require.Equal(t, hash, entries[1]["user_id"], "the same value should hash the same, whatever its type")
+	require.NotEqual(t, hash, entries[2]["user_id"])
+	require.NotEqual(t, "jane@example.com", entries[0]["email"], "inherited fields should be hashed")
+	require.Equal(t, "pro", entries[1]["plan"])
+	require.NotContains(t, sink.logs.String(), "jane@example.com")
+}
+
+func TestWithHashFieldsDependsOnKey(t *testing.T) {
+	a, sinkA := newMemoryLogger(t, logger.WithHashFields([]byte("key-a"), "user_id"))
+	b, sinkB := newMemoryLogger(t, logger.WithHashFields([]byte("key-b"), "user_id"))
+
+	a.Info(context.Background(), "x", "user_id", "u1")
+	b.Info(context.Background(), "x", "user_id", "u1")
+
+	require.NotEqual(t, decodeLines(t, sinkA)[0]["user_id"], decodeLines(t, sinkB)[0]["user_id"])
+}
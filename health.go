@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// WithStartupCheck makes New fail when Ping fails within timeout, so that a service with
+// an unwritable log file or an unreachable collector fails fast at startup instead of
+// silently dropping its entries.
+func WithStartupCheck(timeout time.Duration) Option {
+	return func(l *Logger) {
+		l.startupCheck = timeout
+	}
+}
+
+// HealthCheck checks that every output path and every core added through WithCore can accept
+// entries. The results are keyed by output path; cores are keyed by their type, as in Stats,
+// and only checked when they implement HealthChecker. A nil error marks a healthy sink.
+// Files are checked by opening them for writing, stdout and stderr are always healthy, and
+// sinks created through a SinkFactory are checked when they implement HealthChecker, such as
+// the network sinks under sinks/. BatchingSink and SpoolingSink forward the check to the sink
+// they wrap.
+func (l *Logger) HealthCheck(ctx context.Context) map[string]error {
+	results := make(map[string]error)
+	if l.existing == nil {
+		for _, path := range l.outputPaths {
+			results[path] = l.checkOutput(ctx, path)
+		}
+	}
+	for _, r := range l.cores {
+		if hc, ok := r.core.(HealthChecker); ok {
+			results[sinkName(r.core)] = hc.Health(ctx)
+		}
+	}
+	return results
+}
+
+// Ping checks every output path and core like HealthCheck, and returns the failures joined,
+// each prefixed with its output path or core.
+func (l *Logger) Ping(ctx context.Context) error {
+	results := l.HealthCheck(ctx)
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var err error
+	for _, name := range names {
+		if results[name] != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", name, results[name]))
+		}
+	}
+	return err
+}
+
+// checkOutput checks that the output path can accept entries.
+func (l *Logger) checkOutput(ctx context.Context, path string) error {
+	if sink, ok := l.sinks[path]; ok {
+		if hc, ok := sink.(HealthChecker); ok {
+			return hc.Health(ctx)
+		}
+		return nil
+	}
+	name, ok := outputFile(path)
+	if !ok {
+		return nil
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// checkedSink is a memorySink implementing logger.HealthChecker.
+type checkedSink struct {
+	memorySink
+	err error
+}
+
+// Health returns the configured error.
+func (s *checkedSink) Health(_ context.Context) error {
+	return s.err
+}
+
+func TestHealthCheck(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	sink := &checkedSink{}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) {
+		return logger.BatchingSink(sink, 0, time.Hour), nil
+	})
+	remote := scheme + "://collector"
+
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{"stdout", file, remote}))
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.Equal(t, map[string]error{"stdout": nil, file: nil, remote: nil}, l.HealthCheck(ctx))
+	require.NoError(t, l.Ping(ctx))
+
+	sink.err = errors.New("unreachable")
+	require.NoError(t, os.Remove(file))
+	results := l.HealthCheck(ctx)
+	require.ErrorIs(t, results[file], os.ErrNotExist)
+	require.ErrorIs(t, results[remote], sink.err, "BatchingSink should forward the check")
+
+	err = l.Ping(ctx)
+	require.ErrorContains(t, err, remote+": unreachable")
+	require.ErrorContains(t, err, file+": ")
+}
+
+func TestWithStartupCheck(t *testing.T) {
+	sink := &checkedSink{err: errors.New("invalid token")}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+
+	_, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{scheme + "://"}),
+		logger.WithStartupCheck(time.Second),
+	)
+	require.ErrorContains(t, err, "invalid token")
+
+	sink.err = nil
+	_, err = logger.New("test-service",
+		logger.WithOutputPaths([]string{scheme + "://"}),
+		logger.WithStartupCheck(time.Second),
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Hook is a callback invoked for every emitted entry with the entry and all of its fields,
+// including the fields inherited through With. Errors returned by a hook are reported
+// through zap's error output and do not prevent the entry from being written.
+type Hook func(entry zapcore.Entry, fields []zapcore.Field) error
+
+// ContextHook is a Hook that also receives the context the entry was logged with, for example
+// to attach the entry to the span the context carries. Entries that were not logged through
+// the Logger's methods, such as the summaries of WithDedup, get a background context.
+type ContextHook func(ctx context.Context, entry zapcore.Entry, fields []zapcore.Field) error
+
+// WriteErrorHook is a callback invoked when writing an entry to the configured outputs fails.
+type WriteErrorHook func(entry zapcore.Entry, fields []zapcore.Field, err error)
+
+// WithHook registers hooks that are called for every emitted entry, for example to increment
+// metrics or forward Error-level entries to an alerting channel.
+func WithHook(hooks ...Hook) Option {
+	return func(l *Logger) {
+		l.hooks = append(l.hooks, hooks...)
+	}
+}
+
+// WithContextHook registers hooks that are called for every emitted entry, like those
+// registered through WithHook, with the context the entry was logged with.
+func WithContextHook(hooks ...ContextHook) Option {
+	return func(l *Logger) {
+		l.contextHooks = append(l.contextHooks, hooks...)
+	}
+}
+
+// contextKeyName is the key of the marker field carrying the context of an entry to the
+// ContextHooks. The field is never encoded.
+const contextKeyName = "context"
+
+// contextField returns the marker field carrying ctx.
+func contextField(ctx context.Context) zapcore.Field {
+	return zapcore.Field{Key: contextKeyName, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// contextOf returns the context carried by the marker field among fields, or a background
+// context.
+func contextOf(fields []zapcore.Field) context.Context {
+	for _, f := range fields {
+		if ctx, ok := f.Interface.(context.Context); ok && f.Key == contextKeyName && f.Type == zapcore.SkipType {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// WithWriteErrorHook registers hooks that are called when an entry could not be written,
+// for example to count write failures. Failing Hooks do not trigger them.
+func WithWriteErrorHook(hooks ...WriteErrorHook) Option {
+	return func(l *Logger) {
+		l.writeErrorHooks = append(l.writeErrorHooks, hooks...)
+	}
+}
+
+// hookCore is a zapcore.Core that calls hooks after each entry has been written.
+type hookCore struct {
+	zapcore.Core
+	hooks           []Hook
+	contextHooks    []ContextHook
+	writeErrorHooks []WriteErrorHook
+	fields          []zapcore.Field
+}
+
+// newHookCore wraps core so that hooks are called for every written entry.
+func newHookCore(core zapcore.Core, hooks []Hook, contextHooks []ContextHook, writeErrorHooks []WriteErrorHook) zapcore.Core {
+	return &hookCore{Core: core, hooks: hooks, contextHooks: contextHooks, writeErrorHooks: writeErrorHooks}
+}
+
+// With implements zapcore.Core, keeping track of the context fields for the hooks.
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		Core:            c.Core.With(fields),
+		hooks:           c.hooks,
+		contextHooks:    c.contextHooks,
+		writeErrorHooks: c.writeErrorHooks,
+		fields:          append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	if err != nil {
+		for _, hook := range c.writeErrorHooks {
+			hook(ent, all, err)
+		}
+	}
+	for _, hook := range c.hooks {
+		err = errors.Join(err, hook(ent, all))
+	}
+	if len(c.contextHooks) > 0 {
+		ctx := contextOf(fields)
+		for _, hook := range c.contextHooks {
+			err = errors.Join(err, hook(ctx, ent, all))
+		}
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithHook(t *testing.T) {
+	var (
+		levels []zapcore.Level
+		fields []map[string]interface{}
+	)
+	hook := func(ent zapcore.Entry, fs []zapcore.Field) error {
+		levels = append(levels, ent.Level)
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fs {
+			f.AddTo(enc)
+		}
+		fields = append(fields, enc.Fields)
+		return nil
+	}
+	l, sink := newMemoryLogger(t, logger.WithHook(hook), logger.WithErrorFingerprint())
+	ctx := context.Background()
+
+	l.With("component", "billing").Info(ctx, "charged", "amount", 10)
+	l.Error(ctx, "charge failed")
+	l.Debug(ctx, "not emitted")
+
+	require.Equal(t, []zapcore.Level{zapcore.InfoLevel, zapcore.ErrorLevel}, levels, "hooks should only see emitted entries")
+	require.Equal(t, "billing", fields[0]["component"], "hooks should see inherited fields")
+	require.Equal(t, "test-service", fields[0]["service"], "hooks should see initial fields")
+	require.Equal(t, int64(10), fields[0]["amount"], "hooks should see call fields")
+	require.Contains(t, fields[1], "error_fingerprint", "hooks should see fields added by the logger")
+	require.Contains(t, sink.logs.String(), `"msg":"charged"`)
+}
+
+func TestWithHookErrorDoesNotDropEntry(t *testing.T) {
+	hook := func(zapcore.Entry, []zapcore.Field) error { return errors.New("hook failed") }
+	l, sink := newMemoryLogger(t, logger.WithHook(hook))
+
+	l.Info(context.Background(), "still written")
+
+	require.Contains(t, sink.logs.String(), `"msg":"still written"`)
+}
+
+// requestKey is a context key used by the tests.
+type requestKey struct{}
+
+func TestWithContextHook(t *testing.T) {
+	var requests []interface{}
+	hook := func(ctx context.Context, ent zapcore.Entry, _ []zapcore.Field) error {
+		requests = append(requests, ctx.Value(requestKey{}))
+		return nil
+	}
+	l, sink := newMemoryLogger(t, logger.WithContextHook(hook))
+
+	l.Info(context.WithValue(context.Background(), requestKey{}, "r-1"), "charged")
+	l.Info(context.Background(), "idle")
+
+	require.Equal(t, []interface{}{"r-1", nil}, requests)
+	require.NotContains(t, sink.logs.String(), `"context"`, "the context should not be encoded")
+}
+
+// failingSink is a zap.Sink whose writes always fail.
+type failingSink struct{ memorySink }
+
+// Write implements io.Writer.
+func (*failingSink) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWithWriteErrorHook(t *testing.T) {
+	var writeErrs []error
+	hook := func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErrs = append(writeErrs, err) }
+
+	sink := &failingSink{}
+	scheme := registerSink(t, sink)
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{scheme + "://"}), logger.WithWriteErrorHook(hook))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "lost")
+
+	require.Len(t, writeErrs, 1)
+	require.ErrorContains(t, writeErrs[0], "disk full")
+}
@@ -0,0 +1,149 @@
+// Package joblog adapts a Logger to the logger interfaces of background job frameworks, so that
+// their messages about the lifecycle of tasks, such as retries and failures, are written as
+// structured entries. It has no dependencies on the frameworks: Asynq implements asynq's Logger
+// and Machinery the LoggerInterface used by machinery's log package.
+//
+// With github.com/hibiken/asynq:
+//
+//	srv := asynq.NewServer(redisOpt, asynq.Config{Logger: joblog.NewAsynq(log)})
+//
+// With github.com/RichardKnop/machinery, which logs through one logger per level:
+//
+//	machinerylog.SetInfo(joblog.NewMachinery(log, zapcore.InfoLevel))
+//	machinerylog.SetWarning(joblog.NewMachinery(log, zapcore.WarnLevel))
+//	machinerylog.SetError(joblog.NewMachinery(log, zapcore.ErrorLevel))
+//
+// The frameworks write the IDs of tasks into their messages, such as "Retry exhausted for task
+// id=..." and "Task ... failed. Going to retry in 5 seconds.", so the ID is also written as the
+// task_id field. The frameworks pass no context, so the entries carry no trace ID.
+package joblog
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// taskIDKey is the key of the field holding the ID of the task a message is about.
+const taskIDKey = "task_id"
+
+// taskIDPattern matches the task IDs in the messages of asynq and machinery.
+var taskIDPattern = regexp.MustCompile(`(?i)\btask (?:id=)?([\w-]+)`)
+
+// log writes msg at level through l, with the task ID it mentions, if any, reporting the caller
+// of the method calling it.
+func log(l *logger.Logger, level zapcore.Level, msg string) {
+	msg = strings.TrimRight(msg, "\r\n")
+	var keyVals []interface{}
+	if id := taskID(msg); id != "" {
+		keyVals = []interface{}{taskIDKey, id}
+	}
+	l.LogDepth(context.Background(), 2, level, msg, keyVals...)
+}
+
+// taskID returns the task ID mentioned in msg, or an empty string. IDs are generated, so words
+// without digits following "task" are not IDs.
+func taskID(msg string) string {
+	m := taskIDPattern.FindStringSubmatch(msg)
+	if m == nil || !strings.ContainsAny(m[1], "0123456789") {
+		return ""
+	}
+	return m[1]
+}
+
+// Asynq is an asynq Logger writing through a Logger.
+type Asynq struct {
+	l *logger.Logger
+}
+
+// NewAsynq returns an asynq Logger writing through l.
+func NewAsynq(l *logger.Logger) *Asynq {
+	return &Asynq{l: l}
+}
+
+// Debug implements asynq's Logger.
+func (a *Asynq) Debug(args ...interface{}) {
+	log(a.l, zapcore.DebugLevel, fmt.Sprint(args...))
+}
+
+// Info implements asynq's Logger.
+func (a *Asynq) Info(args ...interface{}) {
+	log(a.l, zapcore.InfoLevel, fmt.Sprint(args...))
+}
+
+// Warn implements asynq's Logger.
+func (a *Asynq) Warn(args ...interface{}) {
+	log(a.l, zapcore.WarnLevel, fmt.Sprint(args...))
+}
+
+// Error implements asynq's Logger.
+func (a *Asynq) Error(args ...interface{}) {
+	log(a.l, zapcore.ErrorLevel, fmt.Sprint(args...))
+}
+
+// Fatal implements asynq's Logger, exiting after the entry has been written.
+func (a *Asynq) Fatal(args ...interface{}) {
+	log(a.l, zapcore.FatalLevel, fmt.Sprint(args...))
+}
+
+// Machinery is a machinery LoggerInterface writing the messages it receives at a fixed level.
+// Its Fatal and Panic methods write at FatalLevel and PanicLevel, so they exit and panic after
+// the entry has been written.
+type Machinery struct {
+	l     *logger.Logger
+	level zapcore.Level
+}
+
+// NewMachinery returns a machinery LoggerInterface writing through l at level.
+func NewMachinery(l *logger.Logger, level zapcore.Level) *Machinery {
+	return &Machinery{l: l, level: level}
+}
+
+// Print writes a message formatted as by fmt.Print.
+func (m *Machinery) Print(v ...interface{}) {
+	log(m.l, m.level, fmt.Sprint(v...))
+}
+
+// Printf writes a message formatted as by fmt.Printf.
+func (m *Machinery) Printf(format string, v ...interface{}) {
+	log(m.l, m.level, fmt.Sprintf(format, v...))
+}
+
+// Println writes a message formatted as by fmt.Println.
+func (m *Machinery) Println(v ...interface{}) {
+	log(m.l, m.level, fmt.Sprintln(v...))
+}
+
+// Fatal writes a message formatted as by fmt.Print at FatalLevel.
+func (m *Machinery) Fatal(v ...interface{}) {
+	log(m.l, zapcore.FatalLevel, fmt.Sprint(v...))
+}
+
+// Fatalf writes a message formatted as by fmt.Printf at FatalLevel.
+func (m *Machinery) Fatalf(format string, v ...interface{}) {
+	log(m.l, zapcore.FatalLevel, fmt.Sprintf(format, v...))
+}
+
+// Fatalln writes a message formatted as by fmt.Println at FatalLevel.
+func (m *Machinery) Fatalln(v ...interface{}) {
+	log(m.l, zapcore.FatalLevel, fmt.Sprintln(v...))
+}
+
+// Panic writes a message formatted as by fmt.Print at PanicLevel.
+func (m *Machinery) Panic(v ...interface{}) {
+	log(m.l, zapcore.PanicLevel, fmt.Sprint(v...))
+}
+
+// Panicf writes a message formatted as by fmt.Printf at PanicLevel.
+func (m *Machinery) Panicf(format string, v ...interface{}) {
+	log(m.l, zapcore.PanicLevel, fmt.Sprintf(format, v...))
+}
+
+// Panicln writes a message formatted as by fmt.Println at PanicLevel.
+func (m *Machinery) Panicln(v ...interface{}) {
+	log(m.l, zapcore.PanicLevel, fmt.Sprintln(v...))
+}
@@ -0,0 +1,72 @@
+package joblog_test
+
+import (
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/v2/joblog"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// asynqLogger mirrors the Logger interface of github.com/hibiken/asynq.
+type asynqLogger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+	Fatal(args ...interface{})
+}
+
+// machineryLogger mirrors the LoggerInterface of github.com/RichardKnop/logging, used by
+// machinery's log package.
+type machineryLogger interface {
+	Print(...interface{})
+	Printf(string, ...interface{})
+	Println(...interface{})
+	Fatal(...interface{})
+	Fatalf(string, ...interface{})
+	Fatalln(...interface{})
+	Panic(...interface{})
+	Panicf(string, ...interface{})
+	Panicln(...interface{})
+}
+
+var (
+	_ asynqLogger     = (*joblog.Asynq)(nil)
+	_ machineryLogger = (*joblog.Machinery)(nil)
+)
+
+func TestAsynq(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	a := joblog.NewAsynq(l)
+
+	a.Warn("Retry exhausted for task id=5f0e8c2a-9d3b-4f7e-8a1c-2b6d4e9f0a13")
+	a.Info("Starting processing")
+	a.Error("Failed to forward scheduled tasks: connection refused")
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "Retry exhausted",
+		"task_id", "5f0e8c2a-9d3b-4f7e-8a1c-2b6d4e9f0a13")
+	for _, e := range entries.All()[1:] {
+		require.NotContains(t, e.ContextMap(), "task_id", e.Message)
+	}
+	for _, e := range entries.All() {
+		require.Regexp(t, `/joblog_test\.go:\d+$`, e.Caller.String())
+	}
+}
+
+func TestMachinery(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	warn := joblog.NewMachinery(l, zapcore.WarnLevel)
+
+	warn.Printf("Task %s failed. Going to retry in %d seconds.", "task_0c2b6d4e-9f0a", 5)
+	warn.Println("Signal received: interrupt")
+	require.Panics(t, func() { joblog.NewMachinery(l, zapcore.InfoLevel).Panicf("broker %s gone", "amqp") })
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "Going to retry", "task_id", "task_0c2b6d4e-9f0a")
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "Signal received: interrupt")
+	loggertest.AssertLogged(t, entries, zapcore.PanicLevel, "broker amqp gone")
+	for _, e := range entries.All() {
+		require.Regexp(t, `/joblog_test\.go:\d+$`, e.Caller.String())
+	}
+}
@@ -0,0 +1,58 @@
+// Package kafkalog adapts a Logger to the logger interfaces of the Kafka clients, so that
+// their internal messages, such as rebalances and broker errors, are written as structured
+// entries instead of to stdout. It has no dependencies on the clients: Logger implements
+// sarama's StdLogger and kafka-go's Logger interfaces.
+//
+// With github.com/IBM/sarama:
+//
+//	sarama.Logger = kafkalog.New(log.ForLibrary("sarama", zapcore.InfoLevel), zapcore.InfoLevel)
+//
+// With github.com/segmentio/kafka-go:
+//
+//	kafka.NewReader(kafka.ReaderConfig{
+//		Logger:      kafkalog.New(log, zapcore.DebugLevel),
+//		ErrorLogger: kafkalog.New(log, zapcore.ErrorLevel),
+//	})
+package kafkalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger writes the messages of a Kafka client as entries at a fixed level. The clients' log
+// calls carry no context, so the entries carry no trace ID.
+type Logger struct {
+	l     *logger.Logger
+	level zapcore.Level
+}
+
+// New returns a Logger writing the messages it receives through l at level.
+func New(l *logger.Logger, level zapcore.Level) *Logger {
+	return &Logger{l: l, level: level}
+}
+
+// Print writes a message formatted as by fmt.Print.
+func (k *Logger) Print(v ...interface{}) {
+	k.log(fmt.Sprint(v...))
+}
+
+// Printf writes a message formatted as by fmt.Printf.
+func (k *Logger) Printf(format string, v ...interface{}) {
+	k.log(fmt.Sprintf(format, v...))
+}
+
+// Println writes a message formatted as by fmt.Println.
+func (k *Logger) Println(v ...interface{}) {
+	k.log(fmt.Sprintln(v...))
+}
+
+// log writes msg without the trailing newlines the clients add to their messages, reporting the
+// caller of the method calling it.
+func (k *Logger) log(msg string) {
+	k.l.LogDepth(context.Background(), 2, k.level, strings.TrimRight(msg, "\r\n"))
+}
@@ -0,0 +1,49 @@
+package kafkalog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/v2/kafkalog"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// saramaStdLogger mirrors the StdLogger interface of github.com/IBM/sarama.
+type saramaStdLogger interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+// kafkaGoLogger mirrors the Logger interface of github.com/segmentio/kafka-go.
+type kafkaGoLogger interface {
+	Printf(format string, v ...interface{})
+}
+
+var (
+	_ saramaStdLogger = (*kafkalog.Logger)(nil)
+	_ kafkaGoLogger   = (*kafkalog.Logger)(nil)
+)
+
+func TestLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	info := kafkalog.New(l, zapcore.InfoLevel)
+	errs := kafkalog.New(l, zapcore.ErrorLevel)
+
+	info.Printf("consumer/broker/%d added subscription to %s/%d\n", 1, "orders", 0)
+	info.Println("client/metadata fetching metadata for all topics from broker", "kafka-1:9092")
+	errs.Print("error reading from partition: ", errors.New("broker not available"))
+
+	all := entries.All()
+	require.Len(t, all, 3)
+	require.Equal(t, "consumer/broker/1 added subscription to orders/0", all[0].Message)
+	require.Equal(t, zapcore.InfoLevel, all[0].Level)
+	require.Equal(t, "client/metadata fetching metadata for all topics from broker kafka-1:9092", all[1].Message)
+	require.Equal(t, "error reading from partition: broker not available", all[2].Message)
+	require.Equal(t, zapcore.ErrorLevel, all[2].Level)
+	for _, e := range all {
+		require.Regexp(t, `/kafkalog_test\.go:\d+$`, e.Caller.String(), "the client calling the logger should be the caller")
+	}
+}
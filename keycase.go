@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// KeyCase selects the casing of field keys, see WithKeyNormalization.
+type KeyCase int
+
+const (
+	// KeyCaseAsIs writes keys as they are passed. This is the default.
+	KeyCaseAsIs KeyCase = iota
+	// SnakeCase writes keys in snake_case, e.g. userID and user-id become user_id.
+	SnakeCase
+	// CamelCase writes keys in camelCase, e.g. user_id and UserID become userId.
+	CamelCase
+)
+
+// maxCachedKeys bounds the number of key conversions cached by a keyNormalizer, so that
+// keys built from unbounded values do not grow the cache forever.
+const maxCachedKeys = 1024
+
+// WithKeyNormalization rewrites the keys of the fields to a consistent casing before they are
+// encoded, so that code written by different teams produces a uniform schema. Words are split
+// at underscores, hyphens, spaces and case changes, keeping acronyms together, and the
+// segments of dotted keys are normalized separately. The keys of the fields added by the Logger
+// itself, service and the trace context fields, are kept; see WithTraceIDKey to rename the
+// trace ID. WithRedactKeys and WithHashFields match both the keys as passed and the normalized
+// keys.
+func WithKeyNormalization(keyCase KeyCase) Option {
+	return func(l *Logger) {
+		l.keyCase = keyCase
+	}
+}
+
+// keyNormalizer converts keys to a KeyCase, caching the conversions, since the same keys are
+// logged over and over. A nil keyNormalizer keeps keys as they are.
+type keyNormalizer struct {
+	keyCase KeyCase
+	mu      sync.RWMutex
+	cache   map[string]string
+}
+
+// newKeyNormalizer returns a keyNormalizer for keyCase, or nil for KeyCaseAsIs.
+func newKeyNormalizer(keyCase KeyCase) *keyNormalizer {
+	if keyCase == KeyCaseAsIs {
+		return nil
+	}
+	return &keyNormalizer{keyCase: keyCase, cache: map[string]string{}}
+}
+
+// normalize returns key converted to the KeyCase of n.
+func (n *keyNormalizer) normalize(key string) string {
+	if n == nil || key == "" || isOwnKey(key) {
+		return key
+	}
+	n.mu.RLock()
+	normalized, ok := n.cache[key]
+	n.mu.RUnlock()
+	if ok {
+		return normalized
+	}
+	normalized = normalizeKey(key, n.keyCase)
+	n.mu.Lock()
+	if len(n.cache) < maxCachedKeys {
+		n.cache[key] = normalized
+	}
+	n.mu.Unlock()
+	return normalized
+}
+
+// transform is a fieldTransform rewriting keys to the KeyCase of n.
+func (n *keyNormalizer) transform(f zapcore.Field) zapcore.Field {
+	f.Key = n.normalize(f.Key)
+	return f
+}
+
+// normalizeKey converts key to keyCase, segment by segment.
+func normalizeKey(key string, keyCase KeyCase) string {
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		words := splitWords(segment)
+		for j, word := range words {
+			runes := []rune(strings.ToLower(word))
+			if keyCase == CamelCase && j > 0 {
+				runes[0] = unicode.ToUpper(runes[0])
+			}
+			words[j] = string(runes)
+		}
+		if keyCase == CamelCase {
+			segments[i] = strings.Join(words, "")
+		} else {
+			segments[i] = strings.Join(words, "_")
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// splitWords splits s into words at non-alphanumeric characters and case changes. A run of
+// capitals is a single word, except for its last letter when followed by a lowercase one, so
+// HTTPStatus is split into HTTP and Status.
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words, word = append(words, string(word)), nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				words, word = append(words, string(word)), nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithKeyNormalization(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		keyCase logger.KeyCase
+		want    []string
+	}{
+		{
+			name:    "snake case",
+			keyCase: logger.SnakeCase,
+			want:    []string{"user_id", "http_status", "request_size", "ipv4_address", "http.status_code", "order_id"},
+		},
+		{
+			name:    "camel case",
+			keyCase: logger.CamelCase,
+			want:    []string{"userId", "httpStatus", "requestSize", "ipv4Address", "http.statusCode", "orderId"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l, sink := newMemoryLogger(t, logger.WithKeyNormalization(tc.keyCase), logger.WithTraceID(traceFromContext))
+			ctx := context.WithValue(context.Background(), traceKey{}, "abc123")
+
+			l.With("OrderID", 7).Info(ctx, "entry",
+				"userID", 1, "HTTPStatus", 200, "request-size", 3, "ipv4Address", "10.0.0.1",
+				zap.Int("http.status_code", 200))
+
+			entry := decodeLines(t, sink)[0]
+			for _, key := range tc.want {
+				require.Contains(t, entry, key)
+			}
+			require.Equal(t, "test-service", entry["service"])
+			require.Equal(t, "abc123", entry["trace_id"], "the Logger's own keys should be kept")
+		})
+	}
+}
+
+func TestWithKeyNormalizationRedaction(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithKeyNormalization(logger.SnakeCase), logger.WithRedactKeys("api_key"))
+
+	l.Info(context.Background(), "entry", "apiKey", "secret")
+
+	require.Equal(t, "[REDACTED]", decodeLines(t, sink)[0]["api_key"])
+}
+
+func TestWithKeyNormalizationMatchesOriginalKeys(t *testing.T) {
+	l, sink := newMemoryLogger(t,
+		logger.WithKeyNormalization(logger.CamelCase),
+		logger.WithHashFields([]byte("key"), "user_id"),
+		logger.WithRedactKeys("card_number"),
+	)
+
+	l.Info(context.Background(), "entry", "user_id", "alice", "card_number", "4111", "details", map[string]any{"card_number": "4111"})
+
+	entry := decodeLines(t, sink)[0]
+	require.NotContains(t, sink.logs.String(), "alice")
+	require.NotContains(t, sink.logs.String(), "4111")
+	require.Len(t, entry["userId"], 32, "the value should be hashed")
+	require.Equal(t, "[REDACTED]", entry["cardNumber"])
+}
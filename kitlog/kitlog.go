@@ -0,0 +1,92 @@
+// Package kitlog implements go-kit's log.Logger interface on top of a Logger, so that services
+// migrating from github.com/go-kit/log keep their logging calls while writing through the
+// Logger's encoders and sinks. It has no dependency on go-kit:
+//
+//	var kitLogger log.Logger = kitlog.New(l)
+//	kitLogger = level.NewFilter(kitLogger, level.AllowInfo())
+//	level.Info(kitLogger).Log("msg", "order placed", "order_id", 42)
+//
+// The value of the "level" key, as added by go-kit's level package, sets the level of the
+// entry, and the value of the "msg" key its message; the other pairs are written as fields.
+// The "ts" key is dropped, since every entry has a timestamp. go-kit passes no context, so the
+// entries carry no trace ID.
+package kitlog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Keys with a special meaning in go-kit log lines.
+const (
+	levelKey     = "level"
+	messageKey   = "msg"
+	timestampKey = "ts"
+)
+
+// missingValue is the value of a key without a value, as in go-kit.
+const missingValue = "(MISSING)"
+
+// Logger is a go-kit log.Logger writing through a Logger.
+type Logger struct {
+	l *logger.Logger
+}
+
+// New returns a go-kit log.Logger writing through l.
+func New(l *logger.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+// Log implements go-kit's log.Logger, writing keyvals as an entry at the level of the "level"
+// key, or InfoLevel without one. It always returns nil.
+func (k *Logger) Log(keyvals ...interface{}) error {
+	lvl := zapcore.InfoLevel
+	var msg string
+	fields := make([]interface{}, 0, len(keyvals))
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		var value interface{} = missingValue
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		switch key {
+		case levelKey:
+			if parsed, ok := parseLevel(value); ok {
+				lvl = parsed
+				continue
+			}
+		case messageKey:
+			if msg == "" {
+				msg = fmt.Sprint(value)
+				continue
+			}
+		case timestampKey:
+			continue
+		}
+		fields = append(fields, key, value)
+	}
+	// go-kit's contextual loggers add a frame when they wrap this one.
+	k.l.LogDepth(context.Background(), logger.CallerDepth("github.com/go-kit"), lvl, msg, fields...)
+	return nil
+}
+
+// parseLevel returns the level of a go-kit level value, such as the values of level.DebugValue
+// and level.ErrorValue.
+func parseLevel(v interface{}) (zapcore.Level, bool) {
+	switch strings.ToLower(fmt.Sprint(v)) {
+	case "debug":
+		return zapcore.DebugLevel, true
+	case "info":
+		return zapcore.InfoLevel, true
+	case "warn", "warning":
+		return zapcore.WarnLevel, true
+	case "error":
+		return zapcore.ErrorLevel, true
+	}
+	return zapcore.InfoLevel, false
+}
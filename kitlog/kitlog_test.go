@@ -0,0 +1,47 @@
+package kitlog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/janduursma/zap-logger-wrapper/v2/kitlog"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// kitLogger mirrors the Logger interface of github.com/go-kit/log.
+type kitLogger interface {
+	Log(keyvals ...interface{}) error
+}
+
+var _ kitLogger = (*kitlog.Logger)(nil)
+
+// levelValue mirrors the values of github.com/go-kit/log/level, which print as their name.
+type levelValue string
+
+func (v levelValue) String() string { return string(v) }
+
+func TestLogger(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	k := kitlog.New(l)
+
+	require.NoError(t, k.Log("level", levelValue("warn"), "ts", time.Now(), "msg", "slow query", "duration", "2s"))
+	require.NoError(t, k.Log("msg", "order placed", "order_id", 42))
+	require.NoError(t, k.Log("level", levelValue("error"), "err", errors.New("boom"), "odd"))
+
+	all := entries.All()
+	require.Len(t, all, 3)
+	require.Equal(t, zapcore.WarnLevel, all[0].Level)
+	require.Equal(t, "slow query", all[0].Message)
+	require.Equal(t, map[string]interface{}{"service": "test-service", "duration": "2s"}, all[0].ContextMap())
+	require.Equal(t, zapcore.InfoLevel, all[1].Level)
+	require.Equal(t, int64(42), all[1].ContextMap()["order_id"])
+	require.Equal(t, zapcore.ErrorLevel, all[2].Level)
+	require.Empty(t, all[2].Message)
+	require.Equal(t, "(MISSING)", all[2].ContextMap()["odd"])
+	for _, e := range all {
+		require.Regexp(t, `/kitlog_test\.go:\d+$`, e.Caller.String())
+	}
+}
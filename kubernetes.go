@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+)
+
+// The files the container ID is read from, see WithKubernetesFields.
+var (
+	cgroupPath    = "/proc/self/cgroup"
+	mountinfoPath = "/proc/self/mountinfo"
+)
+
+// kubernetesEnv maps the environment variables conventionally set through the downward API to
+// the keys of their fields.
+var kubernetesEnv = map[string]string{
+	"POD_NAME":      "k8s_pod_name",
+	"POD_NAMESPACE": "k8s_namespace",
+	"NODE_NAME":     "k8s_node_name",
+}
+
+// containerIDPattern matches the 64-character hexadecimal ID of a container in a cgroup path,
+// e.g. .../pod<uid>/<id> or .../cri-containerd-<id>.scope.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// mountContainerIDPattern matches the ID of a container in the paths of its mounts, e.g.
+// /var/lib/docker/containers/<id>/hostname, for cgroup v2 hosts where /proc/self/cgroup holds
+// no ID.
+var mountContainerIDPattern = regexp.MustCompile(`/containers/([0-9a-f]{64})/`)
+
+// WithKubernetesFields adds the k8s_pod_name, k8s_namespace and k8s_node_name fields, read
+// from the POD_NAME, POD_NAMESPACE and NODE_NAME environment variables that pods set through
+// the downward API, and the container_id field, read from the cgroups of the process, to
+// every entry, so that entries can be correlated with the cluster's events and metrics.
+// Missing values are omitted. The fields have the precedence of FieldsFromPlatform.
+//
+//	env:
+//	  - name: POD_NAME
+//	    valueFrom: {fieldRef: {fieldPath: metadata.name}}
+func WithKubernetesFields() Option {
+	return func(l *Logger) {
+		l.fieldLayers = append(l.fieldLayers, fieldLayer{
+			source: FieldsFromPlatform,
+			load: func() ([]keyVal, error) {
+				fields := map[string]interface{}{}
+				for env, key := range kubernetesEnv {
+					if value := os.Getenv(env); value != "" {
+						fields[key] = value
+					}
+				}
+				if id := containerID(); id != "" {
+					fields["container_id"] = id
+				}
+				return mapFields(fields), nil
+			},
+		})
+	}
+}
+
+// containerID returns the ID of the container the process runs in, or an empty string if it
+// cannot be determined.
+func containerID() string {
+	if id := findInFile(cgroupPath, containerIDPattern, 0); id != "" {
+		return id
+	}
+	return findInFile(mountinfoPath, mountContainerIDPattern, 1)
+}
+
+// findInFile returns the given submatch of the first line of the file at path matching
+// pattern, or an empty string.
+func findInFile(path string, pattern *regexp.Regexp, submatch int) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := pattern.FindStringSubmatch(scanner.Text()); m != nil {
+			return m[submatch]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcFile writes content to a temporary file and points target at it for the duration of
+// the test.
+func fakeProcFile(t *testing.T, target *string, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), filepath.Base(*target))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	original := *target
+	*target = path
+	t.Cleanup(func() { *target = original })
+}
+
+func TestWithKubernetesFields(t *testing.T) {
+	id := strings.Repeat("ab12", 16)
+	t.Setenv("POD_NAME", "orders-7d9f-x2k")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("NODE_NAME", "")
+	fakeProcFile(t, logger.CgroupPath,
+		"12:memory:/kubepods/burstable/pod8d1e/"+id+"\n11:cpu:/kubepods/burstable/pod8d1e/"+id+"\n")
+
+	l, sink := newMemoryLogger(t, logger.WithKubernetesFields())
+	l.Info(context.Background(), "hello")
+
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "orders-7d9f-x2k", entry["k8s_pod_name"])
+	require.Equal(t, "shop", entry["k8s_namespace"])
+	require.NotContains(t, entry, "k8s_node_name", "missing values should be omitted")
+	require.Equal(t, id, entry["container_id"])
+}
+
+func TestWithKubernetesFieldsCgroupV2(t *testing.T) {
+	id := strings.Repeat("cd34", 16)
+	fakeProcFile(t, logger.CgroupPath, "0::/\n")
+	fakeProcFile(t, logger.MountinfoPath,
+		"1 0 0:1 / / rw - overlay overlay rw\n"+
+			"2 1 8:1 /var/lib/docker/containers/"+id+"/hostname /etc/hostname rw - ext4 /dev/sda1 rw\n")
+
+	l, sink := newMemoryLogger(t, logger.WithKubernetesFields())
+	l.Info(context.Background(), "hello")
+
+	require.Equal(t, id, decodeLines(t, sink)[0]["container_id"])
+}
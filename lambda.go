@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLambdaMode configures the Logger for AWS Lambda functions:
+//   - Entries in FormatJSON use the keys of Lambda's JSON log format, timestamp, level and
+//     message, with upper-case levels, so that CloudWatch filters them by level, and omit the
+//     caller.
+//   - WithBufferedWrites is ignored, since buffered entries are lost when Lambda freezes the
+//     execution environment between invocations.
+//   - Sync does not sync stdout and stderr, so that it can be called after every invocation.
+//
+// The contrib/lambda module also adds the request ID of the invocation to every entry.
+func WithLambdaMode() Option {
+	return func(l *Logger) {
+		l.lambdaMode = true
+	}
+}
+
+// applyLambda configures the keys of the entry's time, level and message for WithLambdaMode.
+func applyLambda(enc *zapcore.EncoderConfig) {
+	enc.TimeKey = "timestamp"
+	enc.LevelKey = "level"
+	enc.MessageKey = "message"
+	enc.CallerKey = zapcore.OmitKey
+	enc.FunctionKey = zapcore.OmitKey
+	enc.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	enc.EncodeLevel = zapcore.CapitalLevelEncoder
+}
+
+// unsyncedWriter is a zapcore.WriteSyncer whose Sync does nothing, for the console outputs in
+// WithLambdaMode.
+type unsyncedWriter struct {
+	zapcore.WriteSyncer
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (unsyncedWriter) Sync() error {
+	return nil
+}
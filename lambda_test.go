@@ -0,0 +1,32 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLambdaMode(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithLambdaMode(), logger.WithBufferedWrites(1<<20, time.Hour))
+
+	l.Info(context.Background(), "invoked", "key", "value")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1, "entries should not be buffered")
+	require.Equal(t, "INFO", entries[0]["level"])
+	require.Equal(t, "invoked", entries[0]["message"])
+	require.Contains(t, entries[0], "timestamp")
+	require.NotContains(t, entries[0], "caller")
+	require.Equal(t, "value", entries[0]["key"])
+}
+
+func TestWithLambdaModeSync(t *testing.T) {
+	l, err := logger.New("test-service", logger.WithLambdaMode(), logger.WithSyncErrorFilter(nil),
+		logger.WithOutputPaths([]string{"stdout"}))
+	require.NoError(t, err)
+
+	require.NoError(t, l.Sync(), "stdout should not be synced")
+}
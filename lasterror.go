@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// logIDKey is the field under which the ID of a recorded Error entry is emitted.
+const logIDKey = "log_id"
+
+// pendingErrorKey is the key of the marker field carrying a recorded Error entry to the
+// lastErrorCore. The field is never encoded.
+const pendingErrorKey = "last_error"
+
+// ErrorEntry describes an Error entry logged for a request, see LastError.
+type ErrorEntry struct {
+	// Message is the message of the entry.
+	Message string
+	// Fingerprint is the error fingerprint of the entry, the same value WithErrorFingerprint
+	// emits as error_fingerprint.
+	Fingerprint string
+	// LogID uniquely identifies the entry; it is emitted as its log_id field.
+	LogID string
+	// Time is the time at which the entry was logged.
+	Time time.Time
+}
+
+// lastErrorKey is the context key of a request's lastError.
+type lastErrorKey struct{}
+
+// ContextWithLastError returns a copy of ctx that records the Error entries logged with it,
+// so that LastError can return the most recent one. Every Error logged with the returned
+// context gets a log_id field. It is typically called once per request, for example in an
+// HTTP middleware.
+func ContextWithLastError(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lastErrorKey{}, &lastError{})
+}
+
+// lastError holds the most recent Error entry of a request.
+type lastError struct {
+	mu    sync.Mutex
+	entry ErrorEntry
+	ok    bool
+}
+
+// LastError returns the most recent Error entry logged with ctx, for example to include its
+// log_id as a reference in an API error response. Entries dropped by sampling, rate limiting or
+// deduplication are not recorded, so the log_id always refers to a written entry. It returns
+// false when no Error was logged yet, or when ctx was not prepared with ContextWithLastError.
+func (l *Logger) LastError(ctx context.Context) (ErrorEntry, bool) {
+	if ctx == nil {
+		return ErrorEntry{}, false
+	}
+	last, _ := ctx.Value(lastErrorKey{}).(*lastError)
+	if last == nil {
+		return ErrorEntry{}, false
+	}
+	last.mu.Lock()
+	defer last.mu.Unlock()
+	return last.entry, last.ok
+}
+
+// recordError returns the log_id field to add to an Error entry and the marker field recording
+// it as the last error of the request of ctx once it is written, if ctx was prepared with
+// ContextWithLastError.
+func (l *Logger) recordError(ctx context.Context, msg string, keyVals []interface{}) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	last, _ := ctx.Value(lastErrorKey{}).(*lastError)
+	if last == nil {
+		return nil
+	}
+
+	err := keyValsError(keyVals, false)
+	if err == nil {
+		err = keyValsError(l.fields, true)
+	}
+	entry := ErrorEntry{
+		Message:     msg,
+		Fingerprint: fingerprint(msg, err),
+		LogID:       newLogID(),
+		Time:        time.Now(),
+	}
+
+	pending := &pendingError{last: last, entry: entry}
+	return []interface{}{logIDKey, entry.LogID, zapcore.Field{Key: pendingErrorKey, Type: zapcore.SkipType, Interface: pending}}
+}
+
+// pendingError is an Error entry to record as the last error of its request once written.
+type pendingError struct {
+	last  *lastError
+	entry ErrorEntry
+}
+
+// lastErrorCore is a zapcore.Core recording the Error entries it writes as the last error of
+// their request. It sits below the cores dropping entries.
+type lastErrorCore struct {
+	zapcore.Core
+}
+
+// With implements zapcore.Core.
+func (c *lastErrorCore) With(fields []zapcore.Field) zapcore.Core {
+	return &lastErrorCore{Core: c.Core.With(fields)}
+}
+
+// Check implements zapcore.Core.
+func (c *lastErrorCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *lastErrorCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	for _, f := range fields {
+		if p, ok := f.Interface.(*pendingError); ok && f.Key == pendingErrorKey && f.Type == zapcore.SkipType {
+			p.last.mu.Lock()
+			p.last.entry, p.last.ok = p.entry, true
+			p.last.mu.Unlock()
+		}
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// keyValsError returns the first, or with last the last, error among loosely typed
+// key-value pairs, mirroring the error the fingerprint core picks up.
+func keyValsError(keyVals []interface{}, last bool) error {
+	var found error
+	for _, f := range splitKeyVals(keyVals) {
+		var err error
+		switch len(f.items) {
+		case 1:
+			if field, ok := f.items[0].(zapcore.Field); ok && field.Type == zapcore.ErrorType {
+				err, _ = field.Interface.(error)
+			}
+		case 2:
+			err, _ = f.items[1].(error)
+		}
+		if err == nil {
+			continue
+		}
+		if !last {
+			return err
+		}
+		found = err
+	}
+	return found
+}
+
+// newLogID returns a random identifier for a log entry.
+func newLogID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
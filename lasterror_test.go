@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastError(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithErrorFingerprint())
+	ctx := logger.ContextWithLastError(context.Background())
+
+	_, ok := l.LastError(ctx)
+	require.False(t, ok, "no error was logged yet")
+
+	l.Error(ctx, "lookup failed", "err", fmt.Errorf("query: %w", &notFoundError{id: 1}))
+	l.Info(ctx, "recovered")
+	l.With("err", errors.New("timeout after 5s")).Error(ctx, "call failed")
+
+	last, ok := l.LastError(ctx)
+	require.True(t, ok)
+	require.Equal(t, "call failed", last.Message)
+	require.NotEmpty(t, last.LogID)
+	require.False(t, last.Time.IsZero())
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 3)
+	require.NotEqual(t, entries[0]["log_id"], entries[2]["log_id"])
+	require.NotContains(t, entries[1], "log_id", "only errors should carry a log_id")
+	require.Equal(t, last.LogID, entries[2]["log_id"])
+	require.Equal(t, last.Fingerprint, entries[2]["error_fingerprint"], "the fingerprint should match the entry")
+}
+
+func TestLastErrorWithoutContext(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	l.Error(ctx, "failed")
+
+	_, ok := l.LastError(ctx)
+	require.False(t, ok)
+	require.NotContains(t, decodeLines(t, sink)[0], "log_id")
+}
+
+func TestLastErrorNilContext(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	var ctx context.Context
+
+	require.NotPanics(t, func() { l.Error(ctx, "failed") })
+
+	_, ok := l.LastError(ctx)
+	require.False(t, ok)
+	require.Len(t, decodeLines(t, sink), 1)
+}
+
+func TestLastErrorPerRequest(t *testing.T) {
+	l, _ := newMemoryLogger(t)
+	first := logger.ContextWithLastError(context.Background())
+	second := logger.ContextWithLastError(context.Background())
+
+	l.Error(first, "first failed")
+
+	_, ok := l.LastError(second)
+	require.False(t, ok, "errors of other requests should not be returned")
+	last, ok := l.LastError(first)
+	require.True(t, ok)
+	require.Equal(t, "first failed", last.Message)
+}
+
+func TestLastErrorIgnoresDroppedEntries(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRateLimit(0, 1))
+	ctx := logger.ContextWithLastError(context.Background())
+
+	l.Error(ctx, "charge failed", "attempt", 1)
+	l.Error(ctx, "charge failed", "attempt", 2)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	last, ok := l.LastError(ctx)
+	require.True(t, ok)
+	require.Equal(t, entries[0]["log_id"], last.LogID, "the rate-limited entry should not be the last error")
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// lazyValue is a value computed when an entry holding it is encoded.
+type lazyValue struct {
+	fn    func() interface{}
+	once  sync.Once
+	value interface{}
+}
+
+// Lazy returns a value for a key-value pair that calls fn only when the entry is written, so
+// that expensive values, such as large structs or pretty-printed SQL, cost nothing when the
+// entry is disabled by its level or dropped:
+//
+//	l.Debug(ctx, "query", "plan", logger.Lazy(func() interface{} { return explain(q) }))
+//
+// fn is called at most once, however many outputs encode the entry. Its result is encoded
+// as JSON.
+func Lazy(fn func() interface{}) interface{} {
+	return &lazyValue{fn: fn}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v *lazyValue) MarshalJSON() ([]byte, error) {
+	v.once.Do(func() {
+		v.value = v.fn()
+	})
+	return json.Marshal(v.value)
+}
@@ -0,0 +1,38 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLazy(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	calls := 0
+	value := func() interface{} {
+		calls++
+		return map[string]int{"rows": 3}
+	}
+
+	l.Debug(context.Background(), "disabled", "plan", logger.Lazy(value))
+	require.Zero(t, calls, "values of disabled entries should not be computed")
+
+	l.Info(context.Background(), "enabled", "plan", logger.Lazy(value))
+	require.Equal(t, 1, calls)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, map[string]any{"rows": float64(3)}, entries[0]["plan"])
+}
+
+func TestEnabled(t *testing.T) {
+	l, _ := newMemoryLogger(t)
+
+	require.False(t, l.Enabled(zapcore.DebugLevel))
+	require.True(t, l.Enabled(zapcore.InfoLevel))
+	require.True(t, l.Enabled(zapcore.ErrorLevel))
+	require.Equal(t, logger.DebugEnabled, l.ForLibrary("db", zapcore.DebugLevel).Enabled(zapcore.DebugLevel))
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelCore is a zapcore.Core gating entries by level before they reach the wrapped core.
+// Every Logger's cores end in a levelCore, which child loggers can replace to get a level
+// that is independent from their parent's.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+// Level returns the minimum enabled level.
+func (c *levelCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.level)
+}
+
+// With implements zapcore.Core.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check implements zapcore.Core.
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// withLevel returns a shallow copy of l whose entries are gated by level instead of
+// the level l was created with.
+func (l *Logger) withLevel(level zapcore.LevelEnabler) *Logger {
+	regate := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+		return &levelCore{Core: core, level: level}
+	})
+
+	child := *l
+	child.zapLogger = l.zapLogger.WithOptions(regate)
+	child.baseLogger = l.baseLogger.WithOptions(regate)
+	return &child
+}
+
+// ForLibrary returns a child Logger meant to be handed to a chatty third-party library,
+// such as a database driver. Its entries carry a library field and are gated by level,
+// independently from the parent's level: the library's noise can be reduced without
+// affecting application logs, or raised for debugging it while the application stays at
+// its usual level. Trace IDs are still injected from the contexts passed by the library.
+func (l *Logger) ForLibrary(name string, level zapcore.Level) *Logger {
+	return l.withLevel(level).With("library", name)
+}
+
+// minLevelKey is the context key of the level set through ContextWithMinLevel.
+type minLevelKey struct{}
+
+// ContextWithMinLevel returns a copy of ctx overriding the Logger's level for the entries
+// logged with it, or a context derived from it. Middleware can use it to enable Debug logging
+// for a single request, e.g. one carrying an X-Debug header or from an allowlisted user,
+// without changing the level of the other requests. It takes precedence over WithSampledDebug.
+func ContextWithMinLevel(ctx context.Context, level zapcore.Level) context.Context {
+	return context.WithValue(ctx, minLevelKey{}, level)
+}
+
+// MinLevelFromContext returns the level set through ContextWithMinLevel, if any.
+func MinLevelFromContext(ctx context.Context) (zapcore.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(minLevelKey{}).(zapcore.Level)
+	return level, ok
+}
+
+// Enabled reports whether entries at level are written, so that expensive arguments can be
+// computed only when needed. It does not take per-request overrides into account, such as
+// ContextWithMinLevel and WithSampledDebug; use Lazy values to cover those as well.
+func (l *Logger) Enabled(level zapcore.Level) bool {
+	if level == zapcore.DebugLevel && !DebugEnabled {
+		return false
+	}
+	return l.zapLogger.Desugar().Core().Enabled(level)
+}
@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestForLibrary(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	ctx := context.WithValue(context.Background(), traceKey{}, "req-1")
+
+	pgx := l.ForLibrary("pgx", zap.WarnLevel)
+	pgx.Info(ctx, "connection acquired")
+	pgx.Error(ctx, "connection lost")
+	l.Info(ctx, "application info")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "connection lost", entries[0]["msg"])
+	require.Equal(t, "pgx", entries[0]["library"])
+	require.Equal(t, "req-1", entries[0]["trace_id"], "library loggers should still inject trace IDs")
+	require.Equal(t, "application info", entries[1]["msg"], "the parent's level should be unaffected")
+}
+
+func TestForLibraryCanBeMoreVerbose(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	lib := l.ForLibrary("grpc", zap.DebugLevel)
+	lib.Debug(ctx, "library debug")
+	lib.With("conn", 1).Without("conn").Debug(ctx, "still independent")
+	l.Debug(ctx, "application debug")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "library debug", entries[0]["msg"])
+	require.Equal(t, "grpc", entries[1]["library"], "Without should keep the library field and level")
+}
+
+func TestLevelAppliesToExtraCores(t *testing.T) {
+	core, observed := observer.New(zap.DebugLevel)
+	l, _ := newMemoryLogger(t, logger.WithCore(core))
+
+	l.Debug(context.Background(), "below the logger's level")
+
+	require.Equal(t, 0, observed.Len())
+}
+
+func TestContextWithMinLevel(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t)
+	debugCtx := logger.ContextWithMinLevel(context.Background(), zap.DebugLevel)
+	quietCtx := logger.ContextWithMinLevel(context.Background(), zap.ErrorLevel)
+
+	l.Debug(debugCtx, "request debug")
+	l.With("component", "cart").Debug(debugCtx, "child debug")
+	l.Debug(context.Background(), "other request debug")
+	l.Info(quietCtx, "quiet info")
+	l.Error(quietCtx, "quiet error")
+
+	var msgs []string
+	for _, e := range decodeLines(t, sink) {
+		msgs = append(msgs, e["msg"].(string))
+	}
+	require.Equal(t, []string{"request debug", "child debug", "quiet error"}, msgs)
+
+	level, ok := logger.MinLevelFromContext(debugCtx)
+	require.True(t, ok)
+	require.Equal(t, zap.DebugLevel, level)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.5 --name Log --output loggertest --outpkg loggertest --filename mock_log.go --structname MockLog --with-expecter
+
+// Log is the interface of the logging methods of Logger, so that code can depend on it instead
+// of on *Logger, and tests can assert what is logged through loggertest.MockLog, which is
+// generated by mockery. Child derives a Log rather than With, since Go has no covariant return
+// types and With returns a *Logger.
+type Log interface {
+	// Debug logs a message at DebugLevel, see Logger.Debug.
+	Debug(ctx context.Context, msg string, keyVals ...interface{})
+	// Info logs a message at InfoLevel, see Logger.Info.
+	Info(ctx context.Context, msg string, keyVals ...interface{})
+	// Error logs a message at ErrorLevel, see Logger.Error.
+	Error(ctx context.Context, msg string, keyVals ...interface{})
+	// Log logs a message at level, see Logger.Log.
+	Log(ctx context.Context, level zapcore.Level, msg string, keyVals ...interface{})
+	// Errors logs the errors of a batch operation, see Logger.Errors.
+	Errors(ctx context.Context, msg string, errs []error, keyVals ...interface{})
+	// Child returns a child Log with default key-value pairs, see Logger.Child.
+	Child(keyVals ...interface{}) Log
+	// Sync flushes the buffered entries, see Logger.Sync.
+	Sync() error
+}
+
+// Logger implements Log.
+var _ Log = (*Logger)(nil)
@@ -8,10 +8,20 @@
 //   - GetTraceIDFn: nil (i.e. no trace ID is automatically added)
 //
 // These defaults can be overridden using the provided functional options.
+//
+// The core package only depends on zap. Integrations with heavier dependencies, such as
+// error trackers, metrics systems and remote sinks, live in separate modules under contrib/
+// and plug in through WithCore, so consumers only pull in the dependencies they use.
 package logger
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -20,12 +30,83 @@ import (
 // GetTraceIDFn is a function type that, given a context.Context, returns a trace ID.
 type GetTraceIDFn func(ctx context.Context) string
 
+// GetTraceContextFn is a function type that, given a context.Context, returns the IDs of the
+// trace and span it carries and whether the trace is sampled.
+type GetTraceContextFn func(ctx context.Context) (traceID, spanID string, sampled bool)
+
+// GetFieldsFn is a function type that, given a context.Context, returns key-value pairs to add
+// to the entry logged with it.
+type GetFieldsFn func(ctx context.Context) []interface{}
+
 // Logger is the wrapper around zap.SugaredLogger.
 type Logger struct {
 	zapLogger    *zap.SugaredLogger
+	baseLogger   *zap.SugaredLogger
+	fields       []interface{}
 	getTraceIDFn GetTraceIDFn
+	getTraceCtx  GetTraceContextFn
+	getFieldsFns []GetFieldsFn
 	level        zapcore.Level
 	outputPaths  []string
+	format       Format
+	colorMode    ColorMode
+
+	existing         *zap.Logger
+	cores            []route
+	hooks            []Hook
+	contextHooks     []ContextHook
+	writeErrorHooks  []WriteErrorHook
+	errorFingerprint bool
+	redactKeys       []string
+	scrubPatterns    []*regexp.Regexp
+	hashKey          []byte
+	hashFields       []string
+	sampling         samplingConfig
+	stats            *statsRecorder
+	traceSampling    *float64
+	rateLimit        *rateLimitConfig
+	dedupWindow      time.Duration
+	elapsedKey       interface{}
+	duplicateKeys    DuplicateKeyPolicy
+	buffering        *bufferConfig
+	precedingDebug   int
+	offloadStore     BlobStore
+	offloadThreshold int
+	buffer           *zapcore.BufferedWriteSyncer
+	overflow         OverflowPolicy
+	async            *asyncWriter
+	fieldLayers      []fieldLayer
+	fieldConflicts   []FieldConflict
+	syncErrorFilter  SyncErrorFilter
+	sinks            map[string]zap.Sink
+	errorsThreshold  *int
+	errorClassifier  ErrorClassifier
+	verifiedPaths    []string
+	startupCheck     time.Duration
+	levelPrefixes    map[zapcore.Level]string
+	fallbackPath     string
+	gcpProject       string
+	gcpLabels        []string
+	ddEnv            string
+	ddVersion        string
+	siemDevice       [3]string
+	siemKeys         map[string]string
+	traceIDKey       string
+	traceIDFirst     bool
+	sampledDebug     bool
+	outputWriters    []zapcore.WriteSyncer
+	lambdaMode       bool
+	dynamicFields    []*dynamicField
+
+	reservedKeyPolicy ReservedKeyPolicy
+	reservedKeys      map[string]struct{}
+	strictKeyVals     *bool
+	keyCase           KeyCase
+	group             *fieldGroup
+	maxFieldBytes     int
+	closeOutputs      func()
+	dedup             *dedupState
+	rateLimiter       *rateLimiter
 }
 
 // Option defines a functional option for configuring the Logger.
@@ -38,6 +119,27 @@ func WithTraceID(getTraceIDFn GetTraceIDFn) Option {
 	}
 }
 
+// WithTraceContext allows a custom function to be set which automatically adds the trace ID,
+// span ID and sampled flag to logs, as the trace_id, span_id and trace_sampled fields, for
+// backends joining logs and traces precisely. It replaces the function set through WithTraceID.
+// Contexts without a trace ID add none of the fields.
+func WithTraceContext(getTraceContextFn GetTraceContextFn) Option {
+	return func(l *Logger) {
+		l.getTraceCtx = getTraceContextFn
+	}
+}
+
+// WithContextFields registers functions deriving fields from the context of every entry, so
+// that values such as a request or tenant ID are added without every call site repeating them.
+// The trace ID of WithTraceID and the IDs stored through ContextWithRequestID and the other
+// context helpers are derived first; the fields of the functions follow in registration
+// order. See ContextValue for values stored by other packages.
+func WithContextFields(getFieldsFns ...GetFieldsFn) Option {
+	return func(l *Logger) {
+		l.getFieldsFns = append(l.getFieldsFns, getFieldsFns...)
+	}
+}
+
 // WithLevel allows a custom minimum logging level to be set.
 func WithLevel(level zapcore.Level) Option {
 	return func(l *Logger) {
@@ -52,91 +154,473 @@ func WithOutputPaths(outputPaths []string) Option {
 	}
 }
 
+// WithOutputWriter adds writers receiving the encoded entries alongside the output paths, for
+// destinations that are not opened from a path, such as the testing.T of loggertest.NewForTesting.
+// Writers must be safe for concurrent use; they are synced with the outputs, but not closed.
+func WithOutputWriter(writers ...zapcore.WriteSyncer) Option {
+	return func(l *Logger) {
+		l.outputWriters = append(l.outputWriters, writers...)
+	}
+}
+
+// WithCore adds cores that receive every entry alongside the configured output paths.
+// This is the extension point used by the integrations that ship as separate modules
+// under contrib/, such as error trackers and remote sinks.
+func WithCore(cores ...zapcore.Core) Option {
+	return func(l *Logger) {
+		for _, c := range cores {
+			l.cores = append(l.cores, route{core: c, clearance: Restricted})
+		}
+	}
+}
+
+// WithExistingZap wraps an already configured zap logger instead of building one from
+// the options. Its cores, encoders, fields and options are kept, and this package's features,
+// such as trace ID injection and the optional cores, are layered on top.
+// The options configuring the outputs, i.e. WithOutputPaths, WithOutputWriter, WithFormat,
+// WithColor and WithBufferedWrites, have no effect. The level set through WithLevel, Info by default,
+// applies on top of the level of the existing logger's cores.
+func WithExistingZap(l *zap.Logger) Option {
+	return func(logger *Logger) {
+		logger.existing = l
+	}
+}
+
 // New creates a new Logger wrapper around zap.SugaredLogger.
 func New(service string, opts ...Option) (*Logger, error) {
-	defaultTraceIDFn := func(_ context.Context) string { return "" }
-	defaultLevel := zap.InfoLevel
-	defaultOutputPaths := []string{"stdout"}
-
 	l, err := zap.NewProduction()
 	if err != nil {
 		return nil, err
 	}
 
+	logger := configure(opts)
+	logger.zapLogger = l.Sugar()
+	initialFields, err := logger.resolveFields()
+	if err != nil {
+		return nil, err
+	}
+
+	base := logger.existing
+	if base == nil {
+		if base, err = logger.newZap(service); err != nil {
+			return nil, err
+		}
+	} else {
+		logger.setReservedKeys(zap.NewProductionEncoderConfig())
+	}
+
+	// The service and initial fields are added after the cores are wrapped, so that extra cores
+	// receive them too.
+	l = base.WithOptions(
+		zap.AddCallerSkip(callerSkip),
+		zap.WrapCore(logger.wrapCore),
+		zap.Fields(append([]zap.Field{zap.String("service", service)}, initialFields...)...),
+	)
+	logger.zapLogger = l.Sugar()
+	logger.baseLogger = logger.zapLogger
+	if logger.startupCheck > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), logger.startupCheck)
+		defer cancel()
+		if err := logger.Ping(ctx); err != nil {
+			if logger.closeOutputs != nil {
+				logger.closeOutputs()
+			}
+			return nil, fmt.Errorf("startup check: %w", err)
+		}
+	}
+	if logger.async != nil {
+		logger.async.start(logger.reportDrops)
+	}
+
+	return logger, nil
+}
+
+// NewNop returns a Logger that discards every entry and never fails, for tests and optional
+// dependencies that need a Logger but whose output does not matter.
+func NewNop() *Logger {
+	logger := configure(nil)
+	logger.zapLogger = zap.NewNop().Sugar()
+	logger.baseLogger = logger.zapLogger
+	return logger
+}
+
+// configure returns a Logger holding the defaults, overridden by opts.
+func configure(opts []Option) *Logger {
+	defaultTraceIDFn := func(_ context.Context) string { return "" }
+	defaultLevel := zap.InfoLevel
+	defaultOutputPaths := []string{"stdout"}
+
 	logger := &Logger{
-		zapLogger:    l.Sugar(),
 		getTraceIDFn: defaultTraceIDFn,
 		level:        defaultLevel,
 		outputPaths:  defaultOutputPaths,
-	}
+		format:       FormatJSON,
+		sampling:     samplingConfig{tick: time.Second, first: 100, thereafter: 100},
 
+		syncErrorFilter: IgnoreConsoleSyncErrors,
+	}
 	for _, opt := range opts {
 		opt(logger)
 	}
+	return logger
+}
 
+// newZap builds the zap logger writing to the configured output paths in the configured format.
+func (l *Logger) newZap(service string) (*zap.Logger, error) {
 	config := zap.NewProductionConfig()
-	config.Level = zap.NewAtomicLevelAt(logger.level)
+	// The outputs accept every level; the Logger's level is enforced by levelCore instead,
+	// so that child loggers can have their own, independent level.
+	config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.DisableStacktrace = true
-	config.InitialFields = map[string]any{
-		"service": service,
+	config.Sampling = nil // Sampling is done by samplingCore, see WithSampling.
+	config.OutputPaths = l.outputPaths
+	if err := l.applyFormat(&config); err != nil {
+		return nil, err
 	}
-	config.OutputPaths = logger.outputPaths
+	l.setReservedKeys(config.EncoderConfig)
 
-	l, err = config.Build(zap.WithCaller(true))
+	encoder := l.newEncoder(service, config)
+	opts := openOptions{benign: l.syncErrorFilter, verified: l.verifiedPaths, unsyncedConsole: l.lambdaMode}
+	closeFallback := func() {}
+	if l.fallbackPath != "" {
+		var err error
+		if opts.fallback, closeFallback, err = openFallback(l.fallbackPath, service, encoder); err != nil {
+			return nil, err
+		}
+	}
+	sink, sinks, closeSink, err := openSinks(config.OutputPaths, SinkConfig{
+		Service:       service,
+		Format:        l.format,
+		EncoderConfig: config.EncoderConfig,
+	}, opts)
 	if err != nil {
+		closeFallback()
 		return nil, err
 	}
-	logger.zapLogger = l.Sugar()
+	errSink, _, err := zap.Open(config.ErrorOutputPaths...)
+	if err != nil {
+		closeSink()
+		closeFallback()
+		return nil, err
+	}
+	l.sinks = sinks
+	// The outputs, such as the connections of remote sinks, are closed by Close.
+	l.closeOutputs = sync.OnceFunc(func() {
+		closeSink()
+		closeFallback()
+	})
+	if len(l.outputWriters) > 0 {
+		sink = zap.CombineWriteSyncers(append([]zapcore.WriteSyncer{sink}, l.outputWriters...)...)
+	}
 
-	return logger, nil
+	return zap.New(
+		l.formatCore(l.traceFieldCore(zapcore.NewCore(encoder, l.bufferSink(sink), config.Level))),
+		zap.ErrorOutput(errSink),
+		zap.WithCaller(true),
+	), nil
 }
 
-// Info logs a message at InfoLevel, automatically including trace_id if available.
-func (l *Logger) Info(ctx context.Context, msg string, keyVals ...interface{}) {
-	if l.getTraceIDFn != nil {
-		if traceID := l.getTraceIDFn(ctx); traceID != "" {
-			// Append the trace_id as a key-value pair
-			keyVals = append(keyVals, "trace_id", traceID)
+// wrapCore layers the optional cores enabled through the options around core.
+func (l *Logger) wrapCore(core zapcore.Core) zapcore.Core {
+	if l.stats != nil {
+		core = &latencyCore{Core: core, name: outputsSinkName, recorder: l.stats}
+	}
+	cores := []zapcore.Core{core}
+	for _, r := range l.cores {
+		c := r.core
+		if l.stats != nil {
+			c = &latencyCore{Core: c, name: sinkName(c), recorder: l.stats}
 		}
+		if r.clearance < Restricted {
+			c = &clearanceCore{Core: c, clearance: r.clearance}
+		}
+		cores = append(cores, c)
+	}
+	if len(cores) > 1 {
+		core = newTeeCore(cores...)
+	} else {
+		core = cores[0]
+	}
+	if l.stats != nil {
+		core = &statsCore{Core: core, recorder: l.stats}
+	}
+	if len(l.hooks) > 0 || len(l.contextHooks) > 0 || len(l.writeErrorHooks) > 0 {
+		core = newHookCore(core, l.hooks, l.contextHooks, l.writeErrorHooks)
 	}
-	l.zapLogger.Infow(msg, keyVals...)
+	if l.errorFingerprint {
+		core = newFingerprintCore(core)
+	}
+
+	// Field transforms run first, so every output and hook only sees the rewritten fields.
+	var (
+		transforms = []fieldTransform{structTagTransform}
+		messages   []messageTransform
+		normalizer = newKeyNormalizer(l.keyCase)
+	)
+	if len(l.redactKeys) > 0 {
+		transforms = append(transforms, redactTransform(l.redactKeys, normalizer))
+	}
+	if len(l.hashFields) > 0 {
+		transforms = append(transforms, hashTransform(l.hashKey, l.hashFields, normalizer))
+	}
+	// Keys are normalized after redaction and hashing, which match both forms of the keys.
+	if normalizer != nil {
+		transforms = append(transforms, normalizer.transform)
+	}
+	if len(l.scrubPatterns) > 0 {
+		transforms = append(transforms, scrubTransform(l.scrubPatterns))
+		messages = append(messages, scrubMessage(l.scrubPatterns))
+	}
+	if l.offloadStore != nil {
+		transforms = append(transforms, offloadTransform(l.offloadStore, l.offloadThreshold))
+	}
+	// Duplicate keys are resolved below the transforms, which would otherwise have to
+	// rewrite the inherited fields for every entry again.
+	if l.duplicateKeys != DuplicateKeysAllow {
+		core = &duplicateKeysCore{Core: core, policy: l.duplicateKeys}
+	}
+	// Values are truncated below the transforms, so that large values are offloaded first.
+	if l.maxFieldBytes > 0 {
+		core = &truncateCore{Core: core, max: l.maxFieldBytes}
+	}
+	core = newTransformCore(core, transforms, messages)
+	if len(l.levelPrefixes) > 0 {
+		core = &levelPrefixCore{Core: core, prefixes: l.levelPrefixes}
+	}
+
+	// Error entries are recorded for LastError below the cores dropping entries.
+	core = &lastErrorCore{Core: core}
+
+	if l.dedupWindow > 0 {
+		l.dedup = &dedupState{window: l.dedupWindow}
+		core = newDedupCore(core, l.dedup)
+	}
+
+	if l.rateLimit != nil {
+		l.rateLimiter = newRateLimiter(*l.rateLimit)
+		core = newRateLimitCore(core, l.rateLimiter, l.recordDrop)
+	}
+
+	// Sampling decides in Check, so dropped entries never reach the other cores.
+	if l.sampling.tick > 0 {
+		core = newSamplingCore(core, l.sampling, l.recordDrop)
+	}
+
+	// The level gate comes last, so disabled entries are rejected before any other work.
+	return &levelCore{Core: core, level: l.level}
+}
+
+// callerSkip is the number of wrapper frames between the call site and zap:
+// the public logging method, log and write.
+const callerSkip = 3
+
+// recordDrop accounts for an entry that was dropped instead of written.
+func (l *Logger) recordDrop(ent zapcore.Entry) {
+	if l.stats != nil {
+		l.stats.recordDrop(ent)
+	}
+}
+
+// Info logs a message at InfoLevel, automatically including trace_id if available.
+func (l *Logger) Info(ctx context.Context, msg string, keyVals ...interface{}) {
+	l.log(ctx, zapcore.InfoLevel, msg, keyVals)
 }
 
 // Error logs a message at ErrorLevel, automatically including trace_id if available.
 func (l *Logger) Error(ctx context.Context, msg string, keyVals ...interface{}) {
-	if l.getTraceIDFn != nil {
-		if traceID := l.getTraceIDFn(ctx); traceID != "" {
-			keyVals = append(keyVals, "trace_id", traceID)
+	l.log(ctx, zapcore.ErrorLevel, msg, keyVals)
+}
+
+// Log logs a message at the given level, automatically including trace_id if available. It
+// serves adapters for libraries whose levels are only known at runtime, such as WarnLevel.
+// As with zap, PanicLevel panics and FatalLevel exits after the entry has been written.
+// DebugLevel entries are discarded in builds with the logger_nodebug tag.
+func (l *Logger) Log(ctx context.Context, level zapcore.Level, msg string, keyVals ...interface{}) {
+	if level == zapcore.DebugLevel && !DebugEnabled {
+		return
+	}
+	l.log(ctx, level, msg, keyVals)
+}
+
+// LogDepth is Log for adapters and helpers logging on behalf of their caller: the entry's
+// caller is the function depth frames above the caller of LogDepth, so LogDepth with depth 0
+// reports the same caller as Log.
+//
+//	func (a *adapter) Warn(msg string) {
+//		a.l.LogDepth(context.Background(), 1, zapcore.WarnLevel, msg)
+//	}
+func (l *Logger) LogDepth(ctx context.Context, depth int, level zapcore.Level, msg string, keyVals ...interface{}) {
+	if level == zapcore.DebugLevel && !DebugEnabled {
+		return
+	}
+	if depth != 0 {
+		l = l.withCallerSkip(depth)
+	}
+	l.log(ctx, level, msg, keyVals)
+}
+
+// CallerDepth returns the depth to pass to LogDepth for the first caller outside the package of
+// the function calling CallerDepth and the packages with the import paths pkgs, or one of their
+// subpackages. It is meant for adapters called through libraries adding a varying number of
+// frames, such as database/sql; adapters with a fixed depth should pass it to LogDepth instead.
+func CallerDepth(pkgs ...string) int {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	frame, more := frames.Next()
+	pkgs = append(pkgs, funcPackage(frame.Function))
+	depth := 0
+	for more {
+		frame, more = frames.Next()
+		if !inPackages(frame.Function, pkgs) {
+			return depth + 1
 		}
+		depth++
 	}
-	l.zapLogger.Errorw(msg, keyVals...)
+	return depth
+}
+
+// funcPackage returns the import path of the package of fn, a fully qualified function name
+// as reported by runtime.Frame.
+func funcPackage(fn string) string {
+	slash := strings.LastIndex(fn, "/") + 1
+	if dot := strings.Index(fn[slash:], "."); dot >= 0 {
+		return fn[:slash+dot]
+	}
+	return fn
+}
+
+// inPackages reports whether fn, a fully qualified function name, belongs to one of pkgs or
+// one of their subpackages.
+func inPackages(fn string, pkgs []string) bool {
+	for _, pkg := range pkgs {
+		if rest, ok := strings.CutPrefix(fn, pkg); ok && rest != "" && (rest[0] == '.' || rest[0] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+// withCallerSkip returns a shallow copy of l reporting the caller skip frames further up.
+func (l *Logger) withCallerSkip(skip int) *Logger {
+	child := *l
+	child.zapLogger = l.zapLogger.WithOptions(zap.AddCallerSkip(skip))
+	child.baseLogger = l.baseLogger.WithOptions(zap.AddCallerSkip(skip))
+	return &child
 }
 
-// Debug logs a message at DebugLevel, automatically including trace_id if available.
-func (l *Logger) Debug(ctx context.Context, msg string, keyVals ...interface{}) {
-	if l.getTraceIDFn != nil {
-		if traceID := l.getTraceIDFn(ctx); traceID != "" {
-			keyVals = append(keyVals, "trace_id", traceID)
+// log enriches keyVals with the fields derived from ctx and writes the entry.
+func (l *Logger) log(ctx context.Context, lvl zapcore.Level, msg string, keyVals []interface{}) {
+	keyVals = l.groupKeyVals(l.checkReservedKeys(l.validateKeyVals(keyVals, 0), 0))
+	minLevel, overridden := MinLevelFromContext(ctx)
+	if overridden {
+		l = l.withLevel(minLevel)
+	}
+	switch lvl {
+	case zapcore.DebugLevel:
+		switch {
+		case l.sampledDebug && !overridden && l.traceSampled(ctx):
+			l = l.withLevel(zapcore.DebugLevel)
+		case l.sampledDebug && !overridden:
+			l.holdDebug(ctx, msg, keyVals)
+			return
+		case l.holdDebug(ctx, msg, keyVals):
+			return
 		}
+	case zapcore.ErrorLevel:
+		keyVals = append(keyVals, l.precedingFields(ctx)...)
+	}
+	// Entries the level disables are not sampled, so that they are not counted as dropped.
+	if l.sampledOut(lvl, l.traceID(ctx)) && l.zapLogger.Desugar().Core().Enabled(lvl) {
+		l.recordDrop(zapcore.Entry{Level: lvl, Time: time.Now(), Message: msg})
+		return
 	}
-	l.zapLogger.Debugw(msg, keyVals...)
+	if lvl == zapcore.ErrorLevel {
+		keyVals = append(keyVals, l.recordError(ctx, msg, keyVals)...)
+	}
+	keyVals = append(keyVals, l.contextFields(ctx)...)
+	l.write(lvl, msg, append(keyVals, l.dynamicFieldValues(lvl)...))
+}
+
+// traceID returns the trace ID carried by ctx, or an empty string.
+func (l *Logger) traceID(ctx context.Context) string {
+	if l.getTraceCtx != nil {
+		traceID, _, _ := l.getTraceCtx(ctx)
+		return traceID
+	}
+	if l.getTraceIDFn == nil {
+		return ""
+	}
+	return l.getTraceIDFn(ctx)
+}
+
+// traceFields returns the trace_id field of the trace ID carried by ctx, if any, and the
+// span_id and trace_sampled fields with WithTraceContext.
+func (l *Logger) traceFields(ctx context.Context) []interface{} {
+	if l.getTraceCtx != nil {
+		traceID, spanID, sampled := l.getTraceCtx(ctx)
+		if traceID == "" {
+			return nil
+		}
+		keyVals := []interface{}{traceIDKey, traceID}
+		if spanID != "" {
+			keyVals = append(keyVals, spanIDKey, spanID)
+		}
+		return append(keyVals, traceSampledKey, sampled)
+	}
+	if traceID := l.traceID(ctx); traceID != "" {
+		return []interface{}{traceIDKey, traceID}
+	}
+	return nil
+}
+
+// contextFields returns the key-value pairs that are automatically derived from ctx.
+func (l *Logger) contextFields(ctx context.Context) []interface{} {
+	var keyVals []interface{}
+	for _, fn := range append([]GetFieldsFn{l.traceFields, idFields, operationFields}, l.getFieldsFns...) {
+		keyVals = append(keyVals, fn(ctx)...)
+	}
+	if len(l.contextHooks) > 0 {
+		keyVals = append(keyVals, contextField(ctx))
+	}
+	return append(keyVals, l.elapsedFields(ctx)...)
+}
+
+// write hands the entry to zap.
+func (l *Logger) write(lvl zapcore.Level, msg string, keyVals []interface{}) {
+	l.zapLogger.Logw(lvl, msg, keyVals...)
 }
 
 // With returns a child Logger that includes some default key-value pairs.
 // The child still auto-injects trace IDs.
 func (l *Logger) With(keyVals ...interface{}) *Logger {
-	// zap.SugaredLogger has a With(...) method that returns a new SugaredLogger
-	newSugared := l.zapLogger.With(keyVals...)
-	return &Logger{
-		zapLogger:    newSugared,
-		getTraceIDFn: l.getTraceIDFn,
-		level:        l.level,
-		outputPaths:  l.outputPaths,
+	return l.with(keyVals)
+}
+
+// Child is With returning the child as a Log, for code depending on the Log interface.
+func (l *Logger) Child(keyVals ...interface{}) Log {
+	return l.with(keyVals)
+}
+
+// with returns a child Logger including keyVals, for With and Child.
+func (l *Logger) with(keyVals []interface{}) *Logger {
+	keyVals = l.checkReservedKeys(l.validateKeyVals(keyVals, 0), 0)
+	child := *l
+	if g := l.group; g != nil {
+		// The pairs are held by the group, to be nested with those of the logging calls.
+		group := *g
+		group.keyVals = append(g.keyVals[:len(g.keyVals):len(g.keyVals)], keyVals...)
+		child.group = &group
+		return &child
 	}
+	// zap.SugaredLogger has a With(...) method that returns a new SugaredLogger
+	child.zapLogger = l.zapLogger.With(keyVals...)
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], keyVals...)
+	return &child
 }
 
-// Sync flushes any buffered log entries.
+// Sync flushes any buffered log entries. Failures of the output paths are returned as
+// *SyncError, except those reported benign by the SyncErrorFilter.
 func (l *Logger) Sync() error {
 	return l.zapLogger.Sync()
 }
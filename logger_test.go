@@ -2,13 +2,19 @@ package logger_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	logger "github.com/janduursma/zap-logger-wrapper/v2"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // memorySink is a simple zap.WriteSyncer that stores logs in a string builder.
@@ -31,7 +37,37 @@ func (m *memorySink) Close() error {
 	return nil
 }
 
+// sinkCounter makes each memory sink scheme unique, since zap sinks can only be registered once.
+var sinkCounter atomic.Int64
+
+// newMemoryLogger creates a logger writing to a fresh in-memory sink.
+func newMemoryLogger(t *testing.T, opts ...logger.Option) (*logger.Logger, *memorySink) {
+	t.Helper()
+
+	sink := &memorySink{}
+	scheme := registerSink(t, sink)
+
+	opts = append([]logger.Option{logger.WithOutputPaths([]string{scheme + "://"})}, opts...)
+	l, err := logger.New("test-service", opts...)
+	require.NoError(t, err, "failed to create logger")
+	return l, sink
+}
+
+// registerSink registers sink under a fresh scheme and returns the scheme.
+func registerSink(t *testing.T, sink zap.Sink) string {
+	t.Helper()
+
+	scheme := fmt.Sprintf("memory%d", sinkCounter.Add(1))
+	require.NoError(t, zap.RegisterSink(scheme, func(_ *url.URL) (zap.Sink, error) {
+		return sink, nil
+	}), "failed to register memory sink")
+	return scheme
+}
+
 func TestLogger(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
 	// Register a custom sink to specify the output path.
 	sink := &memorySink{}
 	require.NoError(t, zap.RegisterSink("test", func(_ *url.URL) (zap.Sink, error) {
@@ -85,3 +121,118 @@ func TestLogger(t *testing.T) {
 	err = l.Sync()
 	require.NoError(t, err, "logger.Sync() should not return an error")
 }
+
+func TestCallerIsCallSite(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	l.Info(context.Background(), "where am I")
+	l.With("k", "v").Error(context.Background(), "and now")
+
+	for _, entry := range decodeLines(t, sink) {
+		require.Contains(t, entry["caller"], "logger_test.go", "caller should point at the call site, not the wrapper")
+	}
+}
+
+func TestWithCore(t *testing.T) {
+	core, observed := observer.New(zap.InfoLevel)
+	l, sink := newMemoryLogger(t, logger.WithCore(core))
+
+	l.With("component", "billing").Info(context.Background(), "teed")
+
+	require.Contains(t, sink.logs.String(), `"msg":"teed"`, "output paths should still receive entries")
+	require.Equal(t, 1, observed.Len(), "extra core should receive entries")
+	fields := observed.All()[0].ContextMap()
+	require.Equal(t, "test-service", fields["service"], "extra core should receive the service field")
+	require.Equal(t, "billing", fields["component"])
+}
+
+func TestWithCoreRespectsCoreLevel(t *testing.T) {
+	core, observed := observer.New(zap.ErrorLevel)
+	// Hooks wrap the extra cores, so their level must still be honored on write.
+	l, sink := newMemoryLogger(t, logger.WithCore(core), logger.WithHook(func(zapcore.Entry, []zapcore.Field) error {
+		return nil
+	}))
+
+	l.Info(context.Background(), "info only goes to the output paths")
+	l.Error(context.Background(), "error goes everywhere")
+
+	require.Contains(t, sink.logs.String(), "info only goes to the output paths")
+	require.Equal(t, 1, observed.Len(), "extra core should only receive entries it is enabled for")
+	require.Equal(t, "error goes everywhere", observed.All()[0].Message)
+}
+
+func TestWithExistingZap(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	existing := zap.New(core, zap.AddCaller()).With(zap.String("team", "payments"))
+
+	l, err := logger.New("test-service",
+		logger.WithExistingZap(existing),
+		logger.WithTraceID(func(_ context.Context) string { return "trace-1" }),
+	)
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "wrapped", "key", "value")
+	l.Debug(context.Background(), "filtered by the default level")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	require.Equal(t, map[string]any{
+		"team": "payments", "key": "value", "trace_id": "trace-1", "service": "test-service",
+	}, entries[0].ContextMap())
+	require.Contains(t, entries[0].Caller.File, "logger_test.go", "caller should be the call site")
+}
+
+func TestNewNop(t *testing.T) {
+	l := logger.NewNop()
+	ctx := context.Background()
+
+	require.NotPanics(t, func() {
+		l.Debug(ctx, "debug")
+		l.Info(ctx, "info", "key", "value")
+		l.Error(ctx, "error", "error", errors.New("boom"))
+		l.With("component", "signup").Without("component").Info(ctx, "child")
+		l.ForLibrary("db", zapcore.DebugLevel).Debug(ctx, "library")
+		l.Snapshot(ctx).Info("snapshot")
+		_, _ = l.LastError(ctx)
+		_ = l.Stats()
+	})
+	require.False(t, l.Enabled(zapcore.ErrorLevel))
+	require.NoError(t, l.Ping(ctx))
+	require.NoError(t, l.Flush(ctx))
+	require.NoError(t, l.Sync())
+	require.NoError(t, l.Close(ctx))
+}
+
+func TestLog(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	ctx := context.WithValue(context.Background(), traceKey{}, "req-1")
+
+	l.Log(ctx, zapcore.WarnLevel, "slow query", "duration_ms", 1500)
+	l.Log(ctx, zapcore.DebugLevel, "filtered")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "warn", entries[0]["level"])
+	require.Equal(t, "req-1", entries[0]["trace_id"])
+	require.Contains(t, entries[0]["caller"], "logger_test.go")
+}
+
+// logVia logs msg through LogDepth on behalf of its caller.
+func logVia(l *logger.Logger, msg string) {
+	l.LogDepth(context.Background(), 1, zapcore.WarnLevel, msg)
+}
+
+func TestLogDepth(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	l.LogDepth(context.Background(), 0, zapcore.InfoLevel, "direct")
+	_, _, line, _ := runtime.Caller(0)
+	logVia(l, "via a helper")
+	logVia(l.With("k", "v"), "via a child")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 3)
+	require.Regexp(t, fmt.Sprintf(`/logger_test\.go:%d$`, line-1), entries[0]["caller"])
+	require.Regexp(t, fmt.Sprintf(`/logger_test\.go:%d$`, line+1), entries[1]["caller"], "the helper's caller should be reported")
+	require.Regexp(t, fmt.Sprintf(`/logger_test\.go:%d$`, line+2), entries[2]["caller"])
+}
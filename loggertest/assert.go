@@ -0,0 +1,72 @@
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// AssertLogged checks that observed holds an entry at level whose message contains msgSubstr
+// and that holds the fields of the key-value pairs keyVals, compared loosely as with Field:
+//
+//	loggertest.AssertLogged(t, entries, zapcore.ErrorLevel, "charge failed", "amount", 5)
+//
+// It reports the observed entries when there is none, and returns whether one was found.
+func AssertLogged(t TestingT, observed Source, level zapcore.Level, msgSubstr string, keyVals ...interface{}) bool {
+	t.Helper()
+
+	entries := observed.All()
+	if findLogged(entries, level, msgSubstr, keyVals) >= 0 {
+		return true
+	}
+	t.Errorf("expected entry not logged: %s\nobserved entries:\n%s",
+		describeLogged(level, msgSubstr, keyVals), formatEntries(entries))
+	return false
+}
+
+// AssertNotLogged checks that observed holds no entry matching level, msgSubstr and keyVals,
+// as described by AssertLogged. It reports the first matching entry, and returns whether
+// there was none.
+func AssertNotLogged(t TestingT, observed Source, level zapcore.Level, msgSubstr string, keyVals ...interface{}) bool {
+	t.Helper()
+
+	entries := observed.All()
+	i := findLogged(entries, level, msgSubstr, keyVals)
+	if i < 0 {
+		return true
+	}
+	t.Errorf("unexpected entry logged: %s\nmatching entry %d: %s %q %v",
+		describeLogged(level, msgSubstr, keyVals), i+1, entries[i].Level, entries[i].Message, entries[i].ContextMap())
+	return false
+}
+
+// findLogged returns the index of the first entry matching level, msgSubstr and keyVals, or -1.
+func findLogged(entries []observer.LoggedEntry, level zapcore.Level, msgSubstr string, keyVals []interface{}) int {
+	for i, entry := range entries {
+		if entry.Level != level || !strings.Contains(entry.Message, msgSubstr) {
+			continue
+		}
+		fields := entry.ContextMap()
+		matches := true
+		for j := 0; j+1 < len(keyVals) && matches; j += 2 {
+			v, ok := fields[fmt.Sprint(keyVals[j])]
+			matches = ok && assert.ObjectsAreEqualValues(keyVals[j+1], v)
+		}
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeLogged describes the entries matched by AssertLogged for failure messages.
+func describeLogged(level zapcore.Level, msgSubstr string, keyVals []interface{}) string {
+	s := fmt.Sprintf("%s containing %q", level, msgSubstr)
+	for j := 0; j+1 < len(keyVals); j += 2 {
+		s += fmt.Sprintf(" %v=%v", keyVals[j], keyVals[j+1])
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package loggertest_test
+
+import (
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAssertLogged(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	observed := observedFlow(t)
+
+	require.True(t, loggertest.AssertLogged(t, observed, zapcore.ErrorLevel, "fail", "code", 500))
+	require.True(t, loggertest.AssertLogged(t, observed, zapcore.DebugLevel, "connect"))
+	require.True(t, loggertest.AssertNotLogged(t, observed, zapcore.ErrorLevel, "failed", "code", 404))
+	require.True(t, loggertest.AssertNotLogged(t, observed, zapcore.InfoLevel, "failed"))
+}
+
+func TestAssertLoggedFailures(t *testing.T) {
+	observed := observedFlow(t)
+
+	rec := &recordingT{}
+	require.False(t, loggertest.AssertLogged(rec, observed, zapcore.ErrorLevel, "failed", "code", 404))
+	require.Len(t, rec.failures, 1)
+	require.Contains(t, rec.failures[0], `error containing "failed" code=404`)
+	require.Contains(t, rec.failures[0], "observed entries:")
+
+	rec = &recordingT{}
+	require.False(t, loggertest.AssertNotLogged(rec, observed, zapcore.InfoLevel, "start"))
+	require.Len(t, rec.failures, 1)
+	require.Contains(t, rec.failures[0], `matching entry 1: info "starting"`)
+}
@@ -0,0 +1,65 @@
+package loggertest
+
+import (
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Entries records the entries of a Logger created by NewTestLogger, or a filtered subset of
+// them. It implements Source.
+type Entries struct {
+	observed *observer.ObservedLogs
+}
+
+// NewTestLogger returns a Logger for the service "test-service" that records its entries in
+// memory instead of writing them, at every level, and the recorder of its entries. opts are
+// applied after the defaults of the test logger. The Logger is synced when the test ends.
+func NewTestLogger(t testing.TB, opts ...logger.Option) (*logger.Logger, *Entries) {
+	t.Helper()
+
+	core, observed := observer.New(zapcore.DebugLevel)
+	opts = append([]logger.Option{
+		logger.WithOutputPaths([]string{}),
+		logger.WithCore(core),
+		logger.WithLevel(zapcore.DebugLevel),
+	}, opts...)
+	l, err := logger.New("test-service", opts...)
+	if err != nil {
+		t.Fatalf("create test logger: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Sync() })
+	return l, &Entries{observed: observed}
+}
+
+// All returns the recorded entries, in the order they were logged.
+func (e *Entries) All() []observer.LoggedEntry {
+	return e.observed.All()
+}
+
+// Len returns the number of recorded entries.
+func (e *Entries) Len() int {
+	return e.observed.Len()
+}
+
+// FilterMessage returns the entries with the message msg.
+func (e *Entries) FilterMessage(msg string) *Entries {
+	return &Entries{observed: e.observed.FilterMessage(msg)}
+}
+
+// FilterLevel returns the entries at level.
+func (e *Entries) FilterLevel(level zapcore.Level) *Entries {
+	return &Entries{observed: e.observed.FilterLevelExact(level)}
+}
+
+// FilterField returns the entries holding a field with the given key and value. Values are
+// compared loosely, as with Field.
+func (e *Entries) FilterField(key string, value interface{}) *Entries {
+	return &Entries{observed: e.observed.Filter(func(entry observer.LoggedEntry) bool {
+		v, ok := entry.ContextMap()[key]
+		return ok && assert.ObjectsAreEqualValues(value, v)
+	})}
+}
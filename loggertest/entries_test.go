@@ -0,0 +1,33 @@
+package loggertest_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewTestLogger(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, entries := loggertest.NewTestLogger(t)
+
+	ctx := context.Background()
+	l.Debug(ctx, "connecting", "attempt", 1)
+	l.Info(ctx, "request", "status", 200)
+	l.Info(ctx, "request", "status", 404)
+	l.Error(ctx, "failed", "status", 500)
+
+	require.Equal(t, 4, entries.Len())
+	require.Equal(t, 2, entries.FilterMessage("request").Len())
+	require.Equal(t, 1, entries.FilterMessage("request").FilterField("status", 404).Len())
+	require.Equal(t, 1, entries.FilterLevel(zapcore.ErrorLevel).Len())
+	require.Zero(t, entries.FilterField("status", 302).Len())
+	require.Equal(t, "test-service", entries.All()[0].ContextMap()["service"])
+
+	loggertest.Expect().Info("request").ThenError("failed").Assert(t, entries)
+}
@@ -0,0 +1,159 @@
+// Package loggertest provides helpers for testing code that logs through the logger package.
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Source provides the entries logged during a test. *observer.ObservedLogs implements it,
+// so the entries of a logger created with logger.WithCore(observerCore) can be asserted.
+type Source interface {
+	All() []observer.LoggedEntry
+}
+
+// FieldMatcher is an expectation on a field of an entry, see Field.
+type FieldMatcher struct {
+	key   string
+	value interface{}
+}
+
+// Field expects an entry to hold a field with the given key and value. Values are compared
+// loosely, so Field("code", 500) matches integers of any type.
+func Field(key string, value interface{}) FieldMatcher {
+	return FieldMatcher{key: key, value: value}
+}
+
+// String implements fmt.Stringer.
+func (f FieldMatcher) String() string {
+	return fmt.Sprintf("%s=%v", f.key, f.value)
+}
+
+// expectation is a single expected entry.
+type expectation struct {
+	level  zapcore.Level
+	msg    string
+	fields []FieldMatcher
+}
+
+// String implements fmt.Stringer.
+func (e expectation) String() string {
+	s := fmt.Sprintf("%s %q", e.level, e.msg)
+	for _, f := range e.fields {
+		s += " " + f.String()
+	}
+	return s
+}
+
+// matches reports whether entry meets the expectation.
+func (e expectation) matches(entry observer.LoggedEntry) bool {
+	if entry.Level != e.level || entry.Message != e.msg {
+		return false
+	}
+	fields := entry.ContextMap()
+	for _, f := range e.fields {
+		v, ok := fields[f.key]
+		if !ok || !assert.ObjectsAreEqualValues(f.value, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sequence is an ordered list of expected entries, built with Expect:
+//
+//	seq := loggertest.Expect().Info("starting").ThenError("failed", loggertest.Field("code", 500))
+//	seq.Assert(t, observed)
+type Sequence struct {
+	expected []expectation
+}
+
+// Expect starts an empty sequence of expected entries.
+func Expect() *Sequence {
+	return &Sequence{}
+}
+
+// Then expects an entry at level with message msg and the given fields after the entries
+// expected so far.
+func (s *Sequence) Then(level zapcore.Level, msg string, fields ...FieldMatcher) *Sequence {
+	s.expected = append(s.expected, expectation{level: level, msg: msg, fields: fields})
+	return s
+}
+
+// Debug expects a Debug entry. It reads best as the first expectation of a sequence.
+func (s *Sequence) Debug(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Then(zapcore.DebugLevel, msg, fields...)
+}
+
+// Info expects an Info entry. It reads best as the first expectation of a sequence.
+func (s *Sequence) Info(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Then(zapcore.InfoLevel, msg, fields...)
+}
+
+// Error expects an Error entry. It reads best as the first expectation of a sequence.
+func (s *Sequence) Error(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Then(zapcore.ErrorLevel, msg, fields...)
+}
+
+// ThenDebug expects a Debug entry after the entries expected so far.
+func (s *Sequence) ThenDebug(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Debug(msg, fields...)
+}
+
+// ThenInfo expects an Info entry after the entries expected so far.
+func (s *Sequence) ThenInfo(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Info(msg, fields...)
+}
+
+// ThenError expects an Error entry after the entries expected so far.
+func (s *Sequence) ThenError(msg string, fields ...FieldMatcher) *Sequence {
+	return s.Error(msg, fields...)
+}
+
+// Assert checks that observed holds the expected entries in order. Other entries may be
+// logged before, between and after them. It reports the first expectation that is not met,
+// together with the observed entries, and returns whether the sequence was found.
+func (s *Sequence) Assert(t TestingT, observed Source) bool {
+	t.Helper()
+
+	entries := observed.All()
+	next := 0
+	for i, exp := range s.expected {
+		found := false
+		for ; next < len(entries); next++ {
+			if exp.matches(entries[next]) {
+				found = true
+				next++
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected entry %d of the sequence not found: %s\nobserved entries:\n%s",
+				i+1, exp, formatEntries(entries))
+			return false
+		}
+	}
+	return true
+}
+
+// formatEntries lists entries for failure messages.
+func formatEntries(entries []observer.LoggedEntry) string {
+	if len(entries) == 0 {
+		return "  (none)"
+	}
+	var b strings.Builder
+	for i, e := range entries {
+		fmt.Fprintf(&b, "  %d. %s %q %v\n", i+1, e.Level, e.Message, e.ContextMap())
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,81 @@
+package loggertest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordingT is a loggertest.TestingT recording the reported failures.
+type recordingT struct {
+	failures []string
+}
+
+// Helper implements loggertest.TestingT.
+func (r *recordingT) Helper() {}
+
+// Errorf implements loggertest.TestingT.
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// observedFlow logs a typical flow and returns the observed entries.
+func observedFlow(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, observed := observer.New(zapcore.DebugLevel)
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{}),
+		logger.WithCore(core),
+		logger.WithLevel(zapcore.DebugLevel),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	l.Info(ctx, "starting")
+	l.Debug(ctx, "connecting", "attempt", 1)
+	l.Error(ctx, "failed", "code", 500)
+	l.Info(ctx, "stopped")
+	return observed
+}
+
+func TestSequenceAssert(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	observed := observedFlow(t)
+
+	loggertest.Expect().Info("starting").ThenError("failed", loggertest.Field("code", 500)).Assert(t, observed)
+	loggertest.Expect().
+		Info("starting").
+		ThenDebug("connecting", loggertest.Field("attempt", 1)).
+		ThenError("failed").
+		ThenInfo("stopped", loggertest.Field("service", "test-service")).
+		Assert(t, observed)
+}
+
+func TestSequenceAssertFailures(t *testing.T) {
+	observed := observedFlow(t)
+
+	for name, seq := range map[string]*loggertest.Sequence{
+		"wrong order":   loggertest.Expect().Error("failed").ThenInfo("starting"),
+		"wrong level":   loggertest.Expect().Info("failed"),
+		"wrong field":   loggertest.Expect().Error("failed", loggertest.Field("code", 404)),
+		"missing field": loggertest.Expect().Info("starting", loggertest.Field("code", 500)),
+		"missing entry": loggertest.Expect().Info("starting").ThenInfo("restarted"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := &recordingT{}
+			require.False(t, seq.Assert(rec, observed))
+			require.Len(t, rec.failures, 1)
+			require.Contains(t, rec.failures[0], "observed entries:")
+			require.Contains(t, rec.failures[0], `error "failed"`)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package loggertest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewForTesting returns a Logger for the service "test-service" writing its entries through
+// t.Log, so that they appear interleaved with the test's output, and, without -v, only when
+// the test fails. Entries are written in the console format without colors, at every level;
+// opts are applied after these defaults. The Logger is synced when the test ends, and entries
+// logged afterwards, e.g. by goroutines that outlive the test, are discarded instead of
+// making the test panic.
+func NewForTesting(t testing.TB, opts ...logger.Option) *logger.Logger {
+	t.Helper()
+
+	w := &testingWriter{t: t}
+	opts = append([]logger.Option{
+		logger.WithOutputPaths([]string{}),
+		logger.WithFormat(logger.FormatConsole),
+		logger.WithColor(logger.ColorNever),
+		logger.WithLevel(zapcore.DebugLevel),
+		logger.WithOutputWriter(w),
+	}, opts...)
+	l, err := logger.New("test-service", opts...)
+	if err != nil {
+		t.Fatalf("create logger for testing: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = l.Sync()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.done = true
+	})
+	return l
+}
+
+// testingWriter is a zapcore.WriteSyncer writing entries through t.Log.
+type testingWriter struct {
+	t    testing.TB
+	mu   sync.Mutex
+	done bool
+}
+
+// Write implements io.Writer, logging p through t.Log.
+func (w *testingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done {
+		return len(p), nil
+	}
+	w.t.Log(string(bytes.TrimSuffix(p, []byte("\n"))))
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer. Entries are logged as they are written.
+func (w *testingWriter) Sync() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package loggertest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTB is a testing.TB recording the logged lines and cleanup functions.
+type recordingTB struct {
+	testing.TB
+	lines    []string
+	cleanups []func()
+}
+
+// Log implements testing.TB.
+func (r *recordingTB) Log(args ...any) {
+	r.lines = append(r.lines, fmt.Sprint(args...))
+}
+
+// Cleanup implements testing.TB.
+func (r *recordingTB) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// Helper implements testing.TB.
+func (r *recordingTB) Helper() {}
+
+func TestNewForTesting(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	tb := &recordingTB{TB: t}
+	l := loggertest.NewForTesting(tb)
+
+	l.Debug(context.Background(), "connecting", "attempt", 1)
+	require.Len(t, tb.lines, 1)
+	require.Contains(t, tb.lines[0], "connecting")
+	require.Contains(t, tb.lines[0], `"attempt": 1`)
+	require.NotContains(t, tb.lines[0], "\n")
+
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	l.Info(context.Background(), "after the test")
+	require.Len(t, tb.lines, 1, "entries logged after the test should be discarded")
+}
+
+func TestNewForTestingWritesToT(t *testing.T) {
+	l := loggertest.NewForTesting(t)
+	l.Info(context.Background(), "visible with -v")
+}
@@ -0,0 +1,53 @@
+package loggertest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// charge is code under test depending on the Log interface.
+func charge(ctx context.Context, log logger.Log, amount int) error {
+	if amount <= 0 {
+		err := errors.New("invalid amount")
+		log.Error(ctx, "charge failed", "amount", amount, "error", err)
+		return err
+	}
+	log.Child("amount", amount).Info(ctx, "charged")
+	if amount > 1000 {
+		log.Log(ctx, zapcore.WarnLevel, "large charge", "amount", amount)
+	}
+	return nil
+}
+
+func TestMockLog(t *testing.T) {
+	log := loggertest.NewMockLog(t)
+	log.EXPECT().Error(mock.Anything, "charge failed", "amount", -1, "error", mock.Anything).Once()
+
+	require.Error(t, charge(context.Background(), log, -1))
+}
+
+func TestMockLogChild(t *testing.T) {
+	child := loggertest.NewMockLog(t)
+	child.EXPECT().Info(mock.Anything, "charged").Once()
+	log := loggertest.NewMockLog(t)
+	log.EXPECT().Child("amount", 5).Return(child).Once()
+
+	require.NoError(t, charge(context.Background(), log, 5))
+}
+
+func TestMockLogLevel(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	log := loggertest.NewMockLog(t)
+	log.EXPECT().Child("amount", 5000).Return(l).Once()
+	log.EXPECT().Log(mock.Anything, zapcore.WarnLevel, "large charge", "amount", 5000).Once()
+
+	require.NoError(t, charge(context.Background(), log, 5000))
+	require.Equal(t, 1, entries.FilterMessage("charged").Len(), "the child Logger should write the entry")
+}
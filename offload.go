@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// offloadTimeout bounds how long a single payload may take to be stored.
+const offloadTimeout = 10 * time.Second
+
+// BlobStore stores the payloads offloaded from log entries, see WithOffload.
+// Implementations for remote stores, such as S3, live under contrib/.
+type BlobStore interface {
+	// Put stores data under name and returns a URL at which it can be retrieved.
+	Put(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// WithOffload moves field values larger than threshold bytes to store and replaces them in
+// the entry by a reference holding the returned URL, the payload's SHA-256 hash and its size.
+// This keeps log lines small while keeping large payloads, such as request bodies,
+// recoverable. Strings, byte strings and structured values are offloaded; structured values
+// are stored as JSON. Values are offloaded after redaction, hashing and scrubbing.
+//
+// Payloads are stored synchronously, under their hash, while the entry is logged. If a
+// payload cannot be stored, the value is kept in the entry.
+func WithOffload(store BlobStore, threshold int) Option {
+	return func(l *Logger) {
+		l.offloadStore = store
+		l.offloadThreshold = threshold
+	}
+}
+
+// blobRef is the reference that replaces an offloaded value.
+type blobRef struct {
+	url  string
+	hash string
+	size int
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (r blobRef) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("ref", r.url)
+	enc.AddString("sha256", r.hash)
+	enc.AddInt("size", r.size)
+	return nil
+}
+
+// offloadTransform returns a fieldTransform that offloads large values to store.
+func offloadTransform(store BlobStore, threshold int) fieldTransform {
+	return func(f zapcore.Field) zapcore.Field {
+		data, ok := fieldPayload(f)
+		if !ok || len(data) <= threshold {
+			return f
+		}
+
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		ctx, cancel := context.WithTimeout(context.Background(), offloadTimeout)
+		defer cancel()
+		ref, err := store.Put(ctx, hash, data)
+		if err != nil {
+			return f
+		}
+		return zap.Object(f.Key, blobRef{url: ref, hash: hash, size: len(data)})
+	}
+}
+
+// fieldPayload returns the bytes stored for an offloaded field, and whether the field's
+// type can be offloaded.
+func fieldPayload(f zapcore.Field) ([]byte, bool) {
+	switch f.Type {
+	case zapcore.StringType:
+		return []byte(f.String), true
+	case zapcore.ByteStringType, zapcore.BinaryType:
+		data, ok := f.Interface.([]byte)
+		return data, ok
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		data, err := json.Marshal(enc.Fields[f.Key])
+		return data, err == nil
+	default:
+		return nil, false
+	}
+}
+
+// DirStore is a BlobStore writing payloads as files to a local directory, for example a
+// volume that is shipped alongside the logs.
+type DirStore struct {
+	// Dir is the directory the payloads are written to. It must exist.
+	Dir string
+}
+
+// Put implements BlobStore. It returns a file:// URL.
+func (s DirStore) Put(_ context.Context, name string, data []byte) (string, error) {
+	path, err := filepath.Abs(filepath.Join(s.Dir, name))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	// Windows paths start with a drive letter, which file URLs put after a slash.
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String(), nil
+}
@@ -0,0 +1,92 @@
+package logger_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithOffload(t *testing.T) {
+	dir := t.TempDir()
+	l, sink := newMemoryLogger(t, logger.WithOffload(logger.DirStore{Dir: dir}, 16))
+	ctx := context.Background()
+
+	body := strings.Repeat("x", 100)
+	l.Info(ctx, "request", "body", body, "method", "POST", "headers", map[string]string{"accept": strings.Repeat("y", 20)})
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "POST", entries[0]["method"], "small values should be kept inline")
+
+	ref, ok := entries[0]["body"].(map[string]any)
+	require.True(t, ok, "large values should be replaced by a reference")
+	sum := sha256.Sum256([]byte(body))
+	require.Equal(t, hex.EncodeToString(sum[:]), ref["sha256"])
+	require.EqualValues(t, len(body), ref["size"])
+
+	u, err := url.Parse(ref["ref"].(string))
+	require.NoError(t, err)
+	require.Equal(t, "file", u.Scheme)
+	require.True(t, strings.HasSuffix(u.Path, "/"+ref["sha256"].(string)), "payloads should be stored under their hash")
+	data, err := os.ReadFile(filepath.Join(dir, ref["sha256"].(string)))
+	require.NoError(t, err)
+	require.Equal(t, body, string(data))
+
+	headers, ok := entries[0]["headers"].(map[string]any)
+	require.True(t, ok)
+	require.Contains(t, headers, "ref", "structured values should be offloaded as JSON")
+}
+
+func TestWithOffloadAfterRedaction(t *testing.T) {
+	store := &memoryStore{}
+	l, _ := newMemoryLogger(t,
+		logger.WithOffload(store, 16),
+		logger.WithRedactKeys("password"),
+		logger.WithScrubPatterns(emailPattern),
+	)
+
+	l.Info(context.Background(), "signup", zap.Any("form", map[string]string{
+		"password": "hunter2", "email": "jane@example.com", "bio": strings.Repeat("z", 20),
+	}))
+
+	require.Len(t, store.blobs, 1)
+	for _, data := range store.blobs {
+		require.NotContains(t, string(data), "hunter2")
+		require.NotContains(t, string(data), "jane@example.com")
+	}
+}
+
+func TestWithOffloadKeepsValueOnError(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithOffload(&memoryStore{err: errors.New("unavailable")}, 16))
+
+	l.Info(context.Background(), "request", "body", strings.Repeat("x", 20))
+	require.Equal(t, strings.Repeat("x", 20), decodeLines(t, sink)[0]["body"])
+}
+
+// memoryStore is a logger.BlobStore keeping payloads in memory.
+type memoryStore struct {
+	blobs map[string][]byte
+	err   error
+}
+
+// Put implements logger.BlobStore.
+func (s *memoryStore) Put(_ context.Context, name string, data []byte) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if s.blobs == nil {
+		s.blobs = map[string][]byte{}
+	}
+	s.blobs[name] = data
+	return "mem://" + name, nil
+}
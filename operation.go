@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// operationKey is the key of the field holding the name of the operation an entry belongs to.
+const operationKey = "operation"
+
+// operationContextKey is the context key of the name of the current operation.
+type operationContextKey struct{}
+
+// Operation is a unit of work started by StartOperation, whose outcome is logged by End.
+type Operation struct {
+	l     *Logger
+	ctx   context.Context // The context returned by StartOperation, carrying the name.
+	start time.Time
+}
+
+// StartOperation starts the operation name, e.g. "charge_payment". The returned context
+// carries the name, so the entries logged with it, or a context derived from it, include it
+// as operation; nested operations replace it. Call End when the operation is done:
+//
+//	op, ctx := log.StartOperation(ctx, "charge_payment")
+//	err := charge(ctx)
+//	op.End(err)
+func (l *Logger) StartOperation(ctx context.Context, name string) (*Operation, context.Context) {
+	ctx = context.WithValue(ctx, operationContextKey{}, name)
+	return &Operation{l: l, ctx: ctx, start: time.Now()}, ctx
+}
+
+// End logs the operation with the message "operation finished", its name, the duration_ms
+// since StartOperation and its outcome: success at InfoLevel, or error at ErrorLevel with err.
+// Further key-value pairs are added to the entry.
+func (op *Operation) End(err error, keyVals ...interface{}) {
+	// The name is added from op.ctx, like for the entries logged during the operation, which
+	// also keeps the name of an enclosing operation out of the entry.
+	fields := make([]interface{}, 0, 6+len(keyVals))
+	fields = append(fields, "duration_ms", float64(time.Since(op.start).Microseconds())/1000)
+	lvl := zapcore.InfoLevel
+	if err != nil {
+		lvl = zapcore.ErrorLevel
+		fields = append(fields, "outcome", "error", "error", err)
+	} else {
+		fields = append(fields, "outcome", "success")
+	}
+	op.l.log(op.ctx, lvl, "operation finished", append(fields, keyVals...))
+}
+
+// operationFields returns the operation field of the operation carried by ctx, if any.
+func operationFields(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	if name, ok := ctx.Value(operationContextKey{}).(string); ok {
+		return []interface{}{operationKey, name}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartOperation(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	op, ctx := l.StartOperation(context.Background(), "charge_payment")
+	l.Info(ctx, "charging")
+	op.End(nil, "amount", 42)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "charge_payment", entries[0]["operation"], "entries within the operation should carry its name")
+	require.Equal(t, "info", entries[1]["level"])
+	require.Equal(t, "operation finished", entries[1]["msg"])
+	require.Equal(t, "charge_payment", entries[1]["operation"])
+	require.Equal(t, "success", entries[1]["outcome"])
+	require.Equal(t, float64(42), entries[1]["amount"])
+	require.Contains(t, entries[1], "duration_ms")
+	require.NotContains(t, entries[1], "error")
+}
+
+func TestStartOperationError(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	op, _ := l.StartOperation(context.Background(), "charge_payment")
+	op.End(errors.New("card declined"))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "error", entries[0]["level"])
+	require.Equal(t, "error", entries[0]["outcome"])
+	require.Equal(t, "card declined", entries[0]["error"])
+}
+
+func TestStartOperationNested(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	outer, ctx := l.StartOperation(context.Background(), "checkout")
+	inner, _ := l.StartOperation(ctx, "charge_payment")
+	inner.End(nil)
+	outer.End(nil)
+
+	lines := strings.Split(strings.TrimSpace(sink.logs.String()), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		require.Equal(t, 1, strings.Count(line, `"operation":`), "the operation should be written once: %s", line)
+	}
+	entries := decodeLines(t, sink)
+	require.Equal(t, "charge_payment", entries[0]["operation"])
+	require.Equal(t, "checkout", entries[1]["operation"])
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OverflowPolicy selects what happens to an entry when buffered writes cannot keep up
+// with the rate at which entries are logged.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the logging call wait until the buffer has been written out.
+	// No entries are lost, but a slow output slows down the application. This is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop hands entries to a background writer through a bounded queue and drops
+	// them when the queue is full, so logging never blocks on a slow output.
+	OverflowDrop
+)
+
+// asyncQueueLen is the number of entries the background writer of OverflowDrop can hold.
+const asyncQueueLen = 1024
+
+// defaultFlushInterval matches the default of zapcore.BufferedWriteSyncer.
+const defaultFlushInterval = 30 * time.Second
+
+// WithOverflowPolicy selects the overflow behavior of WithBufferedWrites; it has no effect
+// without it. With OverflowDrop, the number of dropped entries is reported by DroppedWrites
+// and counted in Stats, and a warning with the number of entries dropped since the previous
+// one is logged every flush interval in which entries were dropped.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(l *Logger) {
+		l.overflow = policy
+	}
+}
+
+// DroppedWrites returns the number of entries dropped so far because the background writer
+// of OverflowDrop could not keep up.
+func (l *Logger) DroppedWrites() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.dropped.Load()
+}
+
+// asyncWriter is a zapcore.WriteSyncer handing writes to a background goroutine through a
+// bounded queue, dropping them when the queue is full.
+type asyncWriter struct {
+	ws       zapcore.WriteSyncer
+	queue    chan asyncWrite
+	quit     chan struct{}
+	stopOnce sync.Once
+	stopErr  error
+	dropped  atomic.Uint64
+	onDrop   func()
+	interval time.Duration
+}
+
+// asyncWrite is a queued write, or a request to sync when synced is set.
+type asyncWrite struct {
+	p      []byte
+	synced chan error
+}
+
+// newAsyncWriter creates an asyncWriter writing to ws. Its goroutine is started by start.
+func newAsyncWriter(ws zapcore.WriteSyncer, interval time.Duration, onDrop func()) *asyncWriter {
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	return &asyncWriter{
+		ws:       ws,
+		queue:    make(chan asyncWrite, asyncQueueLen),
+		quit:     make(chan struct{}),
+		onDrop:   onDrop,
+		interval: interval,
+	}
+}
+
+// Write implements io.Writer. It never blocks and never fails; entries that do not fit in
+// the queue are counted as dropped. Once the writer is stopped, p is written to ws directly.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	if w.stopped() {
+		return w.ws.Write(p)
+	}
+	// zap reuses the encoding buffer once Write returns.
+	req := asyncWrite{p: append([]byte(nil), p...)}
+	select {
+	case w.queue <- req:
+	default:
+		w.dropped.Add(1)
+		w.onDrop()
+	}
+	return len(p), nil
+}
+
+// Sync waits until the queued writes have been written out and synced. Once the writer is
+// stopped, it syncs ws directly.
+func (w *asyncWriter) Sync() error {
+	if w.stopped() {
+		return w.ws.Sync()
+	}
+	synced := make(chan error, 1)
+	select {
+	case w.queue <- asyncWrite{synced: synced}:
+	case <-w.quit:
+		return w.ws.Sync()
+	}
+	select {
+	case err := <-synced:
+		return err
+	case <-w.quit:
+		// The writer stopped before handling the request.
+		return w.ws.Sync()
+	}
+}
+
+// stopped reports whether Stop was called.
+func (w *asyncWriter) stopped() bool {
+	select {
+	case <-w.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// start runs the background writer, which calls report every interval in which entries
+// were dropped.
+func (w *asyncWriter) start(report func(dropped uint64)) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		var reported uint64
+		for {
+			select {
+			case req := <-w.queue:
+				if req.synced != nil {
+					req.synced <- w.ws.Sync()
+					continue
+				}
+				// Errors cannot be returned to the caller anymore; the entry is lost.
+				_, _ = w.ws.Write(req.p)
+			case <-ticker.C:
+				if dropped := w.dropped.Load(); dropped > reported {
+					report(dropped - reported)
+					reported = dropped
+				}
+			case <-w.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop writes out the queued writes and stops the background writer. Calling it again
+// returns the result of the first call.
+func (w *asyncWriter) Stop() error {
+	w.stopOnce.Do(func() {
+		w.stopErr = w.Sync()
+		close(w.quit)
+	})
+	return w.stopErr
+}
+
+// reportDrops logs a warning about entries dropped by the background writer.
+func (l *Logger) reportDrops(dropped uint64) {
+	l.baseLogger.Desugar().WithOptions(zap.WithCaller(false)).Warn(
+		"log entries dropped because the output could not keep up", zap.Uint64("dropped", dropped),
+	)
+}
@@ -0,0 +1,113 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedSink is a thread-safe memory sink whose writes block until open is closed.
+type gatedSink struct {
+	mu   sync.Mutex
+	logs strings.Builder
+	open chan struct{}
+}
+
+// Write implements io.Writer.
+func (g *gatedSink) Write(p []byte) (int, error) {
+	<-g.open
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.logs.Write(p)
+}
+
+// String returns the entries written so far.
+func (g *gatedSink) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.logs.String()
+}
+
+// Sync is a no-op for gatedSink.
+func (g *gatedSink) Sync() error { return nil }
+
+// Close is a no-op for gatedSink.
+func (g *gatedSink) Close() error { return nil }
+
+func TestWithOverflowPolicyDrop(t *testing.T) {
+	sink := &gatedSink{open: make(chan struct{})}
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{registerSink(t, sink) + "://"}),
+		logger.WithBufferedWrites(1, 20*time.Millisecond),
+		logger.WithOverflowPolicy(logger.OverflowDrop),
+		logger.WithSampling(0, 0, 0),
+		logger.WithStats(time.Minute),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 5000 {
+			l.Info(ctx, "flood")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging blocked on a stalled output")
+	}
+
+	dropped := l.DroppedWrites()
+	require.Positive(t, dropped)
+	require.Equal(t, dropped, l.Stats().Dropped)
+
+	close(sink.open)
+	require.Eventually(t, func() bool {
+		return strings.Contains(sink.String(), "log entries dropped")
+	}, 5*time.Second, 10*time.Millisecond, "drops should be reported")
+	require.NoError(t, l.Close(ctx))
+}
+
+func TestWithOverflowPolicyBlock(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithBufferedWrites(1, time.Hour), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	for range 100 {
+		l.Info(ctx, "entry")
+	}
+	require.NoError(t, l.Close(ctx))
+	require.Zero(t, l.DroppedWrites())
+	require.Equal(t, 100, strings.Count(sink.logs.String(), `"msg":"entry"`))
+}
+
+func TestWithOverflowPolicyDropAfterClose(t *testing.T) {
+	l, sink := newMemoryLogger(t,
+		logger.WithBufferedWrites(1, time.Hour),
+		logger.WithOverflowPolicy(logger.OverflowDrop),
+		logger.WithSampling(0, 0, 0),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l.Info(ctx, "before close")
+	require.NoError(t, l.Close(ctx))
+	require.NoError(t, l.Close(ctx), "closing twice should not block")
+	require.NoError(t, l.Flush(ctx), "flushing after Close should not block")
+
+	done := make(chan error, 1)
+	go func() { done <- l.Sync() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync blocked after Close")
+	}
+	require.Contains(t, sink.logs.String(), "before close")
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithPrecedingDebug keeps the last k Debug entries of each request in memory while
+// DebugLevel is disabled, and attaches them as a "preceding" array to the first Error
+// logged for that request. This gives errors their context without enabling debug
+// logging globally. Only requests whose context carries a buffer, see
+// ContextWithDebugBuffer, are tracked.
+func WithPrecedingDebug(k int) Option {
+	return func(l *Logger) {
+		l.precedingDebug = k
+	}
+}
+
+// debugBufferKey is the context key of a request's debugBuffer.
+type debugBufferKey struct{}
+
+// ContextWithDebugBuffer returns a copy of ctx carrying an empty buffer for the Debug
+// entries that precede the first Error of a request. It is typically called once per
+// request, for example in an HTTP middleware.
+func ContextWithDebugBuffer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugBufferKey{}, &debugBuffer{})
+}
+
+// debugBuffer holds the most recent Debug entries of a request, until its first Error.
+type debugBuffer struct {
+	mu      sync.Mutex
+	entries []bufferedEntry
+	done    bool
+}
+
+// bufferedEntry is a Debug entry that was held back instead of written.
+type bufferedEntry struct {
+	time    time.Time
+	msg     string
+	keyVals []interface{}
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (e bufferedEntry) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddTime("ts", e.time)
+	enc.AddString("msg", e.msg)
+	return keyValsObject(e.keyVals).MarshalLogObject(enc)
+}
+
+// bufferedEntries is the "preceding" array attached to an Error.
+type bufferedEntries []bufferedEntry
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (es bufferedEntries) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range es {
+		if err := enc.AppendObject(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record keeps a Debug entry, evicting the oldest one beyond k entries. It reports
+// whether the entry was kept, which is no longer the case after the first Error.
+func (b *debugBuffer) record(k int, e bufferedEntry) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return false
+	}
+	if len(b.entries) == k {
+		b.entries = append(b.entries[:0], b.entries[1:]...)
+	}
+	b.entries = append(b.entries, e)
+	return true
+}
+
+// drain returns the kept entries and stops the buffering for the request.
+func (b *debugBuffer) drain() bufferedEntries {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := b.entries
+	b.entries, b.done = nil, true
+	return entries
+}
+
+// precedingBuffer returns the debug buffer carried by ctx, if preceding Debug entries are
+// tracked for it.
+func (l *Logger) precedingBuffer(ctx context.Context) *debugBuffer {
+	if l.precedingDebug <= 0 || ctx == nil {
+		return nil
+	}
+	buf, _ := ctx.Value(debugBufferKey{}).(*debugBuffer)
+	return buf
+}
+
+// holdDebug keeps a disabled Debug entry in the request's buffer. It reports whether the
+// entry was held back.
+func (l *Logger) holdDebug(ctx context.Context, msg string, keyVals []interface{}) bool {
+	buf := l.precedingBuffer(ctx)
+	if buf == nil || l.zapLogger.Level().Enabled(zapcore.DebugLevel) {
+		return false
+	}
+	return buf.record(l.precedingDebug, bufferedEntry{time: time.Now(), msg: msg, keyVals: keyVals})
+}
+
+// precedingFields returns the field carrying the request's held back Debug entries, for
+// the first Error of the request.
+func (l *Logger) precedingFields(ctx context.Context) []interface{} {
+	buf := l.precedingBuffer(ctx)
+	if buf == nil {
+		return nil
+	}
+	entries := buf.drain()
+	if len(entries) == 0 {
+		return nil
+	}
+	return []interface{}{zap.Array("preceding", entries)}
+}
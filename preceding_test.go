@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithPrecedingDebug(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t, logger.WithPrecedingDebug(2))
+	ctx := logger.ContextWithDebugBuffer(context.Background())
+
+	l.Debug(ctx, "step 1")
+	l.Debug(ctx, "step 2", "rows", 3)
+	l.Debug(ctx, "step 3", zap.String("table", "users"))
+	l.Error(ctx, "query failed")
+	l.Debug(ctx, "after the error")
+	l.Error(ctx, "second failure")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2, "Debug entries should only be written as part of the first Error")
+
+	preceding, ok := entries[0]["preceding"].([]any)
+	require.True(t, ok, "first Error should carry the preceding Debug entries")
+	require.Len(t, preceding, 2, "only the last k entries should be kept")
+	require.Equal(t, "step 2", preceding[0].(map[string]any)["msg"])
+	require.EqualValues(t, 3, preceding[0].(map[string]any)["rows"])
+	require.Equal(t, "users", preceding[1].(map[string]any)["table"])
+	require.Contains(t, preceding[1], "ts")
+
+	require.NotContains(t, entries[1], "preceding")
+}
+
+func TestWithPrecedingDebugRequiresBuffer(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithPrecedingDebug(10))
+	ctx := context.Background()
+
+	l.Debug(ctx, "untracked")
+	l.Error(ctx, "failure")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.NotContains(t, entries[0], "preceding")
+}
+
+func TestWithPrecedingDebugWhenDebugEnabled(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t, logger.WithPrecedingDebug(10), logger.WithLevel(zapcore.DebugLevel))
+	ctx := logger.ContextWithDebugBuffer(context.Background())
+
+	l.Debug(ctx, "written directly")
+	l.Error(ctx, "failure")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.NotContains(t, entries[1], "preceding", "enabled Debug entries should not be duplicated")
+}
+
+func TestWithPrecedingDebugIsRedacted(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithPrecedingDebug(10), logger.WithRedactKeys("password"))
+	ctx := logger.ContextWithDebugBuffer(context.Background())
+
+	l.Debug(ctx, "login", "password", "hunter2")
+	l.Error(ctx, "failure")
+
+	require.NotContains(t, sink.logs.String(), "hunter2")
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLevelMessagePrefixes prepends a prefix to the message of the entries of the given
+// levels, for example {zapcore.ErrorLevel: "ERROR: "}, so that legacy collectors and
+// alerting rules keying off message prefixes keep working after the migration to structured
+// logging. The prefixes are added as given, including any separator, and apply to every
+// output, extra core and hook.
+func WithLevelMessagePrefixes(prefixes map[zapcore.Level]string) Option {
+	return func(l *Logger) {
+		l.levelPrefixes = prefixes
+	}
+}
+
+// levelPrefixCore is a zapcore.Core prepending a per-level prefix to the message.
+type levelPrefixCore struct {
+	zapcore.Core
+	prefixes map[zapcore.Level]string
+}
+
+// With implements zapcore.Core.
+func (c *levelPrefixCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelPrefixCore{Core: c.Core.With(fields), prefixes: c.prefixes}
+}
+
+// Check implements zapcore.Core.
+func (c *levelPrefixCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *levelPrefixCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.prefixes[ent.Level] + ent.Message
+	return c.Core.Write(ent, fields)
+}
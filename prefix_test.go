@@ -0,0 +1,25 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithLevelMessagePrefixes(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithLevelMessagePrefixes(map[zapcore.Level]string{
+		zapcore.ErrorLevel: "ERROR: ",
+	}))
+
+	ctx := context.Background()
+	l.Error(ctx, "payment failed")
+	l.Info(ctx, "order placed")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "ERROR: payment failed", entries[0]["msg"])
+	require.Equal(t, "order placed", entries[1]["msg"], "levels without a prefix are unchanged")
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// PrivacyLevel classifies how sensitive an entry is, and how trusted a sink must be to
+// receive it.
+type PrivacyLevel int
+
+const (
+	// Public entries may be sent to any sink. Entries without a Privacy marker are public.
+	Public PrivacyLevel = iota
+	// Internal entries may only be sent to sinks operated by the organization.
+	Internal
+	// Restricted entries may only be sent to the most trusted sinks, such as the local outputs.
+	Restricted
+)
+
+// privacyKey is the key of the field returned by Privacy. The field is never encoded.
+const privacyKey = "privacy"
+
+// Privacy returns a marker field that classifies an entry, or all entries of a child Logger
+// when passed to With. Sinks whose clearance is below the level never receive the entry, see
+// WithClearance. The marker only routes entries; it does not appear in the output.
+//
+//	l.Error(ctx, "payment declined", "card_holder", name, logger.Privacy(logger.Restricted))
+func Privacy(level PrivacyLevel) zapcore.Field {
+	return zapcore.Field{Key: privacyKey, Type: zapcore.SkipType, Interface: level}
+}
+
+// WithClearance adds cores that, like the cores added through WithCore, receive the entries
+// alongside the output paths, but only for entries classified at or below clearance.
+// Use it for lower-trust sinks, such as third-party services:
+//
+//	logger.WithClearance(logger.Public, sentryCore)
+//
+// The output paths and the cores added through WithCore have Restricted clearance.
+func WithClearance(clearance PrivacyLevel, cores ...zapcore.Core) Option {
+	return func(l *Logger) {
+		for _, c := range cores {
+			l.cores = append(l.cores, route{core: c, clearance: clearance})
+		}
+	}
+}
+
+// route is an extra core together with the most sensitive privacy level it may receive.
+type route struct {
+	core      zapcore.Core
+	clearance PrivacyLevel
+}
+
+// PrivacyOf returns the most sensitive privacy level marked in fields, or Public. Hooks
+// forwarding entries to other systems use it to honor the markers like WithClearance does.
+func PrivacyOf(fields []zapcore.Field) PrivacyLevel {
+	level := Public
+	for _, f := range fields {
+		if p, ok := f.Interface.(PrivacyLevel); ok && f.Key == privacyKey && f.Type == zapcore.SkipType {
+			level = max(level, p)
+		}
+	}
+	return level
+}
+
+// clearanceCore is a zapcore.Core dropping the entries that are classified above what the
+// wrapped core is cleared for.
+type clearanceCore struct {
+	zapcore.Core
+	clearance PrivacyLevel
+	privacy   PrivacyLevel // The level marked in the fields added through With.
+}
+
+// With implements zapcore.Core.
+func (c *clearanceCore) With(fields []zapcore.Field) zapcore.Core {
+	return &clearanceCore{
+		Core:      c.Core.With(fields),
+		clearance: c.clearance,
+		privacy:   max(c.privacy, PrivacyOf(fields)),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *clearanceCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *clearanceCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if max(c.privacy, PrivacyOf(fields)) > c.clearance {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPrivacyClearance(t *testing.T) {
+	public, publicLogs := observer.New(zapcore.InfoLevel)
+	internal, internalLogs := observer.New(zapcore.InfoLevel)
+	trusted, trustedLogs := observer.New(zapcore.InfoLevel)
+	l, sink := newMemoryLogger(t,
+		logger.WithClearance(logger.Public, public),
+		logger.WithClearance(logger.Internal, internal),
+		logger.WithCore(trusted),
+	)
+	ctx := context.Background()
+
+	l.Info(ctx, "unmarked")
+	l.Info(ctx, "internal", logger.Privacy(logger.Internal))
+	l.Info(ctx, "restricted", "card_holder", "Jane", logger.Privacy(logger.Restricted))
+	l.With(logger.Privacy(logger.Restricted)).Info(ctx, "restricted child")
+
+	messages := func(logs *observer.ObservedLogs) []string {
+		var msgs []string
+		for _, e := range logs.All() {
+			msgs = append(msgs, e.Message)
+		}
+		return msgs
+	}
+	require.Equal(t, []string{"unmarked"}, messages(publicLogs))
+	require.Equal(t, []string{"unmarked", "internal"}, messages(internalLogs))
+	require.Equal(t, []string{"unmarked", "internal", "restricted", "restricted child"}, messages(trustedLogs))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 4, "the outputs should receive every entry")
+	require.NotContains(t, entries[2], "privacy", "the marker should not be encoded")
+	require.Equal(t, "Jane", entries[2]["card_holder"])
+}
+
+func TestPrivacyClearanceWithTransforms(t *testing.T) {
+	public, publicLogs := observer.New(zapcore.InfoLevel)
+	var requests []interface{}
+	hook := func(ctx context.Context, _ zapcore.Entry, _ []zapcore.Field) error {
+		requests = append(requests, ctx.Value(requestKey{}))
+		return nil
+	}
+	l, sink := newMemoryLogger(t,
+		logger.WithClearance(logger.Public, public),
+		logger.WithContextHook(hook),
+		logger.WithRedactKeys("*privacy*", "context"),
+		logger.WithHashFields([]byte("key"), "privacy", "context"),
+		logger.WithKeyNormalization(logger.CamelCase),
+	)
+	ctx := context.WithValue(context.Background(), requestKey{}, "r-1")
+
+	l.Info(ctx, "restricted", logger.Privacy(logger.Restricted))
+
+	require.Zero(t, publicLogs.Len(), "the marker should survive the transforms")
+	require.Equal(t, []interface{}{"r-1"}, requests)
+	require.NotContains(t, sink.logs.String(), "REDACTED", "markers should not be encoded")
+}
@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// rateLimitKeyName is the key of the field returned by RateLimitKey. The field is never encoded.
+	rateLimitKeyName = "rate_limit_key"
+	// suppressedKey holds the number of entries suppressed by the rate limiter since the
+	// previous entry with the same key.
+	suppressedKey = "suppressed"
+)
+
+// WithRateLimit limits how many entries with the same key are written, so that a tight
+// error loop cannot flood the outputs. Each key may write burst entries at once, and
+// perSecond entries per second on average; a burst below 1 is treated as 1. The key is the
+// entry's message, unless the entry or the Logger carries a RateLimitKey.
+//
+// The first entry written for a key after entries were suppressed carries a suppressed
+// field with their number, so while the limiter kicks in, it acts as a periodic summary.
+// When no entry is written for the key by the time the limiter would allow one again, or
+// when the Logger is closed, a copy of the last suppressed entry carrying the suppressed
+// field is written instead, so the count is not lost.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(l *Logger) {
+		l.rateLimit = &rateLimitConfig{perSecond: perSecond, burst: max(burst, 1)}
+	}
+}
+
+// RateLimitKey returns a marker field that makes WithRateLimit limit the entry under key
+// instead of its message, for example to limit messages that embed varying values together.
+// Passed to With, it applies to all entries of the child Logger.
+func RateLimitKey(key string) zapcore.Field {
+	return zapcore.Field{Key: rateLimitKeyName, Type: zapcore.SkipType, String: key}
+}
+
+// rateLimitConfig holds the settings of WithRateLimit.
+type rateLimitConfig struct {
+	perSecond float64
+	burst     int
+}
+
+// tokenBucket tracks the entries written for a rate limiting key.
+type tokenBucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int
+
+	// The last suppressed entry, written as the summary of the suppressed entries when no
+	// entry is written before timer fires.
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	core   zapcore.Core
+	timer  *time.Timer
+}
+
+// rateLimiter makes the rate limiting decisions shared by a rateLimitCore and its children.
+type rateLimiter struct {
+	cfg rateLimitConfig
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// allow reports whether the entry ent for key is written, and if so, how many entries were
+// suppressed before it. A suppressed entry is held back as the summary, written to core when
+// no entry is allowed before the bucket holds a token again.
+func (r *rateLimiter) allow(key string, ent zapcore.Entry, fields []zapcore.Field, core zapcore.Core) (ok bool, suppressed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := ent.Time
+	b, found := r.buckets[key]
+	if !found {
+		if len(r.buckets) >= maxSampleKeys {
+			r.prune(now)
+		}
+		b = &tokenBucket{tokens: float64(r.cfg.burst), last: now}
+		r.buckets[key] = b
+	}
+	r.refill(b, now)
+
+	if b.tokens < 1 {
+		b.suppressed++
+		b.ent, b.fields, b.core = ent, fields, core
+		if b.timer == nil && r.cfg.perSecond > 0 {
+			wait := time.Duration((1 - b.tokens) / r.cfg.perSecond * float64(time.Second))
+			var timer *time.Timer
+			timer = time.AfterFunc(wait, func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				// The entries were summarized already if the timer was replaced.
+				if b.timer == timer {
+					_ = r.summarize(b)
+				}
+			})
+			b.timer = timer
+		}
+		return false, 0
+	}
+	b.tokens--
+	suppressed = b.suppressed
+	r.release(b)
+	return true, suppressed
+}
+
+// summarize writes the held back entry of b carrying the number of suppressed entries, if
+// any. r.mu must be held.
+func (r *rateLimiter) summarize(b *tokenBucket) error {
+	if b.suppressed == 0 {
+		return nil
+	}
+	ent, fields, core := b.ent, b.fields, b.core
+	suppressed := b.suppressed
+	r.release(b)
+	return core.Write(ent, append(fields[:len(fields):len(fields)], zap.Int(suppressedKey, suppressed)))
+}
+
+// release forgets the suppressed entries of b and stops its timer. r.mu must be held.
+func (r *rateLimiter) release(b *tokenBucket) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.suppressed = 0
+	b.ent, b.fields, b.core = zapcore.Entry{}, nil, nil
+}
+
+// stop writes the summaries of the suppressed entries and stops the timers, for Close.
+func (r *rateLimiter) stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	for _, b := range r.buckets {
+		err = errors.Join(err, r.summarize(b))
+	}
+	return err
+}
+
+// refill adds the tokens earned since the bucket was last used.
+func (r *rateLimiter) refill(b *tokenBucket, now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(r.cfg.burst), b.tokens+elapsed.Seconds()*r.cfg.perSecond)
+		b.last = now
+	}
+}
+
+// prune forgets the buckets that are full again and have no suppressed entries to report.
+func (r *rateLimiter) prune(now time.Time) {
+	for key, b := range r.buckets {
+		r.refill(b, now)
+		if b.tokens >= float64(r.cfg.burst) && b.suppressed == 0 {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// rateLimitCore is a zapcore.Core that suppresses entries written too often for their key.
+// The key may be carried by the entry's fields, so the decision is made in Write.
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+	key     string // The RateLimitKey added through With, if any.
+	onDrop  func(zapcore.Entry)
+}
+
+// newRateLimiter creates the rateLimiter of a rateLimitCore.
+func newRateLimiter(cfg rateLimitConfig) *rateLimiter {
+	return &rateLimiter{cfg: cfg, buckets: make(map[string]*tokenBucket)}
+}
+
+// newRateLimitCore wraps core so that entries are rate limited by limiter.
+// onDrop is called for every suppressed entry.
+func newRateLimitCore(core zapcore.Core, limiter *rateLimiter, onDrop func(zapcore.Entry)) zapcore.Core {
+	return &rateLimitCore{Core: core, limiter: limiter, onDrop: onDrop}
+}
+
+// With implements zapcore.Core. Children share the rate limits of their parent.
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if key, ok := rateLimitKeyOf(fields); ok {
+		clone.key = key
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key, ok := rateLimitKeyOf(fields)
+	if !ok {
+		key = c.key
+	}
+	if key == "" {
+		key = ent.Message
+	}
+
+	allowed, suppressed := c.limiter.allow(key, ent, fields, c.Core)
+	if !allowed {
+		c.onDrop(ent)
+		return nil
+	}
+	if suppressed > 0 {
+		fields = append(fields[:len(fields):len(fields)], zap.Int(suppressedKey, suppressed))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// rateLimitKeyOf returns the key of the last RateLimitKey marker in fields.
+func rateLimitKeyOf(fields []zapcore.Field) (key string, ok bool) {
+	for _, f := range fields {
+		if f.Key == rateLimitKeyName && f.Type == zapcore.SkipType {
+			key, ok = f.String, true
+		}
+	}
+	return key, ok
+}
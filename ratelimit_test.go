@@ -0,0 +1,78 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	// The observer is safe for the concurrent write of the summary, unlike the memory sink.
+	core, observed := observer.New(zapcore.InfoLevel)
+	l, _ := newMemoryLogger(t,
+		logger.WithCore(core),
+		logger.WithRateLimit(20, 3),
+		logger.WithSampling(0, 0, 0),
+		logger.WithStats(time.Minute),
+	)
+	ctx := context.Background()
+
+	for range 100 {
+		l.Error(ctx, "connection refused")
+	}
+	l.Error(ctx, "other message")
+
+	require.Equal(t, 4, observed.Len(), "only the burst should be written per message")
+	require.Equal(t, "other message", observed.All()[3].Message)
+	require.EqualValues(t, 97, l.Stats().Dropped)
+
+	require.Eventually(t, func() bool {
+		return observed.Len() == 5
+	}, time.Second, time.Millisecond, "the suppressed entries should be summarized once the window ends")
+	summary := observed.All()[4]
+	require.Equal(t, "connection refused", summary.Message)
+	require.EqualValues(t, 97, summary.ContextMap()["suppressed"])
+
+	time.Sleep(100 * time.Millisecond)
+	l.Error(ctx, "connection refused")
+	require.Equal(t, 6, observed.Len())
+	require.NotContains(t, observed.All()[5].ContextMap(), "suppressed", "the summarized entries should not be counted again")
+}
+
+func TestWithRateLimitSummaryOnClose(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRateLimit(0.001, 0), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	for range 5 {
+		l.Error(ctx, "connection refused")
+	}
+	require.Len(t, decodeLines(t, sink), 1, "a burst below 1 should be treated as 1")
+
+	require.NoError(t, l.Close(ctx))
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.EqualValues(t, 4, entries[1]["suppressed"], "the suppressed entries should be summarized on Close")
+}
+
+func TestRateLimitKey(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRateLimit(1, 2), logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	for i := range 10 {
+		l.Error(ctx, fmt.Sprintf("retry %d failed", i), logger.RateLimitKey("retry"))
+	}
+	retries := l.With(logger.RateLimitKey("child"))
+	for i := range 10 {
+		retries.Error(ctx, fmt.Sprintf("attempt %d", i))
+	}
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 4, "messages sharing a key should be limited together")
+	require.NotContains(t, entries[0], "rate_limit_key", "the marker should not be encoded")
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// recoverConfig holds the settings for RecoverAndLog and RecoverMiddleware.
+type recoverConfig struct {
+	level   zapcore.Level
+	repanic bool
+}
+
+// RecoverOption defines a functional option for configuring panic recovery.
+type RecoverOption func(cfg *recoverConfig)
+
+// WithRepanic re-panics with the original value after the panic has been logged.
+func WithRepanic() RecoverOption {
+	return func(cfg *recoverConfig) {
+		cfg.repanic = true
+	}
+}
+
+// WithRecoverLevel sets the level at which recovered panics are logged. It defaults to ErrorLevel.
+// Note that FatalLevel terminates the process after the entry has been written.
+func WithRecoverLevel(level zapcore.Level) RecoverOption {
+	return func(cfg *recoverConfig) {
+		cfg.level = level
+	}
+}
+
+// newRecoverConfig applies opts on top of the default recovery settings.
+func newRecoverConfig(opts []RecoverOption) recoverConfig {
+	cfg := recoverConfig{level: zapcore.ErrorLevel}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// RecoverAndLog recovers from a panic, logs the panic value and the goroutine stack,
+// including the trace_id from ctx, and then either swallows the panic or re-panics.
+// It must be deferred directly, since recover only works in the deferred function itself:
+//
+//	defer logger.RecoverAndLog(ctx, log)
+func RecoverAndLog(ctx context.Context, l *Logger, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	cfg := newRecoverConfig(opts)
+	l.log(ctx, cfg.level, "recovered from panic", PanicFields(v))
+	if cfg.repanic {
+		panic(v)
+	}
+}
+
+// RecoverMiddleware returns HTTP middleware that recovers from panics in the wrapped handler,
+// logs them with the request's trace_id, and responds with 500 Internal Server Error,
+// unless WithRepanic is given. http.ErrAbortHandler is always re-panicked, so that
+// net/http can abort the response as intended.
+func RecoverMiddleware(l *Logger, opts ...RecoverOption) func(http.Handler) http.Handler {
+	cfg := newRecoverConfig(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				keyVals := append(PanicFields(v), "method", r.Method, "path", r.URL.Path)
+				l.log(r.Context(), cfg.level, "recovered from panic", keyVals)
+				if cfg.repanic {
+					panic(v)
+				}
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PanicFields returns the key-value pairs describing a recovered panic value v and the current
+// goroutine stack: panic, stack and, when v is an error, error. It is meant for recovery
+// middlewares of other frameworks, so that their entries match those of RecoverMiddleware.
+func PanicFields(v interface{}) []interface{} {
+	keyVals := []interface{}{"panic", fmt.Sprint(v), "stack", string(debug.Stack())}
+	if err, ok := v.(error); ok {
+		keyVals = append(keyVals, "error", err)
+	}
+	return keyVals
+}
@@ -0,0 +1,96 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	ctx := context.WithValue(context.Background(), traceKey{}, "req-1")
+
+	func() {
+		defer logger.RecoverAndLog(ctx, l)
+		panic("boom")
+	}()
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "error", entries[0]["level"])
+	require.Equal(t, "boom", entries[0]["panic"])
+	require.Equal(t, "req-1", entries[0]["trace_id"])
+	require.Contains(t, entries[0]["stack"], "recover_test.go", "stack should include the panicking function")
+}
+
+func TestRecoverAndLogRepanic(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	boom := errors.New("boom")
+
+	require.PanicsWithValue(t, boom, func() {
+		defer logger.RecoverAndLog(context.Background(), l, logger.WithRepanic())
+		panic(boom)
+	})
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "boom", entries[0]["error"])
+}
+
+func TestRecoverAndLogWithoutPanic(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	func() {
+		defer logger.RecoverAndLog(context.Background(), l)
+	}()
+
+	require.Empty(t, sink.logs.String())
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+	handler := logger.RecoverMiddleware(l)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("handler exploded")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req = req.WithContext(context.WithValue(req.Context(), traceKey{}, "req-2"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "handler exploded", entries[0]["panic"])
+	require.Equal(t, "/orders", entries[0]["path"])
+	require.Equal(t, "req-2", entries[0]["trace_id"])
+}
+
+func TestRecoverMiddlewareAbortHandler(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	handler := logger.RecoverMiddleware(l)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Empty(t, sink.logs.String(), "aborted handlers should not be logged as panics")
+}
+
+func TestPanicFields(t *testing.T) {
+	err := errors.New("boom")
+	keyVals := logger.PanicFields(err)
+	require.Len(t, keyVals, 6)
+	require.Equal(t, []interface{}{"panic", "boom"}, keyVals[:2])
+	require.Equal(t, "stack", keyVals[2])
+	require.Contains(t, keyVals[3], "TestPanicFields")
+	require.Equal(t, []interface{}{"error", err}, keyVals[4:])
+
+	require.Len(t, logger.PanicFields("nil map"), 4, "values other than errors should have no error field")
+}
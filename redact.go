@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted replaces the values of redacted fields.
+const redacted = "[REDACTED]"
+
+// WithRedactKeys replaces the values of fields with matching keys with "[REDACTED]" before
+// they are encoded. Keys are matched case-insensitively and may contain the wildcards
+// supported by path.Match, e.g. "*token*". Redaction also applies to the keys of nested
+// objects and maps, at any depth.
+func WithRedactKeys(keys ...string) Option {
+	return func(l *Logger) {
+		for _, k := range keys {
+			l.redactKeys = append(l.redactKeys, strings.ToLower(k))
+		}
+	}
+}
+
+// keyMatcher reports whether a key matches any of a set of lowercased patterns.
+type keyMatcher []string
+
+// matches implements the matching rules of WithRedactKeys.
+func (m keyMatcher) matches(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range m {
+		if pattern == key {
+			return true
+		}
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactTransform returns a fieldTransform redacting the keys matched by m, as passed or as
+// normalized by n.
+func redactTransform(m keyMatcher, n *keyNormalizer) fieldTransform {
+	matches := func(key string) bool {
+		return m.matches(key) || n != nil && m.matches(n.normalize(key))
+	}
+	redactNested := func(v interface{}) interface{} {
+		return walkValue(v, func(key string, value interface{}) interface{} {
+			if matches(key) {
+				return redacted
+			}
+			return value
+		})
+	}
+	return func(f zapcore.Field) zapcore.Field {
+		if f.Key != "" && f.Type != zapcore.NamespaceType && matches(f.Key) {
+			return zap.String(f.Key, redacted)
+		}
+		return mapNested(f, redactNested)
+	}
+}
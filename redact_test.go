@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type session struct {
+	ID    string
+	Token string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (s session) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", s.ID)
+	enc.AddString("access_token", s.Token)
+	return nil
+}
+
+func TestWithRedactKeys(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRedactKeys("password", "*token*", "Authorization"))
+	ctx := context.Background()
+
+	l.With("authorization", "Bearer abc").Info(ctx, "request",
+		"PASSWORD", "hunter2",
+		"user", "jane",
+		"headers", map[string]any{"Authorization": "Bearer abc", "nested": map[string]any{"refresh_token": "xyz"}},
+		"login", credentials{User: "jane", Password: "hunter2"},
+		"sessions", []session{{ID: "s1", Token: "t1"}},
+		zap.Object("session", session{ID: "s2", Token: "t2"}),
+	)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.Equal(t, "[REDACTED]", entry["authorization"], "inherited fields should be redacted")
+	require.Equal(t, "[REDACTED]", entry["PASSWORD"], "keys should match case-insensitively")
+	require.Equal(t, "jane", entry["user"])
+	require.Equal(t, map[string]any{
+		"Authorization": "[REDACTED]",
+		"nested":        map[string]any{"refresh_token": "[REDACTED]"},
+	}, entry["headers"], "nested maps should be redacted")
+	require.Equal(t, map[string]any{"user": "jane", "password": "[REDACTED]"}, entry["login"], "structs should be redacted")
+	require.Equal(t, map[string]any{"id": "s2", "access_token": "[REDACTED]"}, entry["session"], "objects should be redacted")
+	require.NotContains(t, sink.logs.String(), "hunter2")
+	require.NotContains(t, sink.logs.String(), "t1")
+}
+
+func TestWithRedactKeysLeavesOtherFieldsIntact(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithRedactKeys("password"))
+
+	l.Info(context.Background(), "request", "count", 3, "ok", true, zap.Object("session", session{ID: "s1"}))
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, float64(3), entries[0]["count"])
+	require.Equal(t, true, entries[0]["ok"])
+	require.Equal(t, "s1", entries[0]["session"].(map[string]any)["id"])
+}
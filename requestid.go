@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the default header carrying the request ID, see RequestIDMiddleware.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen is the maximum length of a request ID accepted from a request header.
+const maxRequestIDLen = 128
+
+// requestIDConfig holds the settings for RequestIDMiddleware.
+type requestIDConfig struct {
+	header   string
+	generate func() string
+}
+
+// RequestIDOption defines a functional option for configuring RequestIDMiddleware.
+type RequestIDOption func(cfg *requestIDConfig)
+
+// WithRequestIDHeader sets the header the request ID is read from and written to. It defaults
+// to X-Request-ID.
+func WithRequestIDHeader(header string) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		cfg.header = header
+	}
+}
+
+// WithRequestIDGenerator sets the function generating the IDs of requests without a valid
+// request ID header, e.g. to generate ULIDs. It defaults to NewRequestID.
+func WithRequestIDGenerator(generate func() string) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		cfg.generate = generate
+	}
+}
+
+// RequestIDMiddleware returns HTTP middleware that correlates the entries of a request when
+// there is no trace. It reads the request ID from the X-Request-ID header, or generates one
+// when the header is missing or invalid, stores it in the request's context through
+// ContextWithRequestID, so that every entry logged with the context includes it as
+// request_id, and sets it on the response header. IDs longer than 128 characters or with
+// characters other than printable ASCII are replaced, so that clients cannot inject
+// arbitrary content into the logs.
+func RequestIDMiddleware(opts ...RequestIDOption) func(http.Handler) http.Handler {
+	cfg := requestIDConfig{header: RequestIDHeader, generate: NewRequestID}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(cfg.header)
+			if !validRequestID(id) {
+				id = cfg.generate()
+			}
+			w.Header().Set(cfg.header, id)
+			next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// NewRequestID returns a random (version 4) UUID, e.g. "0b9e7a4c-3f0e-4b9a-8c61-2f6a1d7e5c3b".
+func NewRequestID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// validRequestID reports whether id is a non-empty request ID of at most maxRequestIDLen
+// printable ASCII characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	handler := logger.RequestIDMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		l.Info(r.Context(), "handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, "req-42", rec.Header().Get(logger.RequestIDHeader))
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "req-42", entries[0]["request_id"])
+}
+
+func TestRequestIDMiddlewareGenerates(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	var got string
+	handler := logger.RequestIDMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = logger.RequestIDFromContext(r.Context())
+	}))
+
+	for _, header := range []string{"", "bad id", strings.Repeat("x", 129)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logger.RequestIDHeader, header)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Regexp(t, uuid, got, "header %q should be replaced by a UUID", header)
+		require.Equal(t, got, rec.Header().Get(logger.RequestIDHeader))
+	}
+}
+
+func TestRequestIDMiddlewareOptions(t *testing.T) {
+	var got string
+	handler := logger.RequestIDMiddleware(
+		logger.WithRequestIDHeader("X-Correlation-ID"),
+		logger.WithRequestIDGenerator(func() string { return "generated" }),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = logger.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "ignored")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, "generated", got)
+	require.Equal(t, "generated", rec.Header().Get("X-Correlation-ID"))
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// reservedKeySuffix is appended to the reserved keys renamed by ReservedKeysRename.
+const reservedKeySuffix = "_user"
+
+// ReservedKeyPolicy selects what happens when the key-value pairs of a logging call or of With
+// use a key reserved by the Logger: the service field, the trace context fields, such as
+// trace_id or the key set through WithTraceIDKey, and the keys of the encoder, such as level,
+// ts and msg. Reserved keys are otherwise written twice, which some JSON parsers reject.
+type ReservedKeyPolicy int
+
+const (
+	// ReservedKeysAllow writes the fields as they are. This is the default.
+	ReservedKeysAllow ReservedKeyPolicy = iota
+	// ReservedKeysRename appends _user to reserved keys, e.g. service becomes service_user.
+	ReservedKeysRename
+	// ReservedKeysWarn writes the fields as they are, and logs a warning with the key and the
+	// caller, so that collisions can be found and fixed during development.
+	ReservedKeysWarn
+)
+
+// WithReservedKeys sets the policy for key-value pairs using reserved keys. The initial fields
+// are not checked.
+func WithReservedKeys(policy ReservedKeyPolicy) Option {
+	return func(l *Logger) {
+		l.reservedKeyPolicy = policy
+	}
+}
+
+// isOwnKey reports whether key is the key of a field added by the Logger itself: service and
+// the trace context fields.
+func isOwnKey(key string) bool {
+	switch key {
+	case "service", traceIDKey, spanIDKey, traceSampledKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// setReservedKeys computes the reserved keys of the Logger for the keys of enc.
+func (l *Logger) setReservedKeys(enc zapcore.EncoderConfig) {
+	if l.reservedKeyPolicy == ReservedKeysAllow {
+		return
+	}
+	l.reservedKeys = map[string]struct{}{"service": {}, traceIDKey: {}, spanIDKey: {}, traceSampledKey: {}}
+	for _, key := range []string{
+		l.traceIDKey, enc.TimeKey, enc.LevelKey, enc.MessageKey, enc.NameKey,
+		enc.CallerKey, enc.FunctionKey, enc.StacktraceKey,
+	} {
+		if key != "" && key != zapcore.OmitKey {
+			l.reservedKeys[key] = struct{}{}
+		}
+	}
+}
+
+// checkReservedKeys applies the ReservedKeyPolicy to keyVals, which are never modified, and
+// returns the key-value pairs to write. skip adjusts the caller of the warnings for callers
+// other than log.
+func (l *Logger) checkReservedKeys(keyVals []interface{}, skip int) []interface{} {
+	if len(l.reservedKeys) == 0 {
+		return keyVals
+	}
+	checked, cloned := keyVals, false
+	for i := 0; i < len(keyVals); i++ {
+		var key string
+		field, isField := keyVals[i].(zapcore.Field)
+		if isField {
+			key = field.Key
+		} else if key, _ = keyVals[i].(string); key == "" {
+			// Not a key: zap reports it as an invalid pair.
+			i++
+			continue
+		}
+
+		if _, ok := l.reservedKeys[key]; ok {
+			switch l.reservedKeyPolicy {
+			case ReservedKeysRename:
+				if !cloned {
+					checked, cloned = slices.Clone(keyVals), true
+				}
+				if isField {
+					field.Key += reservedKeySuffix
+					checked[i] = field
+				} else {
+					checked[i] = key + reservedKeySuffix
+				}
+			case ReservedKeysWarn:
+				l.baseLogger.Desugar().WithOptions(zap.AddCallerSkip(skip)).Warn(
+					"log field uses a reserved key", zap.String("key", key),
+				)
+			}
+		}
+		if !isField {
+			i++
+		}
+	}
+	return checked
+}
@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithReservedKeysRename(t *testing.T) {
+	l, sink := newMemoryLogger(t,
+		logger.WithReservedKeys(logger.ReservedKeysRename),
+		logger.WithTraceID(traceFromContext),
+	)
+	ctx := context.WithValue(context.Background(), traceKey{}, "abc123")
+
+	keyVals := []interface{}{"service", "billing", "level", "high", zap.String("trace_id", "mine"), "order_id", 7}
+	l.With("msg", "parent").Info(ctx, "entry", keyVals...)
+
+	require.Equal(t, "service", keyVals[0], "the caller's key-value pairs should not be modified")
+	out := sink.logs.String()
+	require.Equal(t, 1, strings.Count(out, `"service":`))
+	require.Equal(t, 1, strings.Count(out, `"level":`))
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "test-service", entry["service"])
+	require.Equal(t, "billing", entry["service_user"])
+	require.Equal(t, "info", entry["level"])
+	require.Equal(t, "high", entry["level_user"])
+	require.Equal(t, "entry", entry["msg"])
+	require.Equal(t, "parent", entry["msg_user"])
+	require.Equal(t, "abc123", entry["trace_id"])
+	require.Equal(t, "mine", entry["trace_id_user"])
+	require.EqualValues(t, 7, entry["order_id"])
+}
+
+func TestWithReservedKeysWarn(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithReservedKeys(logger.ReservedKeysWarn), logger.WithFormat(logger.FormatECS))
+
+	l.Info(context.Background(), "entry", "message", "shadowed", "user", "alice")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "log field uses a reserved key", entries[0]["message"])
+	require.Equal(t, "message", entries[0]["key"])
+	require.Contains(t, entries[0]["log.origin.file.name"], "reservedkeys_test.go")
+	lines := strings.Split(strings.TrimSpace(sink.logs.String()), "\n")
+	require.Equal(t, 2, strings.Count(lines[1], `"message":`), "the field should be written as is")
+}
+
+func TestWithReservedKeysAllow(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	l.Info(context.Background(), "entry", "service", "billing")
+
+	require.Equal(t, 2, strings.Count(sink.logs.String(), `"service":`))
+}
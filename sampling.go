@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// sampledKey marks entries that were emitted as representatives of sampled siblings.
+	sampledKey = "sampled"
+	// suppressedCountKey holds the number of siblings dropped since the previous representative.
+	suppressedCountKey = "suppressed_count"
+
+	// maxSampleKeys bounds the number of distinct messages tracked before expired ones are pruned.
+	maxSampleKeys = 4096
+)
+
+// samplingConfig holds the settings for sampling repetitive entries.
+type samplingConfig struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+}
+
+// WithSampling configures sampling of repetitive entries. Within every tick, the first entries
+// with the same level and message are written, after which only every thereafter-th entry is
+// written and the others are dropped. A non-positive tick disables sampling.
+//
+// Entries written after siblings were dropped carry sampled=true and suppressed_count, the
+// number of siblings dropped since the previous written entry, so it is clear that counts
+// derived from the logs are lower bounds.
+//
+// The default samples with a tick of one second, first 100 and thereafter 100, which matches
+// zap's production settings.
+func WithSampling(tick time.Duration, first, thereafter int) Option {
+	return func(l *Logger) {
+		l.sampling = samplingConfig{tick: tick, first: first, thereafter: thereafter}
+	}
+}
+
+// sampleKey identifies entries that are sampled together.
+type sampleKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// sampleCounter tracks the entries seen for a sampleKey in the current tick.
+type sampleCounter struct {
+	resetAt    int64
+	n          int
+	suppressed int
+}
+
+// sampler makes the sampling decisions shared by a samplingCore and its children.
+type sampler struct {
+	cfg samplingConfig
+
+	mu       sync.Mutex
+	counters map[sampleKey]*sampleCounter
+}
+
+// decide reports whether ent is kept, and if so, how many siblings were dropped before it.
+// The entry's own timestamp is used as the clock.
+func (s *sampler) decide(ent zapcore.Entry) (keep bool, suppressed int) {
+	now := ent.Time.UnixNano()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sampleKey{level: ent.Level, message: ent.Message}
+	c, ok := s.counters[key]
+	if !ok {
+		if len(s.counters) >= maxSampleKeys {
+			s.prune(now)
+		}
+		c = &sampleCounter{}
+		s.counters[key] = c
+	}
+	if now >= c.resetAt {
+		c.n = 0
+		c.resetAt = now + int64(s.cfg.tick)
+	}
+
+	c.n++
+	if c.n > s.cfg.first && (s.cfg.thereafter <= 0 || (c.n-s.cfg.first)%s.cfg.thereafter != 0) {
+		c.suppressed++
+		return false, 0
+	}
+	suppressed, c.suppressed = c.suppressed, 0
+	return true, suppressed
+}
+
+// prune forgets the counters whose tick has expired.
+func (s *sampler) prune(now int64) {
+	for key, c := range s.counters {
+		if now >= c.resetAt {
+			delete(s.counters, key)
+		}
+	}
+}
+
+// samplingCore is a zapcore.Core that drops repetitive entries.
+type samplingCore struct {
+	zapcore.Core
+	sampler *sampler
+	onDrop  func(zapcore.Entry)
+}
+
+// newSamplingCore wraps core so that repetitive entries are sampled according to cfg.
+// onDrop is called for every dropped entry.
+func newSamplingCore(core zapcore.Core, cfg samplingConfig, onDrop func(zapcore.Entry)) zapcore.Core {
+	return &samplingCore{
+		Core:    core,
+		sampler: &sampler{cfg: cfg, counters: make(map[sampleKey]*sampleCounter)},
+		onDrop:  onDrop,
+	}
+}
+
+// With implements zapcore.Core. Children share the sampling decisions of their parent.
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler, onDrop: c.onDrop}
+}
+
+// Check implements zapcore.Core.
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+
+	keep, suppressed := c.sampler.decide(ent)
+	if !keep {
+		c.onDrop(ent)
+		return ce
+	}
+	if suppressed == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	return ce.AddCore(ent, &annotatedCore{
+		Core:   c.Core,
+		fields: []zapcore.Field{zap.Bool(sampledKey, true), zap.Int(suppressedCountKey, suppressed)},
+	})
+}
+
+// annotatedCore is a zapcore.Core that adds fields to the single entry it was created for.
+type annotatedCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+}
+
+// Write implements zapcore.Core.
+func (c *annotatedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, append(fields[:len(fields):len(fields)], c.fields...))
+}
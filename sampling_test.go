@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingAnnotatesRepresentatives(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithSampling(time.Minute, 2, 3))
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		l.Info(ctx, "tight loop", "i", i)
+	}
+	l.Info(ctx, "other message")
+
+	entries := decodeLines(t, sink)
+	var is []float64
+	for _, e := range entries[:len(entries)-1] {
+		is = append(is, e["i"].(float64))
+	}
+	require.Equal(t, []float64{0, 1, 4, 7}, is, "the first entries and every thereafter-th should be kept")
+
+	require.NotContains(t, entries[0], "sampled", "entries without dropped siblings should not be annotated")
+	require.NotContains(t, entries[1], "sampled")
+	require.Equal(t, true, entries[2]["sampled"])
+	require.Equal(t, float64(2), entries[2]["suppressed_count"])
+	require.Equal(t, float64(2), entries[3]["suppressed_count"])
+	require.NotContains(t, entries[4], "sampled", "other messages are sampled independently")
+}
+
+func TestSamplingIsPerLevel(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithSampling(time.Minute, 1, 0))
+	ctx := context.Background()
+
+	l.Info(ctx, "same")
+	l.Info(ctx, "same")
+	l.Error(ctx, "same")
+
+	require.Len(t, decodeLines(t, sink), 2)
+}
+
+func TestSamplingDisabled(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithSampling(0, 0, 0))
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		l.Info(ctx, "tight loop")
+	}
+
+	require.Len(t, decodeLines(t, sink), 250)
+}
+
+func TestDefaultSampling(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		l.Info(ctx, "tight loop")
+	}
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 101, "defaults should match zap's production sampling")
+	require.Equal(t, float64(99), entries[100]["suppressed_count"])
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithScrubPatterns masks every match of the given patterns with "[REDACTED]" in the message
+// and in string field values, including strings nested in objects, maps and slices, and the
+// messages of logged errors. Scrubbing happens before entries are encoded, so it applies to
+// every output and extra core.
+func WithScrubPatterns(patterns ...*regexp.Regexp) Option {
+	return func(l *Logger) {
+		l.scrubPatterns = append(l.scrubPatterns, patterns...)
+	}
+}
+
+// scrubString masks the matches of patterns in s.
+func scrubString(patterns []*regexp.Regexp, s string) string {
+	for _, p := range patterns {
+		s = p.ReplaceAllString(s, redacted)
+	}
+	return s
+}
+
+// scrubMessage returns a messageTransform masking the matches of patterns.
+func scrubMessage(patterns []*regexp.Regexp) messageTransform {
+	return func(msg string) string {
+		return scrubString(patterns, msg)
+	}
+}
+
+// scrubbedError is a logged error whose message was scrubbed, or truncated by
+// WithMaxFieldBytes. It keeps the original error for
+// errorType and the error fingerprint only: it does not unwrap to it, since cores and hooks
+// walking the chain, such as the Sentry one, would see the unscrubbed messages.
+type scrubbedError struct {
+	err error // The original error.
+	msg string
+}
+
+// Error implements error, returning the scrubbed message.
+func (e *scrubbedError) Error() string {
+	return e.msg
+}
+
+// errorType returns the type of err, as formatted by %T, seeing through scrubbedError.
+func errorType(err error) string {
+	if scrubbed, ok := err.(*scrubbedError); ok {
+		err = scrubbed.err
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// scrubTransform returns a fieldTransform masking the matches of patterns in string values.
+func scrubTransform(patterns []*regexp.Regexp) fieldTransform {
+	var scrubValue func(v interface{}) interface{}
+	scrubValue = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			return scrubString(patterns, v)
+		case map[string]interface{}:
+			out := make(map[string]interface{}, len(v))
+			for key, value := range v {
+				out[key] = scrubValue(value)
+			}
+			return out
+		case []interface{}:
+			out := make([]interface{}, len(v))
+			for i, value := range v {
+				out[i] = scrubValue(value)
+			}
+			return out
+		default:
+			return v
+		}
+	}
+
+	return func(f zapcore.Field) zapcore.Field {
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = scrubString(patterns, f.String)
+			return f
+		case zapcore.ByteStringType:
+			return zap.String(f.Key, scrubString(patterns, string(f.Interface.([]byte))))
+		case zapcore.StringerType:
+			return zap.String(f.Key, scrubString(patterns, f.Interface.(fmt.Stringer).String()))
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok {
+				f.Interface = &scrubbedError{err: err, msg: scrubString(patterns, err.Error())}
+			}
+			return f
+		default:
+			return mapNested(f, scrubValue)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+	cardPattern   = regexp.MustCompile(`\b(?:\d[ -]?){13,16}\b`)
+	bearerPattern = regexp.MustCompile(`Bearer\s+[\w.-]+`)
+)
+
+func TestWithScrubPatterns(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithScrubPatterns(emailPattern, cardPattern, bearerPattern))
+	ctx := context.Background()
+
+	l.With("contact", "jane@example.com").Error(ctx, "charge for jane@example.com failed",
+		"card", "4111 1111 1111 1111",
+		zap.ByteString("header", []byte("Bearer abc.def")),
+		"err", errors.New("user jane@example.com not found"),
+		"details", map[string]any{"emails": []any{"a@b.io", "ok"}},
+		"count", 3,
+	)
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	require.Equal(t, "charge for [REDACTED] failed", entry["msg"], "messages should be scrubbed")
+	require.Equal(t, "[REDACTED]", entry["contact"], "inherited fields should be scrubbed")
+	require.Equal(t, "[REDACTED]", entry["card"])
+	require.Equal(t, "[REDACTED]", entry["header"])
+	require.Equal(t, "user [REDACTED] not found", entry["err"], "error messages should be scrubbed")
+	require.Equal(t, map[string]any{"emails": []any{"[REDACTED]", "ok"}}, entry["details"], "nested strings should be scrubbed")
+	require.Equal(t, float64(3), entry["count"])
+}
+
+func TestWithScrubPatternsAppliesToExtraCores(t *testing.T) {
+	core, observed := observer.New(zap.InfoLevel)
+	l, _ := newMemoryLogger(t, logger.WithScrubPatterns(emailPattern), logger.WithCore(core))
+
+	l.Info(context.Background(), "mail jane@example.com")
+
+	require.Equal(t, "mail [REDACTED]", observed.All()[0].Message)
+}
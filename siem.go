@@ -0,0 +1,251 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// siemBufferPool provides the buffers of the entries encoded by siemEncoder.
+var siemBufferPool = buffer.NewPool()
+
+// siemSeverities maps levels to the 0 to 10 severities of CEF and LEEF.
+var siemSeverities = map[zapcore.Level]int{
+	zapcore.DebugLevel:  1,
+	zapcore.InfoLevel:   3,
+	zapcore.WarnLevel:   5,
+	zapcore.ErrorLevel:  7,
+	zapcore.DPanicLevel: 8,
+	zapcore.PanicLevel:  9,
+	zapcore.FatalLevel:  10,
+}
+
+// cefKeys and leefKeys map the keys of the fields added by the Logger and common field keys
+// to the standard CEF extension and LEEF attribute keys.
+var (
+	cefKeys = map[string]string{
+		"service":    "dproc",
+		RequestIDKey: "externalId",
+		UserIDKey:    "suser",
+		"client_ip":  "src",
+		"method":     "requestMethod",
+		"url":        "request",
+		"action":     "act",
+		"outcome":    "outcome",
+		"reason":     "reason",
+	}
+	leefKeys = map[string]string{
+		RequestIDKey: "identSrc",
+		UserIDKey:    "usrName",
+		"client_ip":  "src",
+		"action":     "action",
+		"reason":     "reason",
+	}
+)
+
+// SIEMEventIDKey is the key of the field holding the signature ID of a CEF entry and the
+// event ID of a LEEF entry, which identify the type of the event. Entries without the field
+// use their message.
+const SIEMEventIDKey = "event_id"
+
+// WithSIEMDevice sets the vendor, product and version of the headers of FormatCEF and
+// FormatLEEF entries. The vendor and product default to the service name and the version to 1.0.
+func WithSIEMDevice(vendor, product, version string) Option {
+	return func(l *Logger) {
+		l.siemDevice = [3]string{vendor, product, version}
+	}
+}
+
+// WithSIEMFieldMap maps field keys to CEF extension or LEEF attribute keys, in addition to or
+// replacing the default mapping, e.g. {"tenant": "cs1"}. By default, fields such as user_id,
+// request_id and client_ip are mapped to the standard keys of the format; other fields keep
+// their key.
+func WithSIEMFieldMap(keys map[string]string) Option {
+	return func(l *Logger) {
+		if l.siemKeys == nil {
+			l.siemKeys = make(map[string]string)
+		}
+		for k, v := range keys {
+			l.siemKeys[k] = v
+		}
+	}
+}
+
+// newSIEMEncoder returns the encoder of FormatCEF or FormatLEEF.
+func (l *Logger) newSIEMEncoder(service string) zapcore.Encoder {
+	keys := cefKeys
+	if l.format == FormatLEEF {
+		keys = leefKeys
+	}
+	merged := make(map[string]string, len(keys)+len(l.siemKeys))
+	for k, v := range keys {
+		merged[k] = v
+	}
+	for k, v := range l.siemKeys {
+		merged[k] = v
+	}
+
+	device := l.siemDevice
+	for i, def := range []string{service, service, "1.0"} {
+		if device[i] == "" {
+			device[i] = def
+		}
+	}
+	return &siemEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		leef:             l.format == FormatLEEF,
+		device:           device,
+		keys:             merged,
+	}
+}
+
+// siemEncoder is a zapcore.Encoder writing entries as CEF or LEEF events, one per line.
+type siemEncoder struct {
+	*zapcore.MapObjectEncoder
+	leef   bool
+	device [3]string         // Vendor, product and version.
+	keys   map[string]string // Field keys to extension keys.
+
+	namespaces []string // Keys of the open namespaces, outermost first.
+}
+
+// OpenNamespace implements zapcore.ObjectEncoder, recording the namespace for Clone.
+func (e *siemEncoder) OpenNamespace(key string) {
+	e.MapObjectEncoder.OpenNamespace(key)
+	e.namespaces = append(e.namespaces[:len(e.namespaces):len(e.namespaces)], key)
+}
+
+// Clone implements zapcore.Encoder. The fields of the open namespaces are copied into new maps,
+// which stay open, so that the clone and e do not add fields to each other's namespaces.
+func (e *siemEncoder) Clone() zapcore.Encoder {
+	clone := *e
+	clone.MapObjectEncoder = zapcore.NewMapObjectEncoder()
+	fields := e.Fields
+	for i := 0; ; i++ {
+		for k, v := range fields {
+			if i < len(e.namespaces) && k == e.namespaces[i] {
+				continue
+			}
+			_ = clone.MapObjectEncoder.AddReflected(k, v)
+		}
+		if i == len(e.namespaces) {
+			return &clone
+		}
+		clone.MapObjectEncoder.OpenNamespace(e.namespaces[i])
+		fields, _ = fields[e.namespaces[i]].(map[string]interface{})
+	}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e *siemEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*siemEncoder)
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	if ent.Stack != "" {
+		enc.Fields["stacktrace"] = ent.Stack
+	}
+
+	ext := make(map[string]string, len(enc.Fields)+2)
+	flattenSIEMFields(ext, "", enc.Fields)
+	eventID := ent.Message
+	if id, ok := ext[SIEMEventIDKey]; ok {
+		eventID = id
+		delete(ext, SIEMEventIDKey)
+	}
+	attrs := make(map[string]string, len(ext)+3)
+	for k, v := range ext {
+		if key, ok := e.keys[k]; ok {
+			k = key
+		}
+		attrs[k] = v
+	}
+
+	buf := siemBufferPool.Get()
+	severity := strconv.Itoa(siemSeverities[ent.Level])
+	vendor, product, version := e.device[0], e.device[1], e.device[2]
+	if e.leef {
+		attrs["sev"] = severity
+		attrs["devTime"] = ent.Time.Format("2006-01-02T15:04:05.000Z07:00")
+		attrs["devTimeFormat"] = "yyyy-MM-dd'T'HH:mm:ss.SSSXXX"
+		attrs["msg"] = ent.Message
+		buf.AppendString("LEEF:1.0|" + siemHeaderEscaper.Replace(vendor) + "|" + siemHeaderEscaper.Replace(product) + "|" +
+			siemHeaderEscaper.Replace(version) + "|" + siemHeaderEscaper.Replace(eventID) + "|")
+		writeSIEMAttributes(buf, attrs, "\t", leefValueEscaper)
+	} else {
+		attrs["rt"] = strconv.FormatInt(ent.Time.UnixMilli(), 10)
+		attrs["msg"] = ent.Message
+		buf.AppendString("CEF:0|" + siemHeaderEscaper.Replace(vendor) + "|" + siemHeaderEscaper.Replace(product) + "|" +
+			siemHeaderEscaper.Replace(version) + "|" + siemHeaderEscaper.Replace(eventID) + "|" +
+			siemHeaderEscaper.Replace(ent.Message) + "|" + severity + "|")
+		writeSIEMAttributes(buf, attrs, " ", cefValueEscaper)
+	}
+	buf.AppendByte('\n')
+	return buf, nil
+}
+
+// writeSIEMAttributes writes attrs as key=value pairs in sorted order.
+func writeSIEMAttributes(buf *buffer.Buffer, attrs map[string]string, sep string, escaper *strings.Replacer) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i > 0 {
+			buf.AppendString(sep)
+		}
+		buf.AppendString(siemKey(k) + "=" + escaper.Replace(attrs[k]))
+	}
+}
+
+// flattenSIEMFields adds the fields to ext as strings, joining the keys of nested namespaces
+// with dots. Arrays and objects are written as JSON.
+func flattenSIEMFields(ext map[string]string, prefix string, fields map[string]interface{}) {
+	for k, v := range fields {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flattenSIEMFields(ext, prefix+k+".", v)
+		case string:
+			ext[prefix+k] = v
+		case time.Time:
+			ext[prefix+k] = v.Format(time.RFC3339Nano)
+		case fmt.Stringer:
+			ext[prefix+k] = v.String()
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+			ext[prefix+k] = fmt.Sprint(v)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				data = []byte(fmt.Sprint(v))
+			}
+			ext[prefix+k] = string(data)
+		}
+	}
+}
+
+// siemKey turns a field key into a valid extension key, replacing the characters other than
+// letters, digits, '_' and '.'.
+func siemKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+var (
+	// siemHeaderEscaper escapes the header fields of CEF and LEEF events.
+	siemHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	// cefValueEscaper escapes CEF extension values.
+	cefValueEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	// leefValueEscaper escapes LEEF attribute values, which are delimited by tabs.
+	leefValueEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+)
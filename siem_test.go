@@ -0,0 +1,71 @@
+package logger_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFormatCEF(t *testing.T) {
+	l, sink := newMemoryLogger(t,
+		logger.WithFormat(logger.FormatCEF),
+		logger.WithSIEMDevice("Acme", "Checkout", "2.1"),
+		logger.WithSIEMFieldMap(map[string]string{"tenant": "cs1"}),
+	)
+
+	l.Error(context.Background(), "login failed | locked",
+		logger.SIEMEventIDKey, "auth-401",
+		"user_id", "ada",
+		"client_ip", "10.0.0.7",
+		"tenant", "eu",
+		"note", "a=b\nc",
+	)
+
+	line := strings.TrimSpace(sink.logs.String())
+	require.True(t, strings.HasPrefix(line, `CEF:0|Acme|Checkout|2.1|auth-401|login failed \| locked|7|`), line)
+	require.Regexp(t, regexp.MustCompile(` rt=\d{13}( |$)`), line)
+	for _, want := range []string{"suser=ada", "src=10.0.0.7", "cs1=eu", `note=a\=b\nc`, "dproc=test-service", "msg=login failed | locked"} {
+		require.Contains(t, line, want)
+	}
+	require.NotContains(t, line, "event_id")
+}
+
+func TestFormatLEEF(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatLEEF))
+
+	l.With("request_id", "r-1").Info(context.Background(), "order placed", "user_id", "ada", "note", "a\tb")
+
+	line := strings.TrimSpace(sink.logs.String())
+	require.True(t, strings.HasPrefix(line, "LEEF:1.0|test-service|test-service|1.0|order placed|"), line)
+	attrs := strings.Split(line[strings.LastIndex(line[:strings.Index(line, "=")], "|")+1:], "\t")
+	require.Contains(t, attrs, "sev=3")
+	require.Contains(t, attrs, "usrName=ada")
+	require.Contains(t, attrs, "identSrc=r-1")
+	require.Contains(t, attrs, `note=a\tb`)
+	require.Contains(t, attrs, "service=test-service")
+	require.Contains(t, attrs, "msg=order placed")
+}
+
+func TestFormatCEFNamespace(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithFormat(logger.FormatCEF))
+
+	child := l.With("tenant", "eu", zap.Namespace("http"), "method", "GET")
+	child.Info(context.Background(), "first", "status", 200)
+	l.Info(context.Background(), "second", "status", 201)
+	child.Info(context.Background(), "third", "status", 404)
+
+	lines := strings.Split(strings.TrimSpace(sink.logs.String()), "\n")
+	require.Len(t, lines, 3)
+	for _, want := range []string{"tenant=eu", "http.method=GET", "http.status=200"} {
+		require.Contains(t, lines[0], want)
+	}
+	require.Contains(t, lines[1], "status=201")
+	require.NotContains(t, lines[1], "http.", "the parent should not share the child's namespace")
+	require.Contains(t, lines[2], "http.status=404")
+	require.NotContains(t, lines[2], "status=200")
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// simulatedTimeLayout is the layout of the ISO 8601 timestamps written by the logger.
+const simulatedTimeLayout = "2006-01-02T15:04:05.000Z0700"
+
+// SimulatedEntry is a recorded entry replayed by Simulate.
+type SimulatedEntry struct {
+	Time    time.Time
+	Level   zapcore.Level
+	Message string
+	// Fields holds the entry's fields, including markers such as Privacy and RateLimitKey.
+	// A trace_id string field is used for WithTraceSampling.
+	Fields []zapcore.Field
+}
+
+// SimulationReport describes what a configuration would do with a stream of entries.
+type SimulationReport struct {
+	// Entries is the number of simulated entries.
+	Entries int
+	// Sinks reports the entries per sink. The output paths are reported as "outputs" and
+	// the cores added through WithCore or WithClearance by their type, as in Stats; cores of
+	// the same type are counted together.
+	Sinks map[string]SinkSimulation
+}
+
+// SinkSimulation counts the entries a sink would receive.
+type SinkSimulation struct {
+	// Emitted is the number of entries written to the sink, including the summaries of WithDedup.
+	Emitted int
+	// Dropped is the number of simulated entries not written to the sink, because of the level,
+	// sampling, rate limiting, deduplication or privacy routing.
+	Dropped int
+	// Levels counts the emitted entries by level.
+	Levels map[zapcore.Level]int
+}
+
+// Simulate replays recorded entries through the level, sampling, rate limiting, deduplication
+// and routing configured by opts, and reports what each sink would receive, so that
+// configuration changes can be validated before they are rolled out. The entries' own
+// timestamps are used as the clock. Nothing is written: the output paths are not opened, the
+// cores are not written to, and hooks and blob stores are not called.
+func Simulate(entries []SimulatedEntry, opts ...Option) SimulationReport {
+	l := configure(opts)
+	l.hooks, l.contextHooks, l.writeErrorHooks, l.offloadStore, l.stats = nil, nil, nil, nil, nil
+
+	var (
+		mu     sync.Mutex
+		counts = make(map[string]*SinkSimulation)
+	)
+	counter := func(name string) *simulatedCore {
+		if _, ok := counts[name]; !ok {
+			counts[name] = &SinkSimulation{Levels: make(map[zapcore.Level]int)}
+		}
+		return &simulatedCore{mu: &mu, counts: counts[name]}
+	}
+
+	outputs := counter(outputsSinkName)
+	outputs.LevelEnabler = zapcore.DebugLevel
+	cores := make([]route, len(l.cores))
+	for i, r := range l.cores {
+		c := counter(sinkName(r.core))
+		c.LevelEnabler = r.core
+		cores[i] = route{core: c, clearance: r.clearance}
+	}
+	l.cores = cores
+	core := l.wrapCore(outputs)
+
+	for _, e := range entries {
+		if l.sampledOut(e.Level, simulatedTraceID(e.Fields)) {
+			continue
+		}
+		ent := zapcore.Entry{Level: e.Level, Time: e.Time, Message: e.Message}
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write(e.Fields...)
+		}
+	}
+	_ = core.Sync()
+
+	report := SimulationReport{Entries: len(entries), Sinks: make(map[string]SinkSimulation, len(counts))}
+	for name, c := range counts {
+		c.Dropped = max(len(entries)-c.Emitted, 0)
+		report.Sinks[name] = *c
+	}
+	return report
+}
+
+// simulatedTraceID returns the trace_id field among fields, if any.
+func simulatedTraceID(fields []zapcore.Field) string {
+	for _, f := range fields {
+		if f.Key == "trace_id" && f.Type == zapcore.StringType {
+			return f.String
+		}
+	}
+	return ""
+}
+
+// simulatedCore is a zapcore.Core counting the entries written to a sink.
+type simulatedCore struct {
+	zapcore.LevelEnabler
+	mu     *sync.Mutex
+	counts *SinkSimulation
+}
+
+// With implements zapcore.Core.
+func (c *simulatedCore) With(_ []zapcore.Field) zapcore.Core {
+	return c
+}
+
+// Check implements zapcore.Core.
+func (c *simulatedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *simulatedCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts.Emitted++
+	c.counts.Levels[ent.Level]++
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (c *simulatedCore) Sync() error {
+	return nil
+}
+
+// ReadSimulatedEntries reads entries recorded in the default JSON format, one per line, for
+// Simulate. The ts, level and msg keys become the entry's time, level and message, the caller
+// is skipped, and all other keys become fields. Lines that are not JSON are skipped.
+func ReadSimulatedEntries(r io.Reader) ([]SimulatedEntry, error) {
+	var entries []SimulatedEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil || fields == nil {
+			continue
+		}
+
+		var e SimulatedEntry
+		if ts, ok := fields["ts"].(string); ok {
+			t, err := time.Parse(simulatedTimeLayout, ts)
+			if err != nil {
+				return nil, fmt.Errorf("read simulated entries: invalid ts %q", ts)
+			}
+			e.Time = t
+		}
+		if level, ok := fields["level"].(string); ok {
+			if err := e.Level.UnmarshalText([]byte(level)); err != nil {
+				return nil, fmt.Errorf("read simulated entries: %w", err)
+			}
+		}
+		e.Message, _ = fields["msg"].(string)
+		for _, key := range []string{"ts", "level", "msg", "caller"} {
+			delete(fields, key)
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			e.Fields = append(e.Fields, zap.Any(key, fields[key]))
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read simulated entries: %w", err)
+	}
+	return entries, nil
+}
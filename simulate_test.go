@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSimulate(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var entries []logger.SimulatedEntry
+	for i := 0; i < 10; i++ {
+		entries = append(entries, logger.SimulatedEntry{
+			Time:    start.Add(time.Duration(i) * time.Millisecond),
+			Level:   zapcore.ErrorLevel,
+			Message: "connection refused",
+		})
+	}
+	entries = append(entries,
+		logger.SimulatedEntry{Time: start, Level: zapcore.DebugLevel, Message: "verbose"},
+		logger.SimulatedEntry{
+			Time:    start,
+			Level:   zapcore.InfoLevel,
+			Message: "card holder",
+			Fields:  []zapcore.Field{logger.Privacy(logger.Restricted)},
+		},
+	)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	report := logger.Simulate(entries,
+		logger.WithRateLimit(1, 3),
+		logger.WithClearance(logger.Public, core),
+	)
+
+	require.Equal(t, 12, report.Entries)
+	outputs := report.Sinks["outputs"]
+	require.Equal(t, 4, outputs.Emitted, "3 rate limited errors and the restricted entry")
+	require.Equal(t, 8, outputs.Dropped)
+	require.Equal(t, 3, outputs.Levels[zapcore.ErrorLevel])
+
+	public := report.Sinks["*observer.contextObserver"]
+	require.Equal(t, 3, public.Emitted, "the restricted entry is not routed to public sinks")
+	require.Zero(t, logs.Len(), "simulated entries are not written")
+}
+
+func TestReadSimulatedEntries(t *testing.T) {
+	recorded := `{"level":"error","ts":"2026-01-01T00:00:00.000Z","caller":"app/main.go:1","msg":"failed","order":42,"trace_id":"abc"}
+not json
+{"level":"info","ts":"2026-01-01T00:00:01.000Z","msg":"ok","trace_id":"def"}
+`
+	entries, err := logger.ReadSimulatedEntries(strings.NewReader(recorded))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	require.Equal(t, "failed", entries[0].Message)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), entries[0].Time.UTC())
+	require.Len(t, entries[0].Fields, 2)
+	require.Equal(t, "order", entries[0].Fields[0].Key)
+	require.Equal(t, "trace_id", entries[0].Fields[1].Key)
+
+	report := logger.Simulate(entries, logger.WithTraceSampling(0))
+	require.Equal(t, 1, report.Sinks["outputs"].Emitted, "only the error should be kept")
+}
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkConfig describes the Logger a sink is created for, so that a SinkFactory can adapt
+// the sink to the encoded entries it receives.
+type SinkConfig struct {
+	// URL is the output path the sink is created for.
+	URL *url.URL
+	// Service is the service name passed to New.
+	Service string
+	// Format is the format the entries are encoded in.
+	Format Format
+	// EncoderConfig is the configuration of the encoder, e.g. holding the keys of the level,
+	// timestamp and message in JSON encoded entries.
+	EncoderConfig zapcore.EncoderConfig
+}
+
+// SinkFactory creates a sink for an output path of a registered scheme.
+type SinkFactory func(cfg SinkConfig) (zap.Sink, error)
+
+// HealthChecker is implemented by sinks that can report whether they are currently able
+// to accept entries, for example whether their remote endpoint is reachable.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// sinkFactories holds the factories registered through RegisterSinkFactory, by scheme.
+var sinkFactories = struct {
+	sync.RWMutex
+	byScheme map[string]SinkFactory
+}{byScheme: make(map[string]SinkFactory)}
+
+// schemePattern matches valid URL schemes (RFC 3986).
+var schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+// RegisterSinkFactory registers a factory for the output paths with the given scheme.
+// Unlike zap.RegisterSink, the factory is called with the configuration of the Logger the
+// sink is created for. Packages providing sinks typically register them in an init function,
+// see the packages under sinks/. Registering a scheme twice is an error.
+func RegisterSinkFactory(scheme string, factory SinkFactory) error {
+	if !schemePattern.MatchString(scheme) {
+		return fmt.Errorf("invalid sink scheme %q", scheme)
+	}
+	if factory == nil {
+		return fmt.Errorf("nil sink factory for scheme %q", scheme)
+	}
+
+	sinkFactories.Lock()
+	defer sinkFactories.Unlock()
+
+	if _, ok := sinkFactories.byScheme[scheme]; ok {
+		return fmt.Errorf("sink factory already registered for scheme %q", scheme)
+	}
+	sinkFactories.byScheme[scheme] = factory
+	return nil
+}
+
+// sinkFactory returns the factory registered for the scheme of path, if any.
+func sinkFactory(path string) (*url.URL, SinkFactory) {
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme == "" {
+		return nil, nil
+	}
+
+	sinkFactories.RLock()
+	defer sinkFactories.RUnlock()
+	return u, sinkFactories.byScheme[u.Scheme]
+}
+
+// secretParams matches the query parameters of output paths holding credentials, such as the
+// token of the splunk sink and the bearer token of the webhook sink.
+var secretParams = keyMatcher{"*token*", "bearer", "*secret*", "*password*", "*key", "auth*", "sig", "signature"}
+
+// redactPath returns path with the userinfo and the credentials in the query of an output
+// path URL masked, like url.URL.Redacted, so that it can be logged or returned in errors.
+// Other paths are returned unchanged.
+func redactPath(path string) string {
+	u, err := url.Parse(path)
+	if err != nil || (u.User == nil && u.RawQuery == "") {
+		return path
+	}
+
+	changed := u.User != nil
+	if changed {
+		u.User = url.User("xxxxx")
+	}
+	q := u.Query()
+	for param := range q {
+		if secretParams.matches(param) {
+			q[param] = []string{"xxxxx"}
+			changed = true
+		}
+	}
+	if !changed {
+		return path
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// openOptions configures openSinks.
+type openOptions struct {
+	// benign filters the Sync errors of the sinks.
+	benign SyncErrorFilter
+	// verified are the output paths whose writes are verified, see WithVerifiedWrites.
+	verified []string
+	// fallback receives the failed writes, if set, see WithFallbackOutput.
+	fallback *fallbackOutput
+	// unsyncedConsole skips syncing stdout and stderr, see WithLambdaMode.
+	unsyncedConsole bool
+}
+
+// openSinks opens the output paths, creating sinks of registered schemes through their
+// factory and all others through zap.Open. It returns the combined sinks, the sinks created
+// through a factory by output path, and a function closing them.
+func openSinks(paths []string, cfg SinkConfig, opts openOptions) (zapcore.WriteSyncer, map[string]zap.Sink, func(), error) {
+	var (
+		syncers []zapcore.WriteSyncer
+		closers []func()
+		sinks   = make(map[string]zap.Sink)
+		verify  = make(map[string]bool)
+	)
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+	for _, path := range opts.verified {
+		verify[path] = true
+	}
+
+	for _, path := range paths {
+		var ws zapcore.WriteSyncer
+		u, factory := sinkFactory(path)
+		if factory == nil {
+			zws, closeSink, err := zap.Open(path)
+			if err != nil {
+				closeAll()
+				return nil, nil, nil, err
+			}
+			ws = zws
+			closers = append(closers, closeSink)
+			if verify[path] {
+				vs, closeFile, err := newReadBackSink(ws, path)
+				if err != nil {
+					closeAll()
+					return nil, nil, nil, err
+				}
+				ws = vs
+				closers = append(closers, closeFile)
+			}
+		} else {
+			cfg.URL = u
+			sink, err := factory(cfg)
+			if err != nil {
+				closeAll()
+				return nil, nil, nil, fmt.Errorf("open sink %q: %w", redactPath(path), err)
+			}
+			ws = zapcore.Lock(sink)
+			closers = append(closers, func() { _ = sink.Close() })
+			sinks[path] = sink
+			if verify[path] {
+				vs, err := newVerifierSink(ws, path, sink)
+				if err != nil {
+					closeAll()
+					return nil, nil, nil, err
+				}
+				ws = vs
+			}
+		}
+		delete(verify, path)
+		if opts.unsyncedConsole && (path == "stdout" || path == "stderr") {
+			ws = unsyncedWriter{ws}
+		}
+		if opts.fallback != nil {
+			ws = &fallbackSink{WriteSyncer: ws, path: path, fallback: opts.fallback}
+		}
+		syncers = append(syncers, &syncFilter{WriteSyncer: ws, path: path, benign: opts.benign})
+	}
+	for path := range verify {
+		closeAll()
+		return nil, nil, nil, fmt.Errorf("verify writes to %q: not an output path", path)
+	}
+	return zap.CombineWriteSyncers(syncers...), sinks, closeAll, nil
+}
+
+// BatchingSink wraps sink so that its Write receives batches of encoded entries, one per
+// line, of up to size bytes, at least every flushInterval. It can be returned by a
+// SinkFactory for destinations with a high cost per write; a non-positive size or
+// flushInterval selects a default of 256 kB or 30 seconds. Closing the returned sink flushes
+// the pending batch.
+func BatchingSink(sink zap.Sink, size int, flushInterval time.Duration) zap.Sink {
+	return &batchingSink{
+		BufferedWriteSyncer: &zapcore.BufferedWriteSyncer{WS: sink, Size: size, FlushInterval: flushInterval},
+		sink:                sink,
+	}
+}
+
+// batchingSink is a zap.Sink buffering the writes to another sink.
+type batchingSink struct {
+	*zapcore.BufferedWriteSyncer
+	sink zap.Sink
+}
+
+// Health implements HealthChecker by forwarding to the wrapped sink, if it implements it.
+func (s *batchingSink) Health(ctx context.Context) error {
+	if hc, ok := s.sink.(HealthChecker); ok {
+		return hc.Health(ctx)
+	}
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *batchingSink) Close() error {
+	return errors.Join(s.Stop(), s.sink.Close())
+}
+
+// Unwrap returns the wrapped sink.
+func (s *batchingSink) Unwrap() zap.Sink {
+	return s.sink
+}
@@ -0,0 +1,106 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// registerSinkFactory registers factory under a fresh scheme and returns the scheme.
+func registerSinkFactory(t *testing.T, factory logger.SinkFactory) string {
+	t.Helper()
+
+	scheme := fmt.Sprintf("factory%d", sinkCounter.Add(1))
+	require.NoError(t, logger.RegisterSinkFactory(scheme, factory))
+	return scheme
+}
+
+func TestRegisterSinkFactory(t *testing.T) {
+	sink := &memorySink{}
+	var got logger.SinkConfig
+	scheme := registerSinkFactory(t, func(cfg logger.SinkConfig) (zap.Sink, error) {
+		got = cfg
+		return sink, nil
+	})
+
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{scheme + "://collector:24224?tag=app"}),
+		logger.WithFormat(logger.FormatConsole),
+		logger.WithColor(logger.ColorNever),
+	)
+	require.NoError(t, err)
+	l.Info(context.Background(), "through the factory")
+
+	require.Equal(t, "collector:24224", got.URL.Host)
+	require.Equal(t, "app", got.URL.Query().Get("tag"))
+	require.Equal(t, "test-service", got.Service)
+	require.Equal(t, logger.FormatConsole, got.Format)
+	require.Equal(t, "msg", got.EncoderConfig.MessageKey)
+	require.Contains(t, sink.logs.String(), "through the factory")
+}
+
+// closingSink is a memorySink counting the calls to Close.
+type closingSink struct {
+	memorySink
+	closed int
+}
+
+// Close implements zap.Sink.
+func (s *closingSink) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestCloseClosesSinks(t *testing.T) {
+	sink := &closingSink{}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{scheme + "://"}), logger.WithBufferedWrites(0, 0))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "before close")
+	require.NoError(t, l.Close(context.Background()))
+	require.NoError(t, l.Close(context.Background()))
+
+	require.Contains(t, sink.logs.String(), "before close", "entries should be flushed before the sink is closed")
+	require.Equal(t, 1, sink.closed)
+}
+
+func TestRegisterSinkFactoryErrors(t *testing.T) {
+	factory := func(_ logger.SinkConfig) (zap.Sink, error) { return &memorySink{}, nil }
+	scheme := registerSinkFactory(t, factory)
+
+	require.Error(t, logger.RegisterSinkFactory(scheme, factory), "schemes can only be registered once")
+	require.Error(t, logger.RegisterSinkFactory("not a scheme", factory))
+	require.Error(t, logger.RegisterSinkFactory("nilfactory", nil))
+
+	failing := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) {
+		return nil, fmt.Errorf("unreachable")
+	})
+	_, err := logger.New("test-service", logger.WithOutputPaths([]string{failing + "://"}))
+	require.ErrorContains(t, err, "unreachable")
+
+	_, err = logger.New("test-service", logger.WithOutputPaths([]string{failing + "://collector?token=s3cret&tag=app"}))
+	require.ErrorContains(t, err, failing+"://collector?tag=app&token=xxxxx", "credentials should be masked")
+	require.NotContains(t, err.Error(), "s3cret")
+}
+
+func TestBatchingSink(t *testing.T) {
+	sink := &memorySink{}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) {
+		return logger.BatchingSink(sink, 1<<20, time.Hour), nil
+	})
+
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{scheme + "://"}))
+	require.NoError(t, err)
+	l.Info(context.Background(), "first")
+	l.Info(context.Background(), "second")
+	require.Empty(t, sink.logs.String(), "entries should be batched")
+
+	require.NoError(t, l.Sync())
+	require.Len(t, decodeLines(t, sink), 2)
+}
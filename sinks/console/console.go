@@ -0,0 +1,82 @@
+// Package console registers a zap sink for WebAssembly builds, so shared code using the
+// logger can run in browsers and WASM-based plugins. Importing the package registers the
+// "console" scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/console"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{"console://"}))
+//
+// With GOOS=js, every entry is passed to the JavaScript console, using console.error,
+// console.warn, console.info or console.debug depending on the level of JSON encoded entries,
+// so that browsers' developer tools can filter them. On every other platform, including
+// wasip1, the sink falls back to writing to the standard output.
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+)
+
+// Scheme is the output path scheme of the console sink.
+const Scheme = "console"
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// sink is a zap.Sink handing every line to the platform's write function.
+type sink struct {
+	mu    sync.Mutex
+	write func(method string, line []byte) error
+}
+
+// Write implements io.Writer. p holds one or more encoded entries, one per line.
+func (s *sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if err := s.write(method(line), line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Sync implements zap.Sink. Lines are written as they are received.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	return nil
+}
+
+// method returns the name of the console method matching the level of a JSON encoded line,
+// or "log" if the line carries no known level.
+func method(line []byte) string {
+	var entry struct {
+		Level string `json:"level"`
+	}
+	if json.Unmarshal(line, &entry) != nil {
+		return "log"
+	}
+	switch entry.Level {
+	case "debug":
+		return "debug"
+	case "info":
+		return "info"
+	case "warn":
+		return "warn"
+	case "error", "dpanic", "panic", "fatal":
+		return "error"
+	default:
+		return "log"
+	}
+}
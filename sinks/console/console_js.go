@@ -0,0 +1,19 @@
+//go:build js
+
+package console
+
+import (
+	"syscall/js"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap"
+)
+
+// newSink creates a sink passing the lines to the JavaScript console.
+func newSink(_ logger.SinkConfig) (zap.Sink, error) {
+	console := js.Global().Get("console")
+	return &sink{write: func(method string, line []byte) error {
+		console.Call(method, string(line))
+		return nil
+	}}, nil
+}
@@ -0,0 +1,19 @@
+//go:build !js
+
+package console
+
+import (
+	"os"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap"
+)
+
+// newSink creates a sink writing the lines to the standard output, since there is no
+// JavaScript console outside GOOS=js.
+func newSink(_ logger.SinkConfig) (zap.Sink, error) {
+	return &sink{write: func(_ string, line []byte) error {
+		_, err := os.Stdout.Write(append(line, '\n'))
+		return err
+	}}, nil
+}
@@ -0,0 +1,33 @@
+//go:build !js
+
+package console_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	_ "github.com/janduursma/zap-logger-wrapper/v2/sinks/console"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleFallsBackToStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{"console://"}))
+	require.NoError(t, err)
+	l.Info(context.Background(), "hello from wasm")
+	l.Error(context.Background(), "failure")
+
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"msg":"hello from wasm"`)
+	require.Contains(t, string(out), `"msg":"failure"`)
+}
@@ -0,0 +1,22 @@
+package console_test
+
+import (
+	"testing"
+
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/console"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethod(t *testing.T) {
+	for line, want := range map[string]string{
+		`{"level":"debug","msg":"x"}`:  "debug",
+		`{"level":"info","msg":"x"}`:   "info",
+		`{"level":"warn","msg":"x"}`:   "warn",
+		`{"level":"error","msg":"x"}`:  "error",
+		`{"level":"dpanic","msg":"x"}`: "error",
+		`{"msg":"x"}`:                  "log",
+		"INFO\tconsole encoded line":   "log",
+	} {
+		require.Equal(t, want, console.Method([]byte(line)), line)
+	}
+}
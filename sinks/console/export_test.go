@@ -0,0 +1,4 @@
+package console
+
+// Internal functions exported for tests in package console_test.
+var Method = method
@@ -0,0 +1,17 @@
+package fluentd
+
+import (
+	"bufio"
+)
+
+// Decode exports decode for testing.
+func Decode(r *bufio.Reader) (interface{}, error) {
+	return decode(r)
+}
+
+// Encode returns the MessagePack encoding of v, for testing.
+func Encode(v interface{}) []byte {
+	var e encoder
+	e.value(v)
+	return e.buf
+}
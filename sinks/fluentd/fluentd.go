@@ -0,0 +1,307 @@
+// Package fluentd registers a zap sink that ships entries to Fluentd or Fluent Bit using the
+// forward protocol, MessagePack over TCP. Importing the package registers the "fluentd"
+// scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/fluentd"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{
+//		"fluentd://aggregator:24224?tag=app.checkout&ack=true",
+//	}))
+//
+// The following query parameters are supported:
+//   - tag: the tag of the events; the service name passed to logger.New by default.
+//   - ack: true to wait for the aggregator to acknowledge every message (at-least-once).
+//   - timeout: how long to wait for connecting, writing and acknowledgements, 5s by default.
+//   - spool: a directory spooling the writes that fail, which are replayed in order once the
+//     aggregator is reachable again; spool_max_bytes bounds its size, 64 MB by default, and
+//     spool_retry sets how often replaying is attempted, 5s by default. See
+//     logger.SpoolingSink.
+//
+// The port defaults to 24224. The sink connects on the first write and reconnects when the
+// connection is lost, retrying a failed write once. The sink expects JSON encoded entries, the
+// default format; their fields, except the timestamp, become the record of the event. Writes
+// holding several entries, e.g. with BatchingSink or WithBufferedWrites, are sent as a single
+// forward mode message. The sink implements logger.HealthChecker by connecting to the
+// aggregator, if it is not connected yet. With ack=true and without a spool, the output path
+// can be passed to logger.WithVerifiedWrites, since a write only succeeds once the aggregator
+// acknowledged it.
+package fluentd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/record"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/spool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scheme is the output path scheme of the fluentd sink.
+const Scheme = "fluentd"
+
+// defaultTimeout bounds connecting, writing and waiting for acknowledgements.
+const defaultTimeout = 5 * time.Second
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// sink is a zap.Sink sending entries to a forward protocol server.
+type sink struct {
+	addr    string
+	tag     string
+	ack     bool
+	timeout time.Duration
+	encoder zapcore.EncoderConfig
+
+	mu   sync.Mutex
+	conn *connection
+}
+
+// newSink creates a sink from a fluentd:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	u := cfg.URL
+	q := u.Query()
+	s := &sink{
+		addr:    u.Host,
+		tag:     q.Get("tag"),
+		timeout: defaultTimeout,
+		encoder: cfg.EncoderConfig,
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("fluentd: missing host in %q", u.String())
+	}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), "24224")
+	}
+	if s.tag == "" {
+		s.tag = cfg.Service
+	}
+	if s.tag == "" {
+		return nil, errors.New("fluentd: missing tag")
+	}
+	if ack := q.Get("ack"); ack != "" {
+		var err error
+		if s.ack, err = strconv.ParseBool(ack); err != nil {
+			return nil, fmt.Errorf("fluentd: invalid ack %q", ack)
+		}
+	}
+	if timeout := q.Get("timeout"); timeout != "" {
+		var err error
+		if s.timeout, err = time.ParseDuration(timeout); err != nil || s.timeout <= 0 {
+			return nil, fmt.Errorf("fluentd: invalid timeout %q", timeout)
+		}
+	}
+
+	spooled, err := spool.Wrap(s, q)
+	if err != nil {
+		return nil, fmt.Errorf("fluentd: %w", err)
+	}
+	return spooled, nil
+}
+
+// Write implements io.Writer. p holds one or more encoded entries, one per line, which are
+// sent together in a single message.
+func (s *sink) Write(p []byte) (int, error) {
+	lines := record.Lines(p)
+	if len(lines) == 0 {
+		return len(p), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, chunk := s.message(lines)
+	if err := s.send(msg, chunk); err != nil {
+		s.disconnect()
+		if err = s.send(msg, chunk); err != nil {
+			s.disconnect()
+			return 0, fmt.Errorf("fluentd: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+// message encodes lines as a forward mode message: [tag, [[time, record]...], option].
+// It returns the message and, when acknowledgements are enabled, its chunk ID.
+func (s *sink) message(lines [][]byte) ([]byte, string) {
+	var e encoder
+	e.arrayHeader(3)
+	e.string(s.tag)
+	e.arrayHeader(len(lines))
+	for _, line := range lines {
+		fields, ok := record.Decode(line)
+		if !ok {
+			fields = map[string]interface{}{s.encoder.MessageKey: string(line)}
+		}
+		t, ok := record.Time(fields[s.encoder.TimeKey])
+		if ok {
+			delete(fields, s.encoder.TimeKey)
+		} else {
+			t = time.Now()
+		}
+
+		e.arrayHeader(2)
+		e.eventTime(t)
+		e.value(fields)
+	}
+
+	var chunk string
+	options := map[string]interface{}{"size": int64(len(lines))}
+	if s.ack {
+		chunk = newChunkID()
+		options["chunk"] = chunk
+	}
+	e.value(options)
+	return e.buf, chunk
+}
+
+// send writes msg and waits for its acknowledgement when chunk is set. s.mu must be held.
+func (s *sink) send(msg []byte, chunk string) error {
+	if s.conn != nil && s.conn.closed() {
+		s.disconnect()
+	}
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.addr, s.timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = newConnection(conn)
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-s.conn.replies:
+		if m, ok := resp.(map[string]interface{}); !ok || m["ack"] != chunk {
+			return fmt.Errorf("unexpected ack %v for chunk %s", resp, chunk)
+		}
+		return nil
+	case <-s.conn.done:
+		return fmt.Errorf("read ack: %w", s.conn.err)
+	case <-timer.C:
+		return errors.New("read ack: timeout")
+	}
+}
+
+// disconnect closes the connection, if any, so the next send reconnects. s.mu must be held.
+func (s *sink) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// connection is a connection to the server whose replies are read in the background, which
+// also detects connections closed by the server before the next message is lost on them.
+type connection struct {
+	net.Conn
+	replies chan interface{}
+	done    chan struct{}
+	err     error // Set before done is closed.
+}
+
+// newConnection starts reading the replies of conn.
+func newConnection(conn net.Conn) *connection {
+	c := &connection{Conn: conn, replies: make(chan interface{}, 1), done: make(chan struct{})}
+	go func() {
+		r := bufio.NewReader(conn)
+		for {
+			reply, err := decode(r)
+			if err != nil {
+				c.err = err
+				close(c.done)
+				return
+			}
+			select {
+			case c.replies <- reply:
+			default: // Nobody is waiting for the reply.
+			}
+		}
+	}()
+	return c
+}
+
+// closed reports whether the connection can no longer be read, typically because the
+// server closed it.
+func (c *connection) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Health implements logger.HealthChecker by connecting to the aggregator, unless the sink
+// is connected already.
+func (s *sink) Health(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil && s.conn.closed() {
+		s.disconnect()
+	}
+	if s.conn != nil {
+		return nil
+	}
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("fluentd: %w", err)
+	}
+	s.conn = newConnection(conn)
+	return nil
+}
+
+// VerifyWrite implements logger.WriteVerifier. A write succeeds only once the aggregator
+// acknowledged it, so writes can only be verified when acknowledgements are enabled.
+func (s *sink) VerifyWrite(_ []byte) error {
+	if !s.ack {
+		return errors.New("fluentd: writes are not acknowledged, set ack=true")
+	}
+	return nil
+}
+
+// Sync implements zap.Sink. Messages are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnect()
+	return nil
+}
+
+// newChunkID returns a random chunk ID for acknowledgements.
+func newChunkID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
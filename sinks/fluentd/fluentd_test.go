@@ -0,0 +1,199 @@
+package fluentd_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/fluentd"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// forwardServer is a fake forward protocol server.
+type forwardServer struct {
+	ln       net.Listener
+	messages chan []interface{}
+}
+
+// newForwardServer starts a server handling connections with handle, which receives every
+// decoded message and returns the bytes to reply, and whether to keep the connection open.
+func newForwardServer(t *testing.T, handle func(msg []interface{}) ([]byte, bool)) *forwardServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &forwardServer{ln: ln, messages: make(chan []interface{}, 16)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				for {
+					v, err := fluentd.Decode(r)
+					if err != nil {
+						return
+					}
+					msg, _ := v.([]interface{})
+					s.messages <- msg
+					reply, keep := handle(msg)
+					if reply != nil {
+						_, _ = conn.Write(reply)
+					}
+					if !keep {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return s
+}
+
+// receive returns the next message received by the server.
+func (s *forwardServer) receive(t *testing.T) []interface{} {
+	t.Helper()
+
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestFluentd(t *testing.T) {
+	server := newForwardServer(t, func(_ []interface{}) ([]byte, bool) { return nil, true })
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String()}))
+	require.NoError(t, err)
+
+	before := time.Now().Add(-time.Second)
+	l.Error(context.Background(), "payment failed", "order", 42, "amount", 9.5)
+
+	msg := server.receive(t)
+	require.Len(t, msg, 3)
+	require.Equal(t, "checkout", msg[0], "the tag should default to the service")
+
+	entries := msg[1].([]interface{})
+	require.Len(t, entries, 1)
+	event := entries[0].([]interface{})
+	ts := time.Time(event[0].(fluentd.EventTime))
+	require.True(t, ts.After(before), ts)
+
+	fields := event[1].(map[string]interface{})
+	require.Equal(t, "payment failed", fields["msg"])
+	require.Equal(t, "error", fields["level"])
+	require.Equal(t, "checkout", fields["service"])
+	require.Equal(t, int64(42), fields["order"])
+	require.Equal(t, 9.5, fields["amount"])
+	require.NotContains(t, fields, "ts")
+	require.Equal(t, map[string]interface{}{"size": int64(1)}, msg[2])
+}
+
+func TestFluentdAck(t *testing.T) {
+	ack := func(msg []interface{}) ([]byte, bool) {
+		chunk := msg[2].(map[string]interface{})["chunk"]
+		return fluentd.Encode(map[string]interface{}{"ack": chunk}), true
+	}
+	server := newForwardServer(t, ack)
+	var writeErr error
+	l, err := logger.New("checkout",
+		logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String() + "?tag=app.logs&ack=true"}),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErr = err }),
+	)
+	require.NoError(t, err)
+	l.Info(context.Background(), "acknowledged")
+
+	msg := server.receive(t)
+	require.Equal(t, "app.logs", msg[0])
+	require.NotEmpty(t, msg[2].(map[string]interface{})["chunk"])
+	require.NoError(t, writeErr)
+}
+
+func TestFluentdWrongAck(t *testing.T) {
+	server := newForwardServer(t, func(_ []interface{}) ([]byte, bool) {
+		return fluentd.Encode(map[string]interface{}{"ack": "something else"}), true
+	})
+
+	var writeErr error
+	l, err := logger.New("checkout",
+		logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String() + "?ack=true&timeout=1s"}),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErr = err }),
+	)
+	require.NoError(t, err)
+	l.Info(context.Background(), "not acknowledged")
+
+	require.ErrorContains(t, writeErr, "unexpected ack")
+}
+
+func TestFluentdHealth(t *testing.T) {
+	server := newForwardServer(t, func(_ []interface{}) ([]byte, bool) { return nil, true })
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String()}))
+	require.NoError(t, err)
+	require.NoError(t, l.Ping(context.Background()))
+
+	require.NoError(t, server.ln.Close())
+	l, err = logger.New("checkout", logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String() + "?timeout=1s"}))
+	require.NoError(t, err)
+	require.Error(t, l.Ping(context.Background()), "the aggregator should be unreachable")
+}
+
+func TestFluentdVerifiedWritesNeedAck(t *testing.T) {
+	server := newForwardServer(t, func(_ []interface{}) ([]byte, bool) { return nil, true })
+	path := "fluentd://" + server.ln.Addr().String()
+
+	var writeErr error
+	l, err := logger.New("checkout",
+		logger.WithOutputPaths([]string{path}),
+		logger.WithVerifiedWrites(path),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErr = err }),
+	)
+	require.NoError(t, err)
+	l.Info(context.Background(), "not verifiable")
+
+	var verifyErr *logger.VerificationError
+	require.ErrorAs(t, writeErr, &verifyErr)
+	require.ErrorContains(t, writeErr, "ack=true")
+}
+
+func TestFluentdReconnects(t *testing.T) {
+	// The server drops the connection after the first message.
+	var messages atomic.Int64
+	server := newForwardServer(t, func(_ []interface{}) ([]byte, bool) { return nil, messages.Add(1) > 1 })
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"fluentd://" + server.ln.Addr().String()}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "first")
+	server.receive(t)
+	time.Sleep(50 * time.Millisecond) // Let the server close the connection.
+	l.Info(context.Background(), "second")
+	l.Info(context.Background(), "third")
+
+	for _, want := range []string{"second", "third"} {
+		msg := server.receive(t)
+		event := msg[1].([]interface{})[0].([]interface{})
+		require.Equal(t, want, event[1].(map[string]interface{})["msg"])
+	}
+}
+
+func TestFluentdInvalidURL(t *testing.T) {
+	for _, path := range []string{
+		"fluentd://",
+		"fluentd://localhost?ack=maybe",
+		"fluentd://localhost?timeout=soon",
+	} {
+		_, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+		require.Error(t, err, path)
+	}
+}
@@ -0,0 +1,279 @@
+package fluentd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// encoder writes the subset of MessagePack needed by the forward protocol.
+type encoder struct {
+	buf []byte
+}
+
+// value appends a value decoded from a JSON encoded entry.
+func (e *encoder) value(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, 0xc0)
+	case bool:
+		if v {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case json.Number:
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			e.int(n)
+		} else if f, err := v.Float64(); err == nil {
+			e.float(f)
+		} else {
+			e.string(v.String())
+		}
+	case int64:
+		e.int(v)
+	case float64:
+		e.float(v)
+	case string:
+		e.string(v)
+	case []interface{}:
+		e.arrayHeader(len(v))
+		for _, item := range v {
+			e.value(item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.mapHeader(len(v))
+		for _, k := range keys {
+			e.string(k)
+			e.value(v[k])
+		}
+	default:
+		e.string(fmt.Sprint(v))
+	}
+}
+
+// int appends an integer in its shortest fixint form, or as an int64.
+func (e *encoder) int(n int64) {
+	if n >= -32 && n < 128 {
+		e.buf = append(e.buf, byte(n))
+		return
+	}
+	e.buf = append(e.buf, 0xd3)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(n))
+}
+
+// float appends a float64.
+func (e *encoder) float(f float64) {
+	e.buf = append(e.buf, 0xcb)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+// string appends a str.
+func (e *encoder) string(s string) {
+	switch n := len(s); {
+	case n < 32:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+// arrayHeader appends the header of an array of n items.
+func (e *encoder) arrayHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xdc)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdd)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+// mapHeader appends the header of a map of n pairs.
+func (e *encoder) mapHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xde)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdf)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+// eventTime appends t as a forward protocol EventTime, an ext 0 holding the seconds and
+// nanoseconds as big-endian uint32s.
+func (e *encoder) eventTime(t time.Time) {
+	e.buf = append(e.buf, 0xd7, 0x00)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Unix()))
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Nanosecond()))
+}
+
+// EventTime is a decoded forward protocol EventTime.
+type EventTime time.Time
+
+// errUnsupported is returned for MessagePack types the decoder does not handle.
+var errUnsupported = errors.New("unsupported msgpack type")
+
+// decode reads a single value: nil, bools, integers as int64, floats as float64, str and
+// bin as string, arrays, maps with string keys, and EventTimes.
+func decode(r *bufio.Reader) (interface{}, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b < 0x80:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return decodeMap(r, int(b&0x0f))
+	case b&0xf0 == 0x90:
+		return decodeArray(r, int(b&0x0f))
+	case b&0xe0 == 0xa0:
+		return decodeString(r, int(b&0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2, 0xc3:
+		return b == 0xc3, nil
+	case 0xc4, 0xd9:
+		n, err := readUint(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		return decodeString(r, int(n))
+	case 0xc5, 0xda:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return decodeString(r, int(n))
+	case 0xc6, 0xdb:
+		n, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return decodeString(r, int(n))
+	case 0xca:
+		n, err := readUint(r, 4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := readUint(r, 8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := readUint(r, 1<<(b-0xcc))
+		return int64(n), err
+	case 0xd0:
+		n, err := readUint(r, 1)
+		return int64(int8(n)), err
+	case 0xd1:
+		n, err := readUint(r, 2)
+		return int64(int16(n)), err
+	case 0xd2:
+		n, err := readUint(r, 4)
+		return int64(int32(n)), err
+	case 0xd3:
+		n, err := readUint(r, 8)
+		return int64(n), err
+	case 0xd7:
+		if typ, err := r.ReadByte(); err != nil || typ != 0 {
+			return nil, errUnsupported
+		}
+		sec, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		nsec, err := readUint(r, 4)
+		return EventTime(time.Unix(int64(sec), int64(nsec))), err
+	case 0xdc, 0xdd:
+		n, err := readUint(r, 2<<(b-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(r, int(n))
+	case 0xde, 0xdf:
+		n, err := readUint(r, 2<<(b-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return decodeMap(r, int(n))
+	}
+	return nil, fmt.Errorf("%w 0x%x", errUnsupported, b)
+}
+
+// readUint reads a big-endian unsigned integer of size bytes.
+func readUint(r io.Reader, size int) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[8-size:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// decodeString reads a string of n bytes.
+func decodeString(r io.Reader, n int) (string, error) {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+// decodeArray reads n values.
+func decodeArray(r *bufio.Reader, n int) ([]interface{}, error) {
+	out := make([]interface{}, n)
+	for i := range out {
+		v, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// decodeMap reads n pairs with string keys.
+func decodeMap(r *bufio.Reader, n int) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, n)
+	for range n {
+		k, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: non-string map key", errUnsupported)
+		}
+		if out[key], err = decode(r); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
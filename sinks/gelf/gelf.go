@@ -0,0 +1,326 @@
+// Package gelf registers a zap sink that sends entries to Graylog in the Graylog Extended Log
+// Format (GELF 1.1), so that Graylog ingests them without a translator. Importing the package
+// registers the "gelf" scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/gelf"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{
+//		"gelf://graylog.example.com:12201?proto=udp&compress=gzip",
+//	}))
+//
+// The following query parameters are supported:
+//   - proto: udp (default) or tcp.
+//   - compress: gzip (default), zlib or none. Only UDP messages can be compressed.
+//   - chunk_size: the maximum size of a UDP datagram, 1420 bytes by default. Larger messages
+//     are chunked, up to 128 chunks; larger messages are dropped.
+//   - host: the host field of the messages; the hostname by default.
+//
+// The port defaults to 12201. TCP messages are delimited by a null byte. The sink expects JSON
+// encoded entries, the default format: levels are mapped to syslog severities, the message
+// becomes short_message, the stack trace full_message, and the other fields become additional
+// fields, prefixed with an underscore. Keys are restricted to letters, digits, '_', '-' and
+// '.'; nested values are sent as JSON strings. Lines that are not JSON are sent as
+// informational messages. The sink implements logger.HealthChecker by reconnecting when the
+// connection was lost; UDP servers cannot be checked.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/record"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scheme is the output path scheme of the gelf sink.
+const Scheme = "gelf"
+
+const (
+	// defaultChunkSize is the default maximum size of a UDP datagram, which fits the MTU of
+	// most networks.
+	defaultChunkSize = 1420
+	// chunkHeaderLen is the length of a chunk's header: the magic bytes, the message ID, and
+	// the sequence number and count.
+	chunkHeaderLen = 12
+	// maxChunks is the maximum number of chunks of a message.
+	maxChunks = 128
+)
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// sink is a zap.Sink sending entries to a GELF input.
+type sink struct {
+	network   string
+	addr      string
+	compress  string
+	chunkSize int
+	host      string
+	encoder   zapcore.EncoderConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newSink creates a sink from a gelf:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	u := cfg.URL
+	q := u.Query()
+	s := &sink{
+		network:   q.Get("proto"),
+		compress:  q.Get("compress"),
+		chunkSize: defaultChunkSize,
+		host:      q.Get("host"),
+		encoder:   cfg.EncoderConfig,
+	}
+
+	switch s.network {
+	case "":
+		s.network = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("gelf: unknown proto %q", s.network)
+	}
+
+	switch s.compress {
+	case "":
+		if s.network == "udp" {
+			s.compress = "gzip"
+		}
+	case "none":
+		s.compress = ""
+	case "gzip", "zlib":
+		if s.network == "tcp" {
+			return nil, fmt.Errorf("gelf: compress=%s requires proto=udp", s.compress)
+		}
+	default:
+		return nil, fmt.Errorf("gelf: unknown compress %q", s.compress)
+	}
+
+	if size := q.Get("chunk_size"); size != "" {
+		var err error
+		if s.chunkSize, err = strconv.Atoi(size); err != nil || s.chunkSize <= chunkHeaderLen {
+			return nil, fmt.Errorf("gelf: invalid chunk_size %q", size)
+		}
+	}
+
+	if s.host == "" {
+		s.host, _ = os.Hostname()
+	}
+	if s.host == "" {
+		s.host = "unknown"
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("gelf: missing host in %q", u.String())
+	}
+	s.addr = u.Host
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), "12201")
+	}
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect dials the GELF input. s.mu must be held, or s must not be shared yet.
+func (s *sink) connect() error {
+	conn, err := net.Dial(s.network, s.addr)
+	if err != nil {
+		return fmt.Errorf("gelf: %w", err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// Write implements io.Writer. p holds one or more encoded entries, one per line; each is
+// sent as a separate GELF message.
+func (s *sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range record.Lines(p) {
+		msg, err := s.encode(s.message(line))
+		if err != nil {
+			return 0, err
+		}
+		if s.network == "tcp" {
+			err = s.send(append(msg, 0))
+		} else {
+			err = s.sendChunked(msg)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// sendChunked sends msg as a single datagram, or as chunks when it exceeds the chunk size.
+// s.mu must be held.
+func (s *sink) sendChunked(msg []byte) error {
+	if len(msg) <= s.chunkSize {
+		return s.send(msg)
+	}
+
+	size := s.chunkSize - chunkHeaderLen
+	count := (len(msg) + size - 1) / size
+	if count > maxChunks {
+		return fmt.Errorf("gelf: message of %d bytes exceeds %d chunks", len(msg), maxChunks)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("gelf: %w", err)
+	}
+	for i := 0; i < count; i++ {
+		data := msg[i*size : min((i+1)*size, len(msg))]
+		chunk := make([]byte, 0, chunkHeaderLen+len(data))
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data...)
+		if err := s.send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send writes msg, reconnecting once if the connection was lost. s.mu must be held.
+func (s *sink) send(msg []byte) error {
+	if s.conn != nil {
+		if _, err := s.conn.Write(msg); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		return fmt.Errorf("gelf: %w", err)
+	}
+	return nil
+}
+
+// encode marshals a message, compressing it when configured.
+func (s *sink) encode(msg map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("gelf: %w", err)
+	}
+
+	var (
+		b bytes.Buffer
+		w io.WriteCloser
+	)
+	switch s.compress {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "zlib":
+		w = zlib.NewWriter(&b)
+	default:
+		return data, nil
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("gelf: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("gelf: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// message formats a line as a GELF message.
+func (s *sink) message(line []byte) map[string]interface{} {
+	e := record.Parse(line, s.encoder)
+	if e.Message == "" {
+		// short_message is required.
+		e.Message = "-"
+	}
+	msg := map[string]interface{}{
+		"version":       "1.1",
+		"host":          s.host,
+		"short_message": e.Message,
+		"timestamp":     json.Number(strconv.FormatFloat(float64(e.Time.UnixMilli())/1e3, 'f', 3, 64)),
+		"level":         e.Severity,
+	}
+	if stack, ok := e.Fields[s.encoder.StacktraceKey].(string); ok && s.encoder.StacktraceKey != "" {
+		msg["full_message"] = stack
+		delete(e.Fields, s.encoder.StacktraceKey)
+	}
+	for k, v := range e.Fields {
+		msg[fieldName(k)] = fieldValue(v)
+	}
+	return msg
+}
+
+// fieldName turns a field key into the name of a GELF additional field: an underscore
+// followed by letters, digits, '_', '-' and '.'. The reserved _id field is renamed to _id_.
+func fieldName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			name[i] = '_'
+		}
+	}
+	if string(name) == "id" {
+		return "_id_"
+	}
+	return "_" + string(name)
+}
+
+// fieldValue returns the value of a GELF additional field, which must be a string or a number.
+func fieldValue(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		return n
+	}
+	return record.String(v)
+}
+
+// Health implements logger.HealthChecker by reconnecting when a write lost the connection.
+func (s *sink) Health(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+	return s.connect()
+}
+
+// Sync implements zap.Sink. Messages are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
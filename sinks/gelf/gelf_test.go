@@ -0,0 +1,153 @@
+package gelf_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	_ "github.com/janduursma/zap-logger-wrapper/v2/sinks/gelf"
+	"github.com/stretchr/testify/require"
+)
+
+// listenUDP returns the address of a UDP listener and a function receiving a datagram.
+func listenUDP(t *testing.T) (string, func() []byte) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn.LocalAddr().String(), func() []byte {
+		buf := make([]byte, 64<<10)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return buf[:n]
+	}
+}
+
+// decode decodes a GELF message.
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+// gunzip decompresses data.
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return out
+}
+
+func TestGELFUDP(t *testing.T) {
+	addr, receive := listenUDP(t)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"gelf://" + addr + "?host=web-1"}))
+	require.NoError(t, err)
+
+	l.Error(context.Background(), "payment failed", "order", 42, "id", "abc", "customer name", "Ada", "meta", map[string]int{"retries": 2})
+
+	msg := decode(t, gunzip(t, receive()))
+	require.Equal(t, "1.1", msg["version"])
+	require.Equal(t, "web-1", msg["host"])
+	require.Equal(t, "payment failed", msg["short_message"])
+	require.EqualValues(t, 3, msg["level"])
+	require.NotZero(t, msg["timestamp"])
+	require.EqualValues(t, 42, msg["_order"])
+	require.Equal(t, "abc", msg["_id_"])
+	require.Equal(t, "Ada", msg["_customer_name"])
+	require.Equal(t, `{"retries":2}`, msg["_meta"])
+	require.Equal(t, "checkout", msg["_service"])
+	require.NotContains(t, msg, "_msg")
+}
+
+func TestGELFChunking(t *testing.T) {
+	addr, receive := listenUDP(t)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"gelf://" + addr + "?compress=none&chunk_size=100"}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed", "note", string(bytes.Repeat([]byte("x"), 300)))
+
+	first := receive()
+	require.Equal(t, []byte{0x1e, 0x0f}, first[:2])
+	count := int(first[11])
+	require.Greater(t, count, 1)
+
+	chunks := map[int][]byte{int(first[10]): first[12:]}
+	for len(chunks) < count {
+		chunk := receive()
+		require.Equal(t, first[2:10], chunk[2:10], "message ID")
+		require.LessOrEqual(t, len(chunk), 100)
+		chunks[int(chunk[10])] = chunk[12:]
+	}
+	var data []byte
+	for i := 0; i < count; i++ {
+		data = append(data, chunks[i]...)
+	}
+	msg := decode(t, data)
+	require.Equal(t, "order placed", msg["short_message"])
+	require.EqualValues(t, 6, msg["level"])
+}
+
+func TestGELFTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan []byte, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		for {
+			msg, err := r.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			received <- msg[:len(msg)-1]
+		}
+	}()
+
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"gelf://" + ln.Addr().String() + "?proto=tcp"}))
+	require.NoError(t, err)
+	l.Info(context.Background(), "first")
+	l.Info(context.Background(), "second")
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case data := <-received:
+			require.Equal(t, want, decode(t, data)["short_message"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not received")
+		}
+	}
+}
+
+func TestGELFInvalidURL(t *testing.T) {
+	for _, path := range []string{
+		"gelf://localhost?proto=carrier-pigeon",
+		"gelf://localhost?proto=tcp&compress=gzip",
+		"gelf://localhost?compress=lz4",
+		"gelf://localhost?chunk_size=8",
+		"gelf://",
+	} {
+		_, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+		require.Error(t, err, path)
+	}
+}
@@ -0,0 +1,81 @@
+// Package post sends requests to the HTTP endpoints of the sinks under sinks/, retrying the
+// failures that may be temporary.
+package post
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Poster sends requests, retrying on network errors and on the status codes that signal a
+// temporary failure (429, 500, 502, 503 and 504).
+type Poster struct {
+	// Client sends the requests.
+	Client *http.Client
+	// Retries is how often a failed request is retried.
+	Retries int
+	// Backoff is the wait before the first retry, doubling for every next one.
+	Backoff time.Duration
+	// Error describes a failed response. The response status is used when it is nil.
+	Error func(resp *http.Response) error
+}
+
+// Post sends the requests created by newRequest until one succeeds with a 2xx status, a
+// failure is not temporary, or the retries are exhausted. newRequest is called for every
+// attempt, so that the body can be read again.
+func (p *Poster) Post(newRequest func() (*http.Request, error)) error {
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := p.send(newRequest)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= p.Retries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send sends a single request. It reports whether a failed request may be retried.
+func (p *Poster) send(newRequest func() (*http.Request, error)) (bool, error) {
+	req, err := newRequest()
+	if err != nil {
+		return false, err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	return temporary(resp.StatusCode), p.describe(resp)
+}
+
+// describe returns the error describing a failed response.
+func (p *Poster) describe(resp *http.Response) error {
+	if p.Error != nil {
+		if err := p.Error(resp); err != nil {
+			return err
+		}
+	}
+	return errors.New(resp.Status)
+}
+
+// temporary reports whether a request that failed with status code may succeed when retried.
+func temporary(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+// Package record decodes the encoded entries received by the sinks under sinks/, for the
+// destinations that have dedicated fields for an entry's severity, time and message.
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SeverityInfo is the syslog severity of informational messages.
+const SeverityInfo = 6
+
+// severities maps zap level names to syslog severities, which journald uses as priorities.
+var severities = map[string]int{
+	"debug":  7,
+	"info":   SeverityInfo,
+	"warn":   4,
+	"error":  3,
+	"dpanic": 2,
+	"panic":  1,
+	"fatal":  0,
+}
+
+// timeLayout is the layout of the ISO 8601 timestamps written by the logger.
+const timeLayout = "2006-01-02T15:04:05.000Z0700"
+
+// Record is a decoded entry.
+type Record struct {
+	// Severity is the syslog severity of the entry's level.
+	Severity int
+	// Time is the entry's timestamp.
+	Time time.Time
+	// Message is the entry's message.
+	Message string
+	// Fields holds the remaining fields, including the caller and the context fields.
+	// Numbers are decoded as json.Number.
+	Fields map[string]interface{}
+}
+
+// Parse decodes a JSON encoded line, using the keys of enc. Lines that are not JSON, for
+// example because the console format is used, become informational messages.
+func Parse(line []byte, enc zapcore.EncoderConfig) Record {
+	r := Record{Severity: SeverityInfo, Time: time.Now(), Message: string(line)}
+
+	fields, ok := Decode(line)
+	if !ok {
+		return r
+	}
+
+	if level, ok := fields[enc.LevelKey].(string); ok {
+		if severity, ok := severities[level]; ok {
+			r.Severity = severity
+		}
+	}
+	if t, ok := Time(fields[enc.TimeKey]); ok {
+		r.Time = t
+	}
+	r.Message, _ = fields[enc.MessageKey].(string)
+	delete(fields, enc.LevelKey)
+	delete(fields, enc.TimeKey)
+	delete(fields, enc.MessageKey)
+	r.Fields = fields
+	return r
+}
+
+// Decode decodes a JSON encoded line into its fields, with numbers as json.Number.
+// It reports whether the line is a JSON object.
+func Decode(line []byte) (map[string]interface{}, bool) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil || fields == nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// Time parses a decoded timestamp field, as written by the logger.
+func Time(v interface{}) (time.Time, bool) {
+	ts, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeLayout, ts)
+	return t, err == nil
+}
+
+// Lines splits p, as received by a sink's Write, into its non-empty lines. Writes hold
+// several entries when they are buffered.
+func Lines(p []byte) [][]byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// String formats a decoded field value: strings as is, numbers as written, and other
+// values as JSON.
+func String(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
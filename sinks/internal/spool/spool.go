@@ -0,0 +1,41 @@
+// Package spool configures the disk spool of the network sinks under sinks/ from the query
+// parameters of their output paths.
+package spool
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap"
+)
+
+// Params are the query parameters configuring the spool:
+//   - spool: the directory of the spool; writes are not spooled when omitted.
+//   - spool_max_bytes: the maximum size of the spool, 64 MB by default.
+//   - spool_retry: how often the spooled writes are replayed, 5s by default.
+var Params = []string{"spool", "spool_max_bytes", "spool_retry"}
+
+// Wrap wraps sink with logger.SpoolingSink when q sets a spool directory, and returns sink
+// otherwise.
+func Wrap(sink zap.Sink, q url.Values) (zap.Sink, error) {
+	cfg := logger.SpoolConfig{Dir: q.Get("spool")}
+	if cfg.Dir == "" {
+		return sink, nil
+	}
+	if v := q.Get("spool_max_bytes"); v != "" {
+		var err error
+		if cfg.MaxBytes, err = strconv.ParseInt(v, 10, 64); err != nil || cfg.MaxBytes <= 0 {
+			return nil, fmt.Errorf("invalid spool_max_bytes %q", v)
+		}
+	}
+	if v := q.Get("spool_retry"); v != "" {
+		var err error
+		if cfg.RetryInterval, err = time.ParseDuration(v); err != nil || cfg.RetryInterval <= 0 {
+			return nil, fmt.Errorf("invalid spool_retry %q", v)
+		}
+	}
+	return logger.SpoolingSink(sink, cfg)
+}
@@ -0,0 +1,158 @@
+// Package journald registers a zap sink that writes entries to the systemd journal with
+// native fields, for services deployed as systemd units. Importing the package registers the
+// "journald" scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/journald"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{"journald://"}))
+//
+// Entries are sent to the journal's native socket, /run/systemd/journal/socket, or to the
+// socket given as the URL's path, e.g. journald:///run/custom/socket. Instead of a JSON
+// document in MESSAGE, every entry is sent as journal fields:
+//   - MESSAGE: the entry's message.
+//   - PRIORITY: the syslog severity of the entry's level.
+//   - SYSLOG_IDENTIFIER and SERVICE: the service name passed to logger.New.
+//   - CODE_FILE and CODE_LINE: the caller, if any.
+//   - every other field, such as TRACE_ID, with its key uppercased and characters that are
+//     not allowed in journal field names replaced by underscores.
+//
+// The sink expects JSON encoded entries, the default format. Entries have to fit in a single
+// datagram of the socket.
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/record"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scheme is the output path scheme of the journald sink.
+const Scheme = "journald"
+
+// defaultSocket is the path of the journal's native protocol socket.
+const defaultSocket = "/run/systemd/journal/socket"
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// sink is a zap.Sink writing entries to the journal.
+type sink struct {
+	service string
+	encoder zapcore.EncoderConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newSink creates a sink from a journald:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	socket := cfg.URL.Path
+	if socket == "" || socket == "/" {
+		socket = defaultSocket
+	}
+
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, fmt.Errorf("journald: %w", err)
+	}
+	return &sink{service: cfg.Service, encoder: cfg.EncoderConfig, conn: conn}, nil
+}
+
+// Write implements io.Writer. p holds one or more encoded entries, one per line; each is
+// sent as a separate journal entry.
+func (s *sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range record.Lines(p) {
+		if _, err := s.conn.Write(s.message(line)); err != nil {
+			return 0, fmt.Errorf("journald: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+// Sync implements zap.Sink. Entries are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	return s.conn.Close()
+}
+
+// message encodes a line in the journal's native protocol.
+func (s *sink) message(line []byte) []byte {
+	r := record.Parse(line, s.encoder)
+
+	var b bytes.Buffer
+	writeField(&b, "MESSAGE", r.Message)
+	writeField(&b, "PRIORITY", strconv.Itoa(r.Severity))
+	if s.service != "" {
+		writeField(&b, "SYSLOG_IDENTIFIER", s.service)
+	}
+
+	if caller, ok := r.Fields[s.encoder.CallerKey].(string); ok {
+		delete(r.Fields, s.encoder.CallerKey)
+		file, line, found := strings.Cut(caller, ":")
+		writeField(&b, "CODE_FILE", file)
+		if found {
+			writeField(&b, "CODE_LINE", line)
+		}
+	}
+
+	keys := make([]string, 0, len(r.Fields))
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if name := fieldName(k); name != "" {
+			writeField(&b, name, record.String(r.Fields[k]))
+		}
+	}
+	return b.Bytes()
+}
+
+// writeField writes a field in the native protocol: NAME=value for single-line values, and
+// the name, the value's length as a little-endian uint64 and the value for others.
+func writeField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name + "=" + value + "\n")
+		return
+	}
+	b.WriteString(name + "\n")
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// fieldName turns a field key into a journal field name, which consists of uppercase
+// letters, digits and underscores, and does not start with a digit or an underscore, since
+// those are reserved for fields added by the journal itself. It returns "" if nothing is left.
+func fieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	trimmed := strings.TrimLeft(string(name), "_0123456789")
+	if len(trimmed) > 64 {
+		trimmed = trimmed[:64]
+	}
+	return trimmed
+}
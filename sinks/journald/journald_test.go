@@ -0,0 +1,87 @@
+//go:build linux
+
+package journald_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	_ "github.com/janduursma/zap-logger-wrapper/v2/sinks/journald"
+	"github.com/stretchr/testify/require"
+)
+
+// listenJournal returns the path of a fake journal socket and a function receiving the
+// fields of an entry.
+func listenJournal(t *testing.T) (string, func() map[string]string) {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return socket, func() map[string]string {
+		buf := make([]byte, 64<<10)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		return decode(t, buf[:n])
+	}
+}
+
+// decode parses a datagram of the journal's native protocol.
+func decode(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		require.GreaterOrEqual(t, nl, 0)
+		line := data[:nl]
+		data = data[nl+1:]
+
+		if name, value, ok := bytes.Cut(line, []byte("=")); ok {
+			fields[string(name)] = string(value)
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[string(line)] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournald(t *testing.T) {
+	socket, receive := listenJournal(t)
+	l, err := logger.New("checkout",
+		logger.WithOutputPaths([]string{"journald://" + socket}),
+		logger.WithTraceID(func(_ context.Context) string { return "trace-1" }),
+	)
+	require.NoError(t, err)
+
+	l.Error(context.Background(), "payment failed", "order-id", 42, "details", "line one\nline two", "_hidden", true)
+
+	fields := receive()
+	require.Equal(t, "payment failed", fields["MESSAGE"])
+	require.Equal(t, "3", fields["PRIORITY"])
+	require.Equal(t, "checkout", fields["SYSLOG_IDENTIFIER"])
+	require.Equal(t, "checkout", fields["SERVICE"])
+	require.Equal(t, "trace-1", fields["TRACE_ID"])
+	require.Equal(t, "42", fields["ORDER_ID"])
+	require.Equal(t, "line one\nline two", fields["DETAILS"])
+	require.Equal(t, "true", fields["HIDDEN"], "leading underscores are reserved for trusted fields")
+	require.Contains(t, fields["CODE_FILE"], "journald_test.go")
+	require.NotEmpty(t, fields["CODE_LINE"])
+	require.NotContains(t, fields, "LEVEL")
+}
+
+func TestJournaldMissingSocket(t *testing.T) {
+	_, err := logger.New("checkout", logger.WithOutputPaths([]string{"journald://" + filepath.Join(t.TempDir(), "missing")}))
+	require.ErrorContains(t, err, "journald")
+}
@@ -0,0 +1,4 @@
+package splunk
+
+// Internal functions exported for tests in package splunk_test.
+var NewSink = newSink
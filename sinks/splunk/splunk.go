@@ -0,0 +1,300 @@
+// Package splunk registers a zap sink that sends entries to a Splunk HTTP Event Collector
+// (HEC). Importing the package registers the "splunk" scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/splunk"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{
+//		"splunk://hec.example.com:8088?token=00000000-0000-0000-0000-000000000000&index=main",
+//	}))
+//
+// The following query parameters are supported:
+//   - token: the HEC token, required.
+//   - index, sourcetype, host: the metadata of the events; the collector's defaults are used when omitted.
+//   - source: the source of the events; the service name passed to logger.New by default.
+//   - tls: false to use HTTP instead of HTTPS.
+//   - gzip: false to send the batches uncompressed.
+//   - batch_size: the maximum size of a batch in bytes, 256 kB by default.
+//   - flush_interval: how often a pending batch is sent, 5s by default.
+//   - retries: how often a failed batch is retried, 3 by default.
+//   - backoff: the wait before the first retry, doubling for every next one, 500ms by default.
+//   - timeout: the timeout of a request, 10s by default.
+//   - spool: a directory spooling the batches that fail, which are replayed in order once the
+//     collector recovers; spool_max_bytes bounds its size, 64 MB by default, and spool_retry
+//     sets how often replaying is attempted, 5s by default. See logger.SpoolingSink.
+//
+// The port defaults to 8088 and the path to /services/collector/event. Batches are retried
+// on network errors and on the status codes that signal a temporary failure (429, 500, 502,
+// 503 and 504); other failures, such as an invalid token, are reported immediately. The sink
+// expects JSON encoded entries, the default format: the timestamp of an entry becomes the
+// time of its event and the other fields its data. Lines that are not JSON are sent as
+// string events. The sink implements logger.HealthChecker using the collector's health
+// endpoint.
+package splunk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/post"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/record"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/spool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scheme is the output path scheme of the splunk sink.
+const Scheme = "splunk"
+
+const (
+	// defaultPath is the path of the HEC event endpoint.
+	defaultPath = "/services/collector/event"
+	// healthPath is the path of the HEC health endpoint.
+	healthPath = "/services/collector/health"
+	// defaultFlushInterval is how often a pending batch is sent.
+	defaultFlushInterval = 5 * time.Second
+	// defaultRetries is how often a failed batch is retried.
+	defaultRetries = 3
+	// defaultBackoff is the wait before the first retry.
+	defaultBackoff = 500 * time.Millisecond
+	// defaultTimeout is the timeout of a request.
+	defaultTimeout = 10 * time.Second
+)
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// sink is a zap.Sink posting batches of events to a HEC endpoint.
+type sink struct {
+	endpoint string
+	health   string
+	token    string
+	gzip     bool
+	client   *http.Client
+	poster   *post.Poster
+	encoder  zapcore.EncoderConfig
+
+	// Event metadata.
+	index      string
+	sourceType string
+	source     string
+	host       string
+}
+
+// event is a HEC event.
+type event struct {
+	Time       json.Number `json:"time"`
+	Host       string      `json:"host,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// newSink creates a batching sink from a splunk:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	u := cfg.URL
+	q := u.Query()
+	s := &sink{
+		token:      q.Get("token"),
+		gzip:       true,
+		client:     &http.Client{Timeout: defaultTimeout},
+		encoder:    cfg.EncoderConfig,
+		index:      q.Get("index"),
+		sourceType: q.Get("sourcetype"),
+		source:     q.Get("source"),
+		host:       q.Get("host"),
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("splunk: missing host in %q", u.Redacted())
+	}
+	if s.token == "" {
+		return nil, errors.New("splunk: missing token")
+	}
+	if s.source == "" {
+		s.source = cfg.Service
+	}
+	s.poster = &post.Poster{Client: s.client, Retries: defaultRetries, Backoff: defaultBackoff, Error: responseError}
+
+	useTLS := true
+	for name, target := range map[string]*bool{"tls": &useTLS, "gzip": &s.gzip} {
+		if v := q.Get(name); v != "" {
+			var err error
+			if *target, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("splunk: invalid %s %q", name, v)
+			}
+		}
+	}
+
+	batchSize := 0
+	if v := q.Get("batch_size"); v != "" {
+		var err error
+		if batchSize, err = strconv.Atoi(v); err != nil || batchSize <= 0 {
+			return nil, fmt.Errorf("splunk: invalid batch_size %q", v)
+		}
+	}
+	if v := q.Get("retries"); v != "" {
+		var err error
+		if s.poster.Retries, err = strconv.Atoi(v); err != nil || s.poster.Retries < 0 {
+			return nil, fmt.Errorf("splunk: invalid retries %q", v)
+		}
+	}
+	flushInterval := defaultFlushInterval
+	for name, target := range map[string]*time.Duration{
+		"flush_interval": &flushInterval,
+		"backoff":        &s.poster.Backoff,
+		"timeout":        &s.client.Timeout,
+	} {
+		if v := q.Get(name); v != "" {
+			var err error
+			if *target, err = time.ParseDuration(v); err != nil || *target <= 0 {
+				return nil, fmt.Errorf("splunk: invalid %s %q", name, v)
+			}
+		}
+	}
+
+	scheme, host, path := "https", u.Host, u.Path
+	if !useTLS {
+		scheme = "http"
+	}
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "8088")
+	}
+	if path == "" || path == "/" {
+		path = defaultPath
+	}
+	s.endpoint = scheme + "://" + host + path
+	s.health = scheme + "://" + host + healthPath
+
+	spooled, err := spool.Wrap(s, q)
+	if err != nil {
+		return nil, fmt.Errorf("splunk: %w", err)
+	}
+	return logger.BatchingSink(spooled, batchSize, flushInterval), nil
+}
+
+// Write implements io.Writer. p holds a batch of encoded entries, one per line, which are
+// posted in a single request.
+func (s *sink) Write(p []byte) (int, error) {
+	lines := record.Lines(p)
+	if len(lines) == 0 {
+		return len(p), nil
+	}
+
+	body, err := s.body(lines)
+	if err != nil {
+		return 0, fmt.Errorf("splunk: %w", err)
+	}
+
+	err = s.poster.Post(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Splunk "+s.token)
+		req.Header.Set("Content-Type", "application/json")
+		if s.gzip {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("splunk: %w", err)
+	}
+	return len(p), nil
+}
+
+// body encodes lines as concatenated HEC events, compressed when gzip is enabled.
+func (s *sink) body(lines [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if s.gzip {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+
+	enc := json.NewEncoder(w)
+	for _, line := range lines {
+		ev := event{
+			Host:       s.host,
+			Source:     s.source,
+			SourceType: s.sourceType,
+			Index:      s.index,
+			Event:      string(line),
+		}
+		t := time.Now()
+		if fields, ok := record.Decode(line); ok {
+			if ts, ok := record.Time(fields[s.encoder.TimeKey]); ok {
+				t = ts
+				delete(fields, s.encoder.TimeKey)
+			}
+			ev.Event = fields
+		}
+		ev.Time = json.Number(strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', 3, 64))
+		if err := enc.Encode(ev); err != nil {
+			return nil, err
+		}
+	}
+
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// responseError describes a failed response, including the reason given by the collector.
+func responseError(resp *http.Response) error {
+	var reply struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&reply); err == nil && reply.Text != "" {
+		return fmt.Errorf("%s: %s (code %d)", resp.Status, reply.Text, reply.Code)
+	}
+	return errors.New(resp.Status)
+}
+
+// Health implements logger.HealthChecker using the collector's health endpoint.
+func (s *sink) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.health, nil)
+	if err != nil {
+		return fmt.Errorf("splunk: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("splunk: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("splunk: %w", responseError(resp))
+	}
+	return nil
+}
+
+// Sync implements zap.Sink. Batches are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
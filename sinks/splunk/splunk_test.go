@@ -0,0 +1,169 @@
+package splunk_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/splunk"
+	"github.com/stretchr/testify/require"
+)
+
+// collector is a fake HTTP Event Collector.
+type collector struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+	events   []map[string]interface{}
+	status   []int // Status codes of the next responses, 200 when exhausted.
+}
+
+// newCollector starts a collector answering with the given status codes, then 200.
+func newCollector(t *testing.T, status ...int) *collector {
+	t.Helper()
+
+	c := &collector{status: status}
+	c.Server = httptest.NewServer(http.HandlerFunc(c.handle))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// handle records the events of a request.
+func (c *collector) handle(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, r)
+	if len(c.status) > 0 {
+		code := c.status[0]
+		c.status = c.status[1:]
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		ev := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		c.events = append(c.events, ev)
+	}
+	_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+}
+
+// path returns an output path for the collector with the given query.
+func (c *collector) path(query string) string {
+	return "splunk://" + strings.TrimPrefix(c.URL, "http://") + "?tls=false&token=secret&" + query
+}
+
+func TestSplunk(t *testing.T) {
+	c := newCollector(t)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{c.path("index=main&sourcetype=_json")}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	l.Info(ctx, "order placed", "order_id", 42)
+	l.Error(ctx, "payment failed")
+	require.NoError(t, l.Sync())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.requests, 1, "entries should be sent in a single batch")
+	require.Equal(t, "Splunk secret", c.requests[0].Header.Get("Authorization"))
+	require.Equal(t, "/services/collector/event", c.requests[0].URL.Path)
+	require.Equal(t, "gzip", c.requests[0].Header.Get("Content-Encoding"))
+
+	require.Len(t, c.events, 2)
+	ev := c.events[0]
+	require.Equal(t, "main", ev["index"])
+	require.Equal(t, "_json", ev["sourcetype"])
+	require.Equal(t, "checkout", ev["source"], "the source should default to the service")
+	require.IsType(t, float64(0), ev["time"])
+	data, ok := ev["event"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "order placed", data["msg"])
+	require.Equal(t, "info", data["level"])
+	require.EqualValues(t, 42, data["order_id"])
+	require.NotContains(t, data, "ts", "the timestamp should become the event time")
+}
+
+func TestSplunkRetry(t *testing.T) {
+	c := newCollector(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{c.path("backoff=1ms&gzip=false")}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.NoError(t, l.Sync())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.requests, 3, "temporary failures should be retried")
+	require.Len(t, c.events, 1)
+}
+
+func TestSplunkPermanentFailure(t *testing.T) {
+	c := newCollector(t, http.StatusForbidden)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{c.path("backoff=1ms")}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	err = l.Sync()
+	require.ErrorContains(t, err, "Invalid token")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.requests, 1, "permanent failures should not be retried")
+}
+
+func TestSplunkHealth(t *testing.T) {
+	c := newCollector(t)
+	u, err := url.Parse(c.path(""))
+	require.NoError(t, err)
+
+	sink, err := splunk.NewSink(logger.SinkConfig{URL: u, Service: "checkout"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sink.Close() })
+
+	hc, ok := sink.(logger.HealthChecker)
+	require.True(t, ok, "the sink should report its health")
+	require.NoError(t, hc.Health(context.Background()))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Equal(t, "/services/collector/health", c.requests[0].URL.Path)
+}
+
+func TestSplunkInvalidURL(t *testing.T) {
+	for _, path := range []string{
+		"splunk://hec.example.com:8088",
+		"splunk://?token=secret",
+		"splunk://hec.example.com?token=secret&retries=-1",
+		"splunk://hec.example.com?token=secret&gzip=maybe",
+		"splunk://hec.example.com?token=secret&flush_interval=0s",
+	} {
+		_, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+		require.Error(t, err, path)
+	}
+}
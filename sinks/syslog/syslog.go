@@ -0,0 +1,307 @@
+// Package syslog registers a zap sink that sends entries to a syslog server, so appliances
+// and legacy SIEMs can ingest the logs directly. Importing the package registers the
+// "syslog" scheme for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/syslog"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{
+//		"syslog://logs.example.com:514?proto=udp&format=rfc5424",
+//	}))
+//
+// The following query parameters are supported:
+//   - proto: udp (default), tcp or tls.
+//   - format: rfc5424 (default) or rfc3164.
+//   - facility: the syslog facility name, user by default, e.g. local0.
+//   - app: the APP-NAME or TAG; the service name passed to logger.New by default.
+//
+// The port defaults to 514, or 6514 for tls. The sink expects JSON encoded entries, the
+// default format: levels are mapped to syslog severities and the fields other than the level,
+// timestamp and message are sent as RFC 5424 structured data, or appended as JSON to the
+// message for RFC 3164. Lines that are not JSON are sent as informational messages. The sink
+// implements logger.HealthChecker by reconnecting when the connection was lost; UDP servers
+// cannot be checked.
+package syslog
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/record"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scheme is the output path scheme of the syslog sink.
+const Scheme = "syslog"
+
+func init() {
+	if err := logger.RegisterSinkFactory(Scheme, newSink); err != nil {
+		panic(err)
+	}
+}
+
+// Format selects the syslog message format.
+type Format string
+
+const (
+	// RFC5424 is the structured syslog protocol. This is the default.
+	RFC5424 Format = "rfc5424"
+	// RFC3164 is the legacy BSD syslog format.
+	RFC3164 Format = "rfc3164"
+)
+
+// sdID is the SD-ID of the structured data element holding the fields. 32473 is the
+// private enterprise number reserved for documentation (RFC 5612).
+const sdID = "fields@32473"
+
+// facilities maps syslog facility names to their codes.
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// sink is a zap.Sink writing entries to a syslog server.
+type sink struct {
+	network  string
+	addr     string
+	format   Format
+	facility int
+	app      string
+	hostname string
+	pid      int
+	encoder  zapcore.EncoderConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newSink creates a sink from a syslog:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	u := cfg.URL
+	q := u.Query()
+	s := &sink{
+		network:  q.Get("proto"),
+		format:   Format(q.Get("format")),
+		facility: facilities["user"],
+		app:      q.Get("app"),
+		pid:      os.Getpid(),
+		encoder:  cfg.EncoderConfig,
+	}
+	if s.app == "" {
+		s.app = cfg.Service
+	}
+
+	if s.network == "" {
+		s.network = "udp"
+	}
+	port := "514"
+	switch s.network {
+	case "udp", "tcp":
+	case "tls":
+		port = "6514"
+	default:
+		return nil, fmt.Errorf("syslog: unknown proto %q", s.network)
+	}
+
+	switch s.format {
+	case "":
+		s.format = RFC5424
+	case RFC5424, RFC3164:
+	default:
+		return nil, fmt.Errorf("syslog: unknown format %q", s.format)
+	}
+
+	if name := q.Get("facility"); name != "" {
+		code, ok := facilities[name]
+		if !ok {
+			return nil, fmt.Errorf("syslog: unknown facility %q", name)
+		}
+		s.facility = code
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("syslog: missing host in %q", u.String())
+	}
+	s.addr = u.Host
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	s.hostname, _ = os.Hostname()
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect dials the syslog server. s.mu must be held, or s must not be shared yet.
+func (s *sink) connect() error {
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.network == "tls" {
+		conn, err = tls.Dial("tcp", s.addr, nil)
+	} else {
+		conn, err = net.Dial(s.network, s.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// Write implements io.Writer. p holds one or more encoded entries, one per line; each is
+// sent as a separate syslog message.
+func (s *sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range record.Lines(p) {
+		if err := s.send(s.frame(s.message(line))); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// send writes a message, reconnecting once if the connection was lost. s.mu must be held.
+func (s *sink) send(msg []byte) error {
+	if s.conn != nil {
+		if _, err := s.conn.Write(msg); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// frame applies the framing required by the transport: stream transports use octet
+// counting (RFC 6587) for RFC 5424 and a trailing newline for RFC 3164.
+func (s *sink) frame(msg []byte) []byte {
+	if s.network == "udp" {
+		return msg
+	}
+	if s.format == RFC3164 {
+		return append(msg, '\n')
+	}
+	return append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+}
+
+// Health implements logger.HealthChecker by reconnecting when a write lost the connection.
+func (s *sink) Health(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+	return s.connect()
+}
+
+// Sync implements zap.Sink. Messages are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// message formats a line as a syslog message.
+func (s *sink) message(line []byte) []byte {
+	e := record.Parse(line, s.encoder)
+	pri := s.facility*8 + e.Severity
+	app := s.app
+	if app == "" {
+		app = "-"
+	}
+
+	var b bytes.Buffer
+	if s.format == RFC3164 {
+		fmt.Fprintf(&b, "<%d>%s %s %s[%d]: %s", pri, e.Time.Format(time.Stamp), s.hostname, app, s.pid, e.Message)
+		if len(e.Fields) > 0 {
+			data, _ := json.Marshal(e.Fields)
+			b.WriteByte(' ')
+			b.Write(data)
+		}
+		return b.Bytes()
+	}
+
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d - ", pri, e.Time.Format("2006-01-02T15:04:05.000Z07:00"), s.hostname, app, s.pid)
+	writeStructuredData(&b, e.Fields)
+	if e.Message != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.Message)
+	}
+	return b.Bytes()
+}
+
+// writeStructuredData writes fields as an RFC 5424 SD-ELEMENT, or the NILVALUE without fields.
+func writeStructuredData(b *bytes.Buffer, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		b.WriteByte('-')
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("[" + sdID)
+	for _, k := range keys {
+		fmt.Fprintf(b, ` %s="%s"`, paramName(k), paramValueEscaper.Replace(record.String(fields[k])))
+	}
+	b.WriteByte(']')
+}
+
+// paramName turns a field key into a valid SD-NAME: at most 32 printable US-ASCII
+// characters, except '=', ' ', ']' and '"'.
+func paramName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return string(name)
+}
+
+// paramValueEscaper escapes the characters RFC 5424 requires to be escaped in a PARAM-VALUE.
+var paramValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
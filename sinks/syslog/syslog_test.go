@@ -0,0 +1,115 @@
+package syslog_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	_ "github.com/janduursma/zap-logger-wrapper/v2/sinks/syslog"
+	"github.com/stretchr/testify/require"
+)
+
+// listenUDP returns the address of a UDP listener and a function receiving a message.
+func listenUDP(t *testing.T) (string, func() string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn.LocalAddr().String(), func() string {
+		buf := make([]byte, 64<<10)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+}
+
+func TestSyslogRFC5424(t *testing.T) {
+	addr, receive := listenUDP(t)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"syslog://" + addr + "?format=rfc5424&facility=local0"}))
+	require.NoError(t, err)
+
+	l.Error(context.Background(), "payment failed", "order", 42, "note", `a "quoted" ] value`)
+
+	msg := receive()
+	// local0 (16) * 8 + err (3) = 131.
+	require.Regexp(t, regexp.MustCompile(`^<131>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}\S+ \S+ checkout \d+ - \[fields@32473 `), msg)
+	require.Contains(t, msg, ` order="42"`)
+	require.Contains(t, msg, ` note="a \"quoted\" \] value"`)
+	require.Contains(t, msg, ` service="checkout"`)
+	require.True(t, strings.HasSuffix(msg, "] payment failed"), msg)
+}
+
+func TestSyslogRFC3164(t *testing.T) {
+	addr, receive := listenUDP(t)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"syslog://" + addr + "?format=rfc3164&app=shop"}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed", "order", 42)
+
+	msg := receive()
+	// user (1) * 8 + info (6) = 14.
+	require.Regexp(t, regexp.MustCompile(`^<14>[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d \S+ shop\[\d+\]: order placed \{.*"order":42`), msg)
+}
+
+func TestSyslogTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		for {
+			var n int
+			if _, err := fmt.Fscanf(r, "%d ", &n); err != nil {
+				return
+			}
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{"syslog://" + ln.Addr().String() + "?proto=tcp"}))
+	require.NoError(t, err)
+	l.Info(context.Background(), "first")
+	l.Info(context.Background(), "second")
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case msg := <-received:
+			require.True(t, strings.HasSuffix(msg, "] "+want), msg)
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not received")
+		}
+	}
+}
+
+func TestSyslogInvalidURL(t *testing.T) {
+	for _, path := range []string{
+		"syslog://localhost?proto=carrier-pigeon",
+		"syslog://localhost?format=json",
+		"syslog://localhost?facility=nowhere",
+		"syslog://",
+	} {
+		_, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+		require.Error(t, err, path)
+	}
+}
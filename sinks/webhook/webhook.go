@@ -0,0 +1,235 @@
+// Package webhook registers a zap sink that posts batches of entries as NDJSON to an HTTP
+// endpoint, such as an in-house collector. Importing the package registers the "http" and
+// "https" schemes for output paths:
+//
+//	import _ "github.com/janduursma/zap-logger-wrapper/v2/sinks/webhook"
+//
+//	log, err := logger.New("myServiceName", logger.WithOutputPaths([]string{
+//		"https://collector.internal/ingest?bearer=s3cr3t&gzip=true&header=X-Team:%20payments",
+//	}))
+//
+// The following query parameters configure the sink and are removed from the endpoint's URL;
+// all others are kept:
+//   - header: a header to send, as "Name: value"; it may be repeated.
+//   - bearer: a token sent as "Authorization: Bearer <token>". Credentials in the URL's user
+//     information are sent using basic authentication instead.
+//   - gzip: true to compress the batches.
+//   - batch_size: the maximum size of a batch in bytes, 256 kB by default.
+//   - flush_interval: how often a pending batch is sent, 5s by default.
+//   - retries: how often a failed batch is retried, 3 by default.
+//   - backoff: the wait before the first retry, doubling for every next one, 500ms by default.
+//   - timeout: the timeout of a request, 10s by default.
+//   - spool: a directory spooling the batches that fail, which are replayed in order once the
+//     endpoint recovers; spool_max_bytes bounds its size, 64 MB by default, and spool_retry
+//     sets how often replaying is attempted, 5s by default. See logger.SpoolingSink.
+//
+// Every batch is a POST request with the encoded entries, one per line, as its body. Batches
+// are retried on network errors and on the status codes that signal a temporary failure (429,
+// 500, 502, 503 and 504). The sink expects JSON encoded entries, the default format. The sink
+// implements logger.HealthChecker by posting an empty batch, which checks that the endpoint
+// is reachable and accepts the credentials.
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/post"
+	"github.com/janduursma/zap-logger-wrapper/v2/sinks/internal/spool"
+	"go.uber.org/zap"
+)
+
+// Schemes are the output path schemes of the webhook sink.
+var Schemes = []string{"http", "https"}
+
+const (
+	// defaultFlushInterval is how often a pending batch is sent.
+	defaultFlushInterval = 5 * time.Second
+	// defaultRetries is how often a failed batch is retried.
+	defaultRetries = 3
+	// defaultBackoff is the wait before the first retry.
+	defaultBackoff = 500 * time.Millisecond
+	// defaultTimeout is the timeout of a request.
+	defaultTimeout = 10 * time.Second
+)
+
+func init() {
+	for _, scheme := range Schemes {
+		if err := logger.RegisterSinkFactory(scheme, newSink); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// sink is a zap.Sink posting batches of entries to an endpoint.
+type sink struct {
+	endpoint string
+	header   http.Header
+	gzip     bool
+	client   *http.Client
+	poster   *post.Poster
+}
+
+// newSink creates a batching sink from an http:// or https:// URL.
+func newSink(cfg logger.SinkConfig) (zap.Sink, error) {
+	u := *cfg.URL
+	if u.Host == "" {
+		return nil, fmt.Errorf("webhook: missing host in %q", u.Redacted())
+	}
+
+	q := u.Query()
+	s := &sink{
+		header: http.Header{"Content-Type": {"application/x-ndjson"}},
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+	s.poster = &post.Poster{Client: s.client, Retries: defaultRetries, Backoff: defaultBackoff}
+
+	for _, h := range q["header"] {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("webhook: invalid header %q", h)
+		}
+		s.header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	if token := q.Get("bearer"); token != "" {
+		s.header.Set("Authorization", "Bearer "+token)
+	}
+	if u.User != nil {
+		req := &http.Request{Header: http.Header{}}
+		password, _ := u.User.Password()
+		req.SetBasicAuth(u.User.Username(), password)
+		s.header.Set("Authorization", req.Header.Get("Authorization"))
+		u.User = nil
+	}
+
+	if v := q.Get("gzip"); v != "" {
+		var err error
+		if s.gzip, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("webhook: invalid gzip %q", v)
+		}
+	}
+	if s.gzip {
+		s.header.Set("Content-Encoding", "gzip")
+	}
+
+	batchSize := 0
+	if v := q.Get("batch_size"); v != "" {
+		var err error
+		if batchSize, err = strconv.Atoi(v); err != nil || batchSize <= 0 {
+			return nil, fmt.Errorf("webhook: invalid batch_size %q", v)
+		}
+	}
+	if v := q.Get("retries"); v != "" {
+		var err error
+		if s.poster.Retries, err = strconv.Atoi(v); err != nil || s.poster.Retries < 0 {
+			return nil, fmt.Errorf("webhook: invalid retries %q", v)
+		}
+	}
+	flushInterval := defaultFlushInterval
+	for name, target := range map[string]*time.Duration{
+		"flush_interval": &flushInterval,
+		"backoff":        &s.poster.Backoff,
+		"timeout":        &s.client.Timeout,
+	} {
+		if v := q.Get(name); v != "" {
+			var err error
+			if *target, err = time.ParseDuration(v); err != nil || *target <= 0 {
+				return nil, fmt.Errorf("webhook: invalid %s %q", name, v)
+			}
+		}
+	}
+
+	spooled, err := spool.Wrap(s, q)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+
+	for _, name := range append([]string{"header", "bearer", "gzip", "batch_size", "flush_interval", "retries", "backoff", "timeout"}, spool.Params...) {
+		q.Del(name)
+	}
+	u.RawQuery = q.Encode()
+	s.endpoint = u.String()
+
+	return logger.BatchingSink(spooled, batchSize, flushInterval), nil
+}
+
+// Write implements io.Writer. p holds a batch of encoded entries, one per line, which are
+// posted in a single request.
+func (s *sink) Write(p []byte) (int, error) {
+	if len(bytes.TrimSpace(p)) == 0 {
+		return len(p), nil
+	}
+
+	body, err := s.body(p)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+
+	err = s.poster.Post(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header = s.header.Clone()
+		return req, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+	return len(p), nil
+}
+
+// body returns p, compressed when gzip is enabled.
+func (s *sink) body(p []byte) ([]byte, error) {
+	if !s.gzip {
+		return p, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(p); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Health implements logger.HealthChecker by posting an empty batch, without retries.
+func (s *sink) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header = s.header.Clone()
+	req.Header.Del("Content-Encoding")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// Sync implements zap.Sink. Batches are sent as they are written.
+func (s *sink) Sync() error {
+	return nil
+}
+
+// Close implements zap.Sink.
+func (s *sink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
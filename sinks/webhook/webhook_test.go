@@ -0,0 +1,189 @@
+package webhook_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	_ "github.com/janduursma/zap-logger-wrapper/v2/sinks/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+// endpoint is a fake collector recording the batches it receives.
+type endpoint struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+	batches  [][]map[string]interface{}
+	status   []int // Status codes of the next responses, 200 when exhausted.
+}
+
+// newEndpoint starts an endpoint answering with the given status codes, then 200.
+func newEndpoint(t *testing.T, status ...int) *endpoint {
+	t.Helper()
+
+	e := &endpoint{status: status}
+	e.Server = httptest.NewServer(http.HandlerFunc(e.handle))
+	t.Cleanup(e.Close)
+	return e
+}
+
+// handle records the entries of a request.
+func (e *endpoint) handle(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests = append(e.requests, r)
+	if len(e.status) > 0 {
+		w.WriteHeader(e.status[0])
+		e.status = e.status[1:]
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	var batch []map[string]interface{}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batch = append(batch, entry)
+	}
+	e.batches = append(e.batches, batch)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestWebhook(t *testing.T) {
+	e := newEndpoint(t)
+	path := e.URL + "/ingest?source=app&bearer=s3cr3t&gzip=true&header=X-Team:%20payments"
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	l.Info(ctx, "order placed", "order_id", 42)
+	l.Error(ctx, "payment failed")
+	require.NoError(t, l.Sync())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(t, e.requests, 1, "entries should be sent in a single batch")
+	req := e.requests[0]
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "/ingest", req.URL.Path)
+	require.Equal(t, "source=app", req.URL.RawQuery, "only the sink's parameters should be removed")
+	require.Equal(t, "Bearer s3cr3t", req.Header.Get("Authorization"))
+	require.Equal(t, "payments", req.Header.Get("X-Team"))
+	require.Equal(t, "application/x-ndjson", req.Header.Get("Content-Type"))
+
+	require.Len(t, e.batches[0], 2)
+	require.Equal(t, "order placed", e.batches[0][0]["msg"])
+	require.Equal(t, "payment failed", e.batches[0][1]["msg"])
+}
+
+func TestWebhookBasicAuth(t *testing.T) {
+	e := newEndpoint(t)
+	path := strings.Replace(e.URL, "http://", "http://user:pass@", 1)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.NoError(t, l.Sync())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	user, pass, ok := e.requests[0].BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", user)
+	require.Equal(t, "pass", pass)
+}
+
+func TestWebhookRetry(t *testing.T) {
+	e := newEndpoint(t, http.StatusBadGateway)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{e.URL + "?backoff=1ms"}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.NoError(t, l.Sync())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(t, e.requests, 2, "temporary failures should be retried")
+	require.Len(t, e.batches, 1)
+}
+
+func TestWebhookPermanentFailure(t *testing.T) {
+	e := newEndpoint(t, http.StatusUnauthorized)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{e.URL + "?backoff=1ms"}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.ErrorContains(t, l.Sync(), "401")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(t, e.requests, 1, "permanent failures should not be retried")
+}
+
+func TestWebhookSpool(t *testing.T) {
+	e := newEndpoint(t, http.StatusUnauthorized)
+	path := e.URL + "?spool=" + url.QueryEscape(t.TempDir()) + "&spool_retry=1h"
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "order placed")
+	require.NoError(t, l.Sync(), "the failed batch should be spooled and replayed")
+	require.Equal(t, logger.SpoolStats{}, l.SpoolStats()[path])
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(t, e.requests, 2)
+	require.Empty(t, e.requests[1].URL.RawQuery, "the spool parameters should be removed")
+	require.Len(t, e.batches, 1)
+}
+
+func TestWebhookHealth(t *testing.T) {
+	e := newEndpoint(t, http.StatusUnauthorized)
+	l, err := logger.New("checkout", logger.WithOutputPaths([]string{e.URL}))
+	require.NoError(t, err)
+
+	require.ErrorContains(t, l.Ping(context.Background()), "401")
+	require.NoError(t, l.Ping(context.Background()))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	require.Len(t, e.requests, 2)
+	require.Empty(t, e.batches[0], "the check should post an empty batch")
+}
+
+func TestWebhookInvalidURL(t *testing.T) {
+	for _, path := range []string{
+		"https:///ingest",
+		"https://collector.internal?header=invalid",
+		"https://collector.internal?gzip=maybe",
+		"https://collector.internal?timeout=soon",
+		"https://collector.internal?spool=/tmp/spool&spool_max_bytes=lots",
+	} {
+		_, err := logger.New("checkout", logger.WithOutputPaths([]string{path}))
+		require.Error(t, err, path)
+	}
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Snapshot is an immutable capture of a Logger's fields together with the fields derived
+// from a context, such as the trace ID. It can be stored and used to log later, for example
+// from an asynchronous callback that runs after the original context is gone, while keeping
+// the original correlation data.
+//
+// A Snapshot must be obtained through Logger.Snapshot; the zero value is not usable.
+type Snapshot struct {
+	logger *Logger
+}
+
+// Snapshot captures the Logger's current fields and the fields derived from ctx, as well as the
+// level set through ContextWithMinLevel. The context is only consulted when the snapshot is
+// taken.
+func (l *Logger) Snapshot(ctx context.Context) Snapshot {
+	fields := l.contextFields(ctx)
+	if minLevel, ok := MinLevelFromContext(ctx); ok {
+		l = l.withLevel(minLevel)
+	}
+
+	// Snapshot methods call write directly, so there is one wrapper frame less to skip.
+	child := *l
+	child.zapLogger = l.zapLogger.With(fields...).WithOptions(zap.AddCallerSkip(-1))
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	return Snapshot{logger: &child}
+}
+
+// Info logs a message at InfoLevel using the captured fields.
+func (s Snapshot) Info(msg string, keyVals ...interface{}) {
+	s.logger.write(zapcore.InfoLevel, msg, s.logger.groupKeyVals(keyVals))
+}
+
+// Error logs a message at ErrorLevel using the captured fields.
+func (s Snapshot) Error(msg string, keyVals ...interface{}) {
+	s.logger.write(zapcore.ErrorLevel, msg, s.logger.groupKeyVals(keyVals))
+}
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type traceKey struct{}
+
+// traceFromContext is a GetTraceIDFn reading the trace ID stored under traceKey.
+func traceFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceKey{}).(string)
+	return id
+}
+
+func TestSnapshot(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext), logger.WithLevel(zap.DebugLevel))
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "req-1"))
+	snap := l.With("component", "async").Snapshot(ctx)
+	cancel()
+
+	snap.Info("callback finished", "attempt", 2)
+	snap.Error("callback failed")
+	snap.Debug("callback details")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		require.Equal(t, "req-1", entry["trace_id"], "captured trace ID should be preserved")
+		require.Equal(t, "async", entry["component"], "captured fields should be preserved")
+		require.Contains(t, entry["caller"], "snapshot_test.go", "caller should point at the call site")
+	}
+	require.Equal(t, float64(2), entries[0]["attempt"])
+}
+
+func TestSnapshotIsImmutable(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext))
+
+	parent := l.With("component", "async")
+	snap := parent.Snapshot(context.WithValue(context.Background(), traceKey{}, "req-1"))
+	_ = parent.With("extra", true)
+
+	snap.Info("later")
+
+	require.NotContains(t, sink.logs.String(), "extra")
+}
+
+func TestSnapshotMinLevel(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t, logger.WithLevel(zap.InfoLevel))
+
+	l.Snapshot(logger.ContextWithMinLevel(context.Background(), zap.DebugLevel)).Debug("debugging this request")
+	l.Snapshot(logger.ContextWithMinLevel(context.Background(), zap.ErrorLevel)).Info("quiet request")
+	l.Snapshot(context.Background()).Debug("other request")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1, "the snapshot should keep the level of its context")
+	require.Equal(t, "debugging this request", entries[0]["msg"])
+}
@@ -0,0 +1,371 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSpoolMaxBytes bounds the size of a spool unless SpoolConfig.MaxBytes is set.
+	defaultSpoolMaxBytes = 64 << 20
+	// defaultSpoolRetryInterval is how often spooled entries are replayed unless
+	// SpoolConfig.RetryInterval is set.
+	defaultSpoolRetryInterval = 5 * time.Second
+	// spoolFile and spoolHeadFile name the spooled writes and the offset of the oldest one.
+	spoolFile     = "spool"
+	spoolHeadFile = "spool.head"
+	// spoolCompactFile names the copy of the spooled writes replacing the spool on compaction.
+	spoolCompactFile = "spool.compact"
+	// spoolHeaderLen is the length of a spooled write's header: its time in Unix nanoseconds
+	// and the length of its data.
+	spoolHeaderLen = 12
+)
+
+// SpoolConfig configures SpoolingSink.
+type SpoolConfig struct {
+	// Dir is the directory holding the spool, created when missing. It must not be shared
+	// with other sinks.
+	Dir string
+	// MaxBytes bounds the size of the spooled writes; writes that do not fit are dropped. The
+	// replayed writes are removed from the spool file once they take half of MaxBytes, so the
+	// file takes up to 1.5 times MaxBytes on disk. A non-positive value selects a default of 64 MB.
+	MaxBytes int64
+	// RetryInterval is how often the spooled writes are replayed while the spool is not
+	// empty. A non-positive value selects a default of 5 seconds.
+	RetryInterval time.Duration
+}
+
+// SpoolStats describes the writes held by a spool.
+type SpoolStats struct {
+	// Entries is the number of spooled writes. A write holds several entries when it is a batch.
+	Entries int
+	// Bytes is the size of the spool.
+	Bytes int64
+	// OldestAge is how long ago the oldest spooled write was spooled, zero when the spool is empty.
+	OldestAge time.Duration
+	// Dropped counts the writes dropped because the spool was full.
+	Dropped uint64
+}
+
+// Spooler is implemented by sinks spooling writes to disk, see SpoolingSink.
+type Spooler interface {
+	SpoolStats() SpoolStats
+}
+
+// SpoolStats returns the stats of the spools of the output paths, keyed by output path.
+// Only the sinks created through a SinkFactory that returns a SpoolingSink, possibly wrapped
+// by BatchingSink, have a spool.
+func (l *Logger) SpoolStats() map[string]SpoolStats {
+	stats := make(map[string]SpoolStats)
+	for path, sink := range l.sinks {
+		if s, ok := findSpooler(sink); ok {
+			stats[path] = s.SpoolStats()
+		}
+	}
+	return stats
+}
+
+// findSpooler returns the Spooler among sink and the sinks it wraps, if any.
+func findSpooler(sink zap.Sink) (Spooler, bool) {
+	for sink != nil {
+		if s, ok := sink.(Spooler); ok {
+			return s, true
+		}
+		w, ok := sink.(interface{ Unwrap() zap.Sink })
+		if !ok {
+			break
+		}
+		sink = w.Unwrap()
+	}
+	return nil, false
+}
+
+// SpoolingSink wraps sink, typically writing to a remote destination, so that the writes it
+// fails are appended to a bounded queue on disk instead of being lost. The spooled writes are
+// replayed in order every cfg.RetryInterval and on Sync, until the sink accepts them again;
+// in the meantime, new writes are spooled behind them. Writes spooled by an earlier process
+// are replayed too. A SinkFactory can return it, wrapped by BatchingSink to spool batches.
+func SpoolingSink(sink zap.Sink, cfg SpoolConfig) (zap.Sink, error) {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultSpoolMaxBytes
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultSpoolRetryInterval
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(cfg.Dir, spoolFile), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+
+	s := &spoolingSink{
+		sink: sink,
+		cfg:  cfg,
+		file: file,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+	go s.run()
+	return s, nil
+}
+
+// spoolingSink is a zap.Sink spooling the writes its sink fails to disk.
+type spoolingSink struct {
+	sink zap.Sink
+	cfg  SpoolConfig
+	quit chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	file    *os.File
+	head    int64         // Offset of the oldest spooled write.
+	tail    int64         // Offset after the newest spooled write.
+	queued  []spooledItem // Spooled writes, oldest first.
+	dropped uint64
+}
+
+// spooledItem describes a spooled write.
+type spooledItem struct {
+	at   time.Time
+	size int64 // Including the header.
+}
+
+// load restores the writes spooled by an earlier process, dropping a partially written one.
+func (s *spoolingSink) load() error {
+	head, err := os.ReadFile(filepath.Join(s.cfg.Dir, spoolHeadFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	// A head beyond the end of the spool was saved before the spool was truncated.
+	if len(head) == 8 && int64(binary.BigEndian.Uint64(head)) <= info.Size() {
+		s.head = int64(binary.BigEndian.Uint64(head))
+	}
+	s.tail = s.head
+
+	header := make([]byte, spoolHeaderLen)
+	for {
+		if _, err := s.file.ReadAt(header, s.tail); err != nil {
+			break
+		}
+		size := spoolHeaderLen + int64(binary.BigEndian.Uint32(header[8:]))
+		if _, err := s.file.ReadAt(make([]byte, 1), s.tail+size-1); err != nil {
+			break
+		}
+		at := time.Unix(0, int64(binary.BigEndian.Uint64(header)))
+		s.queued = append(s.queued, spooledItem{at: at, size: size})
+		s.tail += size
+	}
+	return s.file.Truncate(s.tail)
+}
+
+// run replays the spooled writes every retry interval until the sink is closed.
+func (s *spoolingSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			_ = s.replay()
+			s.mu.Unlock()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Write implements io.Writer. p is written to the sink unless writes are spooled already,
+// and spooled when the sink fails it. An error is only returned when the spool is full.
+func (s *spoolingSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queued) == 0 {
+		if _, err := s.sink.Write(p); err == nil {
+			return len(p), nil
+		}
+	}
+	if err := s.spool(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// spool appends p to the spool.
+func (s *spoolingSink) spool(p []byte) error {
+	size := spoolHeaderLen + int64(len(p))
+	if s.tail-s.head+size > s.cfg.MaxBytes {
+		s.dropped++
+		return fmt.Errorf("spool: full, dropped %d bytes", len(p))
+	}
+
+	now := time.Now()
+	buf := make([]byte, size)
+	binary.BigEndian.PutUint64(buf, uint64(now.UnixNano()))
+	binary.BigEndian.PutUint32(buf[8:], uint32(len(p)))
+	copy(buf[spoolHeaderLen:], p)
+	if _, err := s.file.WriteAt(buf, s.tail); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	s.tail += size
+	s.queued = append(s.queued, spooledItem{at: now, size: size})
+	return nil
+}
+
+// replay writes the spooled writes to the sink, oldest first, until the sink fails one.
+// Writes are blocked meanwhile, so that they stay in order. The spool is truncated once
+// it is empty, and compacted once the replayed writes take half of MaxBytes.
+func (s *spoolingSink) replay() error {
+	if err := s.replayQueued(); err != nil {
+		if s.head > s.cfg.MaxBytes/2 {
+			return errors.Join(err, s.compact())
+		}
+		return err
+	}
+
+	if s.head == 0 {
+		return nil
+	}
+	s.head, s.tail = 0, 0
+	if err := s.file.Truncate(0); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	return s.saveHead()
+}
+
+// replayQueued writes the spooled writes to the sink, oldest first, until the sink fails one.
+func (s *spoolingSink) replayQueued() error {
+	for len(s.queued) > 0 {
+		item := s.queued[0]
+		buf := make([]byte, item.size)
+		if _, err := s.file.ReadAt(buf, s.head); err != nil {
+			return fmt.Errorf("spool: %w", err)
+		}
+		if _, err := s.sink.Write(buf[spoolHeaderLen:]); err != nil {
+			return err
+		}
+		s.head += item.size
+		s.queued = s.queued[1:]
+		if err := s.saveHead(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compact removes the replayed writes from the spool by replacing it with a copy of the
+// spooled writes. The head is reset before the copy replaces the spool, so that a crash in
+// between replays writes again rather than skipping spooled ones.
+func (s *spoolingSink) compact() error {
+	path := filepath.Join(s.cfg.Dir, spoolCompactFile)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	_, err = io.Copy(file, io.NewSectionReader(s.file, s.head, s.tail-s.head))
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("spool: %w", err)
+	}
+
+	head := s.head
+	s.head = 0
+	if err := s.saveHead(); err != nil {
+		s.head = head
+		_ = file.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	if err := os.Rename(path, filepath.Join(s.cfg.Dir, spoolFile)); err != nil {
+		s.head = head
+		_ = file.Close()
+		_ = os.Remove(path)
+		return errors.Join(fmt.Errorf("spool: %w", err), s.saveHead())
+	}
+	_ = s.file.Close()
+	s.file = file
+	s.tail -= head
+	return nil
+}
+
+// saveHead persists the offset of the oldest spooled write, so that a later process does
+// not replay the writes replayed already.
+func (s *spoolingSink) saveHead() error {
+	head := make([]byte, 8)
+	binary.BigEndian.PutUint64(head, uint64(s.head))
+	if err := os.WriteFile(filepath.Join(s.cfg.Dir, spoolHeadFile), head, 0o600); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	return nil
+}
+
+// Sync implements zap.Sink. It replays the spooled writes and syncs the spool and the sink.
+// Writes that cannot be replayed yet are not an error, since they are safe on disk.
+func (s *spoolingSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.replay()
+	return errors.Join(s.file.Sync(), s.sink.Sync())
+}
+
+// Close implements zap.Sink. The writes that are still spooled are replayed by the next
+// SpoolingSink using the same directory.
+func (s *spoolingSink) Close() error {
+	close(s.quit)
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.file.Close(), s.sink.Close())
+}
+
+// SpoolStats implements Spooler.
+func (s *spoolingSink) SpoolStats() SpoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := SpoolStats{Entries: len(s.queued), Bytes: s.tail - s.head, Dropped: s.dropped}
+	if len(s.queued) > 0 {
+		stats.OldestAge = time.Since(s.queued[0].at)
+	}
+	return stats
+}
+
+// Health implements HealthChecker by forwarding to the wrapped sink, if it implements it.
+func (s *spoolingSink) Health(ctx context.Context) error {
+	if hc, ok := s.sink.(HealthChecker); ok {
+		return hc.Health(ctx)
+	}
+	return nil
+}
+
+// Unwrap returns the wrapped sink.
+func (s *spoolingSink) Unwrap() zap.Sink {
+	return s.sink
+}
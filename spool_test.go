@@ -0,0 +1,166 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakySink is a memorySink failing its writes while down is set.
+type flakySink struct {
+	memorySink
+
+	mu   sync.Mutex
+	down bool
+}
+
+// Write fails while the sink is down.
+func (s *flakySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		return 0, errors.New("unavailable")
+	}
+	return s.memorySink.Write(p)
+}
+
+// setDown marks the sink as down or recovered.
+func (s *flakySink) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func TestSpoolingSink(t *testing.T) {
+	sink := &flakySink{down: true}
+	dir := t.TempDir()
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) {
+		return logger.SpoolingSink(sink, logger.SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	})
+	path := scheme + "://"
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{path}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	l.Info(ctx, "first")
+	l.Info(ctx, "second")
+	require.NoError(t, l.Sync(), "spooled entries are not a Sync error")
+
+	stats := l.SpoolStats()[path]
+	require.Equal(t, 2, stats.Entries)
+	require.Positive(t, stats.Bytes)
+	require.Positive(t, stats.OldestAge)
+
+	sink.setDown(false)
+	l.Info(ctx, "third")
+	require.Empty(t, sink.logs.String(), "new entries should queue behind the spooled ones")
+
+	require.NoError(t, l.Sync())
+	lines := decodeLines(t, &sink.memorySink)
+	require.Len(t, lines, 3)
+	for i, msg := range []string{"first", "second", "third"} {
+		require.Equal(t, msg, lines[i]["msg"])
+	}
+	require.Equal(t, logger.SpoolStats{}, l.SpoolStats()[path])
+}
+
+func TestSpoolingSinkReplaysEarlierSpool(t *testing.T) {
+	dir := t.TempDir()
+	down := &flakySink{down: true}
+	spooled, err := logger.SpoolingSink(down, logger.SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	_, err = spooled.Write([]byte("{\"msg\":\"before restart\"}\n"))
+	require.NoError(t, err)
+	require.NoError(t, spooled.Close())
+
+	up := &flakySink{}
+	spooled, err = logger.SpoolingSink(up, logger.SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, 1, spooled.(logger.Spooler).SpoolStats().Entries)
+	require.NoError(t, spooled.Sync())
+	require.Equal(t, "{\"msg\":\"before restart\"}\n", up.logs.String())
+	require.NoError(t, spooled.Close())
+
+	again := &flakySink{}
+	spooled, err = logger.SpoolingSink(again, logger.SpoolConfig{Dir: dir, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	require.Zero(t, spooled.(logger.Spooler).SpoolStats().Entries, "replayed writes should not be replayed again")
+	require.NoError(t, spooled.Close())
+}
+
+func TestSpoolingSinkFull(t *testing.T) {
+	sink := &flakySink{down: true}
+	spooled, err := logger.SpoolingSink(sink, logger.SpoolConfig{Dir: t.TempDir(), MaxBytes: 64, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, spooled.Close()) }()
+
+	entry := make([]byte, 40)
+	_, err = spooled.Write(entry)
+	require.NoError(t, err)
+	_, err = spooled.Write(entry)
+	require.ErrorContains(t, err, "full")
+
+	stats := spooled.(logger.Spooler).SpoolStats()
+	require.Equal(t, 1, stats.Entries)
+	require.Equal(t, uint64(1), stats.Dropped)
+}
+
+// limitedSink is a memorySink failing its writes once it accepted budget of them.
+type limitedSink struct {
+	memorySink
+
+	budget int
+}
+
+// Write fails once the budget is spent.
+func (s *limitedSink) Write(p []byte) (int, error) {
+	if s.budget == 0 {
+		return 0, errors.New("unavailable")
+	}
+	s.budget--
+	return s.memorySink.Write(p)
+}
+
+func TestSpoolingSinkCompacts(t *testing.T) {
+	sink := &limitedSink{}
+	dir := t.TempDir()
+	spooled, err := logger.SpoolingSink(sink, logger.SpoolConfig{Dir: dir, MaxBytes: 200, RetryInterval: time.Hour})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, spooled.Close()) }()
+
+	entry := func(i int) []byte { return []byte(fmt.Sprintf("{\"msg\":\"entry %02d\"}\n", i)) }
+	var want string
+	for i := range 22 {
+		want += string(entry(i))
+	}
+
+	for i := range 2 {
+		_, err = spooled.Write(entry(i))
+		require.NoError(t, err)
+	}
+	for i := 2; i < 22; i++ {
+		// The sink recovers for a single write, so the spool never drains.
+		sink.budget = 1
+		require.NoError(t, spooled.Sync())
+		_, err = spooled.Write(entry(i))
+		require.NoError(t, err)
+
+		info, err := os.Stat(filepath.Join(dir, "spool"))
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), int64(300), "replayed writes should be compacted away")
+	}
+
+	sink.budget = -1 // Unlimited.
+	require.NoError(t, spooled.Sync())
+	require.Equal(t, want, sink.logs.String())
+}
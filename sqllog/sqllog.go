@@ -0,0 +1,191 @@
+// Package sqllog wraps database/sql drivers so that the queries they run are logged through
+// a Logger, with their duration, errors and, optionally, redacted arguments:
+//
+//	db, err := sqllog.Open("postgres", dsn, log, sqllog.WithArgs(sqllog.MaskStrings))
+//
+// Every Exec and Query, also of prepared statements and transactions, is logged with the
+// message "sql query" and the query, duration_ms and rows_affected fields, and error when it
+// failed. The context passed to the query is passed to the Logger, so entries carry its trace
+// ID. Failed connections, prepares, begins, commits and rollbacks are logged as errors.
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// queryMessage is the message of the entries of queries.
+const queryMessage = "sql query"
+
+// ArgRedactor returns the value logged for the argument of a query with the given ordinal
+// position, starting at 1, and name, which is empty for positional arguments.
+type ArgRedactor func(ordinal int, name string, value driver.Value) interface{}
+
+// MaskStrings is an ArgRedactor logging numbers, booleans, times and nil as they are, and
+// replacing strings and byte slices, which may hold personal data or secrets, by "[REDACTED]".
+func MaskStrings(_ int, _ string, value driver.Value) interface{} {
+	switch value.(type) {
+	case string, []byte:
+		return "[REDACTED]"
+	default:
+		return value
+	}
+}
+
+// config holds the settings of the wrapped driver.
+type config struct {
+	level         zapcore.Level
+	errorLevel    zapcore.Level
+	slowLevel     zapcore.Level
+	slowThreshold time.Duration
+	args          ArgRedactor
+}
+
+// Option defines a functional option for configuring the wrapped driver.
+type Option func(cfg *config)
+
+// WithQueryLevel sets the level at which queries are logged. It defaults to DebugLevel.
+func WithQueryLevel(level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.level = level
+	}
+}
+
+// WithErrorLevel sets the level at which failed queries and operations are logged. It
+// defaults to ErrorLevel.
+func WithErrorLevel(level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.errorLevel = level
+	}
+}
+
+// WithSlowQuery logs the queries that took at least threshold at level instead of the query
+// level, e.g. to log slow queries at WarnLevel while others are only logged in debug mode.
+func WithSlowQuery(threshold time.Duration, level zapcore.Level) Option {
+	return func(cfg *config) {
+		cfg.slowThreshold = threshold
+		cfg.slowLevel = level
+	}
+}
+
+// WithArgs logs the arguments of the queries as the args field, passing each through redact.
+// Arguments are not logged by default.
+func WithArgs(redact ArgRedactor) Option {
+	return func(cfg *config) {
+		cfg.args = redact
+	}
+}
+
+// Open opens a database like sql.Open, wrapping the driver registered as driverName so that
+// its queries are logged through l.
+func Open(driverName, dsn string, l *logger.Logger, opts ...Option) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	var c driver.Connector
+	if dc, ok := d.(driver.DriverContext); ok {
+		if c, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		c = &dsnConnector{driver: d, dsn: dsn}
+	}
+	return sql.OpenDB(WrapConnector(c, l, opts...)), nil
+}
+
+// WrapConnector returns a connector whose connections log their queries through l, for
+// sql.OpenDB.
+func WrapConnector(c driver.Connector, l *logger.Logger, opts ...Option) driver.Connector {
+	cfg := &config{level: zapcore.DebugLevel, errorLevel: zapcore.ErrorLevel}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &connector{Connector: c, log: &queryLogger{l: l, cfg: cfg}}
+}
+
+// dsnConnector is the driver.Connector of drivers that do not implement driver.DriverContext.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+// Connect implements driver.Connector.
+func (c *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+// Driver implements driver.Connector.
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// connector is a driver.Connector wrapping the connections of another.
+type connector struct {
+	driver.Connector
+	log *queryLogger
+}
+
+// Connect implements driver.Connector.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		c.log.failed(ctx, "sql connect failed", err)
+		return nil, err
+	}
+	return &conn{Conn: dc, log: c.log}, nil
+}
+
+// queryLogger logs queries and failed operations, reporting the code calling database/sql as
+// their caller.
+type queryLogger struct {
+	l   *logger.Logger
+	cfg *config
+}
+
+// query logs a query that started at start. rows is the number of rows affected, or -1.
+func (q *queryLogger) query(ctx context.Context, query string, args []driver.NamedValue, start time.Time, rows int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	elapsed := time.Since(start)
+	level := q.cfg.level
+	if q.cfg.slowThreshold > 0 && elapsed >= q.cfg.slowThreshold {
+		level = q.cfg.slowLevel
+	}
+	keyVals := []interface{}{"query", query, "duration_ms", float64(elapsed.Microseconds()) / 1000}
+	if q.cfg.args != nil {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = q.cfg.args(arg.Ordinal, arg.Name, arg.Value)
+		}
+		keyVals = append(keyVals, "args", values)
+	}
+	if rows >= 0 {
+		keyVals = append(keyVals, "rows_affected", rows)
+	}
+	if err != nil {
+		level = max(level, q.cfg.errorLevel)
+		keyVals = append(keyVals, "error", err)
+	}
+	q.l.LogDepth(ctx, logger.CallerDepth("database/sql"), level, queryMessage, keyVals...)
+}
+
+// failed logs a failed operation other than a query.
+func (q *queryLogger) failed(ctx context.Context, msg string, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	q.l.LogDepth(ctx, logger.CallerDepth("database/sql"), q.cfg.errorLevel, msg, "error", err)
+}
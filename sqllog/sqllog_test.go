@@ -0,0 +1,179 @@
+package sqllog_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/janduursma/zap-logger-wrapper/v2/loggertest"
+	"github.com/janduursma/zap-logger-wrapper/v2/sqllog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// errQuery is returned by fakeConn for queries containing FAIL.
+var errQuery = errors.New("syntax error")
+
+func init() {
+	sql.Register("sqllogfake", fakeDriver{})
+}
+
+// fakeDriver is a driver.Driver whose connections execute every query successfully,
+// affecting one row, unless it contains FAIL.
+type fakeDriver struct{}
+
+// Open implements driver.Driver.
+func (fakeDriver) Open(_ string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+// fakeConn is the driver.Conn of fakeDriver.
+type fakeConn struct{}
+
+// Prepare implements driver.Conn.
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{query: query}, nil
+}
+
+// Close implements driver.Conn.
+func (fakeConn) Close() error { return nil }
+
+// Begin implements driver.Conn.
+func (fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+// ExecContext implements driver.ExecerContext.
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "FAIL") {
+		return nil, errQuery
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// fakeStmt is the driver.Stmt of fakeConn.
+type fakeStmt struct {
+	query string
+}
+
+// Close implements driver.Stmt.
+func (fakeStmt) Close() error { return nil }
+
+// NumInput implements driver.Stmt.
+func (fakeStmt) NumInput() int { return -1 }
+
+// Exec implements driver.Stmt.
+func (s fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+// Query implements driver.Stmt.
+func (s fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "FAIL") {
+		return nil, errQuery
+	}
+	return &fakeRows{}, nil
+}
+
+// fakeRows holds a single row with a single column.
+type fakeRows struct {
+	done bool
+}
+
+// Columns implements driver.Rows.
+func (*fakeRows) Columns() []string { return []string{"n"} }
+
+// Close implements driver.Rows.
+func (*fakeRows) Close() error { return nil }
+
+// Next implements driver.Rows.
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(42)
+	return nil
+}
+
+// fakeTx is the driver.Tx of fakeConn.
+type fakeTx struct{}
+
+// Commit implements driver.Tx.
+func (fakeTx) Commit() error { return nil }
+
+// Rollback implements driver.Tx.
+func (fakeTx) Rollback() error { return errors.New("connection lost") }
+
+func TestOpen(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, entries := loggertest.NewTestLogger(t)
+	db, err := sqllog.Open("sqllogfake", "", l, sqllog.WithArgs(sqllog.MaskStrings))
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+
+	_, err = db.ExecContext(ctx, "INSERT INTO users (name, age) VALUES (?, ?)", "alice", 30)
+	require.NoError(t, err)
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT n FROM numbers WHERE id = ?", 7).Scan(&n))
+	require.Equal(t, 42, n)
+
+	queries := entries.FilterMessage("sql query").All()
+	require.Len(t, queries, 2)
+	insert := queries[0].ContextMap()
+	require.Equal(t, zapcore.DebugLevel, queries[0].Level)
+	require.Equal(t, "INSERT INTO users (name, age) VALUES (?, ?)", insert["query"])
+	require.Equal(t, []interface{}{"[REDACTED]", int64(30)}, insert["args"])
+	require.Equal(t, int64(1), insert["rows_affected"])
+	require.Equal(t, "req-1", insert["request_id"])
+	require.Contains(t, insert, "duration_ms")
+	require.Equal(t, "SELECT n FROM numbers WHERE id = ?", queries[1].ContextMap()["query"])
+	require.NotContains(t, queries[1].ContextMap(), "rows_affected")
+	for _, q := range queries {
+		require.Regexp(t, `/sqllog_test\.go:\d+$`, q.Caller.String(), "the code running the query should be the caller")
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	db, err := sqllog.Open("sqllogfake", "", l)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, "FAIL", "secret")
+	require.ErrorIs(t, err, errQuery)
+	_, err = db.QueryContext(ctx, "SELECT FAIL")
+	require.ErrorIs(t, err, errQuery)
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.Error(t, tx.Rollback())
+
+	failed := entries.FilterLevel(zapcore.ErrorLevel)
+	loggertest.AssertLogged(t, failed, zapcore.ErrorLevel, "sql query", "query", "FAIL", "error", "syntax error")
+	loggertest.AssertLogged(t, failed, zapcore.ErrorLevel, "sql query", "query", "SELECT FAIL")
+	loggertest.AssertLogged(t, failed, zapcore.ErrorLevel, "sql rollback failed", "error", "connection lost")
+	require.NotContains(t, failed.All()[0].ContextMap(), "args", "args should not be logged by default")
+}
+
+func TestWithSlowQuery(t *testing.T) {
+	l, entries := loggertest.NewTestLogger(t)
+	db, err := sqllog.Open("sqllogfake", "", l,
+		sqllog.WithQueryLevel(zapcore.InfoLevel),
+		sqllog.WithSlowQuery(time.Nanosecond, zapcore.WarnLevel),
+	)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("UPDATE users SET age = age + 1")
+	require.NoError(t, err)
+
+	loggertest.AssertLogged(t, entries, zapcore.WarnLevel, "sql query", "query", "UPDATE users SET age = age + 1")
+}
@@ -0,0 +1,286 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// statsBuckets is the number of buckets the stats window is divided into.
+	statsBuckets = 60
+	// statsTopMessages is the number of most frequent messages reported by Stats.
+	statsTopMessages = 10
+	// statsMaxMessages bounds the distinct messages tracked per bucket.
+	statsMaxMessages = 1024
+	// outputsSinkName names the configured output paths in SinkLatencies.
+	outputsSinkName = "outputs"
+)
+
+// Stats summarizes the entries handled by a Logger over a sliding window.
+type Stats struct {
+	// Window is the period covered by the stats.
+	Window time.Duration
+	// Levels counts the written entries by level.
+	Levels map[zapcore.Level]uint64
+	// TopMessages lists the most frequent message fingerprints, most frequent first.
+	TopMessages []MessageStats
+	// Dropped counts the entries that were dropped instead of written, e.g. by sampling.
+	Dropped uint64
+	// SinkLatencies reports how long writing to each sink took, keyed by sink name.
+	// The configured output paths are reported as "outputs", extra cores by their type.
+	SinkLatencies map[string]LatencyStats
+}
+
+// MessageStats counts the entries sharing a message fingerprint.
+type MessageStats struct {
+	Fingerprint string
+	// Message is an example message with this fingerprint.
+	Message string
+	Count   uint64
+}
+
+// LatencyStats summarizes write latencies.
+type LatencyStats struct {
+	Count uint64
+	Mean  time.Duration
+	Max   time.Duration
+}
+
+// WithStats enables in-process statistics over a sliding window of the given length,
+// which can be queried with Logger.Stats, for example from a debug endpoint.
+func WithStats(window time.Duration) Option {
+	return func(l *Logger) {
+		if window > 0 {
+			l.stats = newStatsRecorder(window)
+		}
+	}
+}
+
+// Stats returns the statistics for the current window. It returns the zero Stats
+// unless WithStats is given.
+func (l *Logger) Stats() Stats {
+	if l.stats == nil {
+		return Stats{}
+	}
+	return l.stats.snapshot(time.Now())
+}
+
+// latencyTotal accumulates write latencies within a bucket.
+type latencyTotal struct {
+	count uint64
+	total time.Duration
+	max   time.Duration
+}
+
+// statsBucket holds the stats for one slice of the window.
+type statsBucket struct {
+	start     int64
+	levels    [zapcore.FatalLevel - zapcore.DebugLevel + 1]uint64
+	messages  map[string]*MessageStats
+	dropped   uint64
+	latencies map[string]*latencyTotal
+}
+
+// statsRecorder maintains the stats of a Logger and its children.
+type statsRecorder struct {
+	window time.Duration
+	span   int64
+
+	mu      sync.Mutex
+	buckets [statsBuckets]statsBucket
+}
+
+// newStatsRecorder creates a statsRecorder for the given window.
+func newStatsRecorder(window time.Duration) *statsRecorder {
+	span := int64(window) / statsBuckets
+	if span == 0 {
+		span = 1
+	}
+	return &statsRecorder{window: window, span: span}
+}
+
+// bucket returns the bucket for t, resetting it when it held an older slice. Callers hold mu.
+func (r *statsRecorder) bucket(t time.Time) *statsBucket {
+	slot := t.UnixNano() / r.span
+	b := &r.buckets[slot%statsBuckets]
+	if start := slot * r.span; b.start != start {
+		*b = statsBucket{start: start}
+	}
+	return b
+}
+
+// recordEntry counts a written entry.
+func (r *statsRecorder) recordEntry(ent zapcore.Entry, fields []zapcore.Field) {
+	fp := fingerprint(ent.Message, findError(fields))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.bucket(ent.Time)
+	if ent.Level >= zapcore.DebugLevel && ent.Level <= zapcore.FatalLevel {
+		b.levels[ent.Level-zapcore.DebugLevel]++
+	}
+	if b.messages == nil {
+		b.messages = make(map[string]*MessageStats)
+	}
+	if m, ok := b.messages[fp]; ok {
+		m.Count++
+	} else if len(b.messages) < statsMaxMessages {
+		b.messages[fp] = &MessageStats{Fingerprint: fp, Message: ent.Message, Count: 1}
+	}
+}
+
+// recordDrop counts a dropped entry.
+func (r *statsRecorder) recordDrop(ent zapcore.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bucket(ent.Time).dropped++
+}
+
+// recordLatency records how long writing an entry to a sink took.
+func (r *statsRecorder) recordLatency(sink string, at time.Time, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := r.bucket(at)
+	if b.latencies == nil {
+		b.latencies = make(map[string]*latencyTotal)
+	}
+	lt, ok := b.latencies[sink]
+	if !ok {
+		lt = &latencyTotal{}
+		b.latencies[sink] = lt
+	}
+	lt.count++
+	lt.total += d
+	lt.max = max(lt.max, d)
+}
+
+// snapshot aggregates the buckets within the window ending at now.
+func (r *statsRecorder) snapshot(now time.Time) Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		Window:        r.window,
+		Levels:        make(map[zapcore.Level]uint64),
+		SinkLatencies: make(map[string]LatencyStats),
+	}
+	messages := make(map[string]*MessageStats)
+	latencies := make(map[string]*latencyTotal)
+	oldest := now.UnixNano() - int64(r.window)
+
+	for i := range r.buckets {
+		b := &r.buckets[i]
+		if b.start+r.span <= oldest || b.start > now.UnixNano() {
+			continue
+		}
+		for i, n := range b.levels {
+			if n > 0 {
+				stats.Levels[zapcore.DebugLevel+zapcore.Level(i)] += n
+			}
+		}
+		for fp, m := range b.messages {
+			if total, ok := messages[fp]; ok {
+				total.Count += m.Count
+			} else {
+				messages[fp] = &MessageStats{Fingerprint: fp, Message: m.Message, Count: m.Count}
+			}
+		}
+		stats.Dropped += b.dropped
+		for sink, lt := range b.latencies {
+			total, ok := latencies[sink]
+			if !ok {
+				total = &latencyTotal{}
+				latencies[sink] = total
+			}
+			total.count += lt.count
+			total.total += lt.total
+			total.max = max(total.max, lt.max)
+		}
+	}
+
+	for _, m := range messages {
+		stats.TopMessages = append(stats.TopMessages, *m)
+	}
+	sort.Slice(stats.TopMessages, func(i, j int) bool {
+		a, b := stats.TopMessages[i], stats.TopMessages[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	if len(stats.TopMessages) > statsTopMessages {
+		stats.TopMessages = stats.TopMessages[:statsTopMessages]
+	}
+	for sink, lt := range latencies {
+		stats.SinkLatencies[sink] = LatencyStats{
+			Count: lt.count,
+			Mean:  lt.total / time.Duration(lt.count),
+			Max:   lt.max,
+		}
+	}
+	return stats
+}
+
+// statsCore is a zapcore.Core that counts the entries written to the wrapped core.
+type statsCore struct {
+	zapcore.Core
+	recorder *statsRecorder
+}
+
+// With implements zapcore.Core.
+func (c *statsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &statsCore{Core: c.Core.With(fields), recorder: c.recorder}
+}
+
+// Check implements zapcore.Core.
+func (c *statsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *statsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.recorder.recordEntry(ent, fields)
+	return c.Core.Write(ent, fields)
+}
+
+// latencyCore is a zapcore.Core that measures how long writing to a sink takes.
+type latencyCore struct {
+	zapcore.Core
+	name     string
+	recorder *statsRecorder
+}
+
+// sinkName returns the name under which the latency of an extra core is reported.
+func sinkName(core zapcore.Core) string {
+	return fmt.Sprintf("%T", core)
+}
+
+// With implements zapcore.Core.
+func (c *latencyCore) With(fields []zapcore.Field) zapcore.Core {
+	return &latencyCore{Core: c.Core.With(fields), name: c.name, recorder: c.recorder}
+}
+
+// Check implements zapcore.Core.
+func (c *latencyCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *latencyCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	start := time.Now()
+	err := c.Core.Write(ent, fields)
+	c.recorder.recordLatency(c.name, ent.Time, time.Since(start))
+	return err
+}
@@ -0,0 +1,61 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStats(t *testing.T) {
+	core, _ := observer.New(zap.InfoLevel)
+	l, _ := newMemoryLogger(t,
+		logger.WithStats(time.Minute),
+		logger.WithSampling(time.Minute, 3, 0),
+		logger.WithCore(core),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		l.Info(ctx, "user 1 logged in")
+	}
+	l.Info(ctx, "user 2 logged in")
+	l.Error(ctx, "payment failed", "err", errors.New("card declined"))
+	l.Debug(ctx, "below the level")
+
+	stats := l.Stats()
+	require.Equal(t, time.Minute, stats.Window)
+	require.Equal(t, map[zapcore.Level]uint64{zapcore.InfoLevel: 4, zapcore.ErrorLevel: 1}, stats.Levels)
+	require.Equal(t, uint64(2), stats.Dropped, "sampled entries should be counted as dropped")
+
+	require.Len(t, stats.TopMessages, 2)
+	require.Equal(t, uint64(4), stats.TopMessages[0].Count, "messages differing only in numbers should share a fingerprint")
+	require.Equal(t, "user 1 logged in", stats.TopMessages[0].Message)
+	require.Equal(t, uint64(1), stats.TopMessages[1].Count)
+
+	require.Equal(t, uint64(5), stats.SinkLatencies["outputs"].Count)
+	require.Equal(t, uint64(5), stats.SinkLatencies["*observer.contextObserver"].Count)
+	require.GreaterOrEqual(t, stats.SinkLatencies["outputs"].Max, stats.SinkLatencies["outputs"].Mean)
+}
+
+func TestStatsSharedWithChildren(t *testing.T) {
+	l, _ := newMemoryLogger(t, logger.WithStats(time.Minute))
+
+	l.With("component", "billing").Info(context.Background(), "from child")
+
+	require.Equal(t, uint64(1), l.Stats().Levels[zapcore.InfoLevel])
+}
+
+func TestStatsDisabled(t *testing.T) {
+	l, _ := newMemoryLogger(t)
+
+	l.Info(context.Background(), "not counted")
+
+	require.Equal(t, logger.Stats{}, l.Stats())
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithStrictKeyVals enables or disables the validation of the key-value pairs passed to logging
+// calls and With. In strict mode, a key without a value or a key that is not a string is
+// reported by an entry at DPanicLevel, "invalid key-value pairs", carrying the call site and
+// the ignored values, and the invalid pairs are dropped from the entry; like zap's DPanic, it
+// panics when a development logger is passed to WithExistingZap. Without it, zap drops them
+// with a less specific entry that does not point at the call site. Strict mode is enabled by
+// default with FormatConsole, the format for local development.
+func WithStrictKeyVals(enabled bool) Option {
+	return func(l *Logger) {
+		l.strictKeyVals = &enabled
+	}
+}
+
+// strict reports whether the key-value pairs are validated.
+func (l *Logger) strict() bool {
+	if l.strictKeyVals != nil {
+		return *l.strictKeyVals
+	}
+	return l.format == FormatConsole
+}
+
+// validateKeyVals reports and drops the invalid pairs of keyVals in strict mode, and returns
+// the valid ones; keyVals is never modified. skip adjusts the caller of the report for callers
+// other than log.
+func (l *Logger) validateKeyVals(keyVals []interface{}, skip int) []interface{} {
+	if !l.strict() {
+		return keyVals
+	}
+	var (
+		valid   []interface{}
+		ignored []interface{}
+		reasons []string
+	)
+	for i := 0; i < len(keyVals); i++ {
+		if _, ok := keyVals[i].(zapcore.Field); ok {
+			valid = append(valid, keyVals[i])
+			continue
+		}
+		if i == len(keyVals)-1 {
+			ignored = append(ignored, keyVals[i])
+			reasons = append(reasons, fmt.Sprintf("key %v without a value", keyVals[i]))
+			break
+		}
+		if _, ok := keyVals[i].(string); !ok {
+			ignored = append(ignored, keyVals[i], keyVals[i+1])
+			reasons = append(reasons, fmt.Sprintf("non-string key %v (%T)", keyVals[i], keyVals[i]))
+		} else {
+			valid = append(valid, keyVals[i], keyVals[i+1])
+		}
+		i++
+	}
+	if len(ignored) == 0 {
+		return keyVals
+	}
+
+	l.baseLogger.Desugar().WithOptions(zap.AddCallerSkip(skip)).DPanic(
+		"invalid key-value pairs", zap.Strings("errors", reasons), zap.Any("ignored", ignored),
+	)
+	return valid
+}
@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithStrictKeyVals(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithStrictKeyVals(true))
+
+	l.Info(context.Background(), "entry", "user", "alice", 42, "answer", zap.Int("attempt", 2), "dangling")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "dpanic", entries[0]["level"])
+	require.Equal(t, "invalid key-value pairs", entries[0]["msg"])
+	require.Contains(t, entries[0]["caller"], "strict_test.go")
+	require.Equal(t, []any{"non-string key 42 (int)", "key dangling without a value"}, entries[0]["errors"])
+	require.Equal(t, []any{42.0, "answer", "dangling"}, entries[0]["ignored"])
+
+	require.Equal(t, "entry", entries[1]["msg"])
+	require.Equal(t, "alice", entries[1]["user"])
+	require.EqualValues(t, 2, entries[1]["attempt"])
+	require.NotContains(t, entries[1], "ignored")
+}
+
+func TestWithStrictKeyValsWith(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithStrictKeyVals(true))
+
+	l.With("component").Info(context.Background(), "entry")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, "invalid key-value pairs", entries[0]["msg"])
+	require.Contains(t, entries[0]["caller"], "strict_test.go")
+	require.Equal(t, "entry", entries[1]["msg"])
+}
+
+func TestWithStrictKeyValsDefault(t *testing.T) {
+	console, consoleSink := newMemoryLogger(t, logger.WithFormat(logger.FormatConsole))
+	console.Info(context.Background(), "entry", "dangling")
+	require.Contains(t, consoleSink.logs.String(), "invalid key-value pairs", "strict mode should be enabled with FormatConsole")
+
+	l, sink := newMemoryLogger(t)
+	l.Info(context.Background(), "entry", "dangling")
+	out := sink.logs.String()
+	require.NotContains(t, out, "invalid key-value pairs")
+	require.True(t, strings.Contains(out, "Ignored key without a value."), "zap should report the pair itself")
+}
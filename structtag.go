@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logTag is the struct tag consulted when a struct is logged as a field value.
+// `log:"redact"` masks a member and `log:"-"` omits it.
+const logTag = "log"
+
+// taggedTypes caches whether a type, or a type nested in it, carries log tags.
+var taggedTypes sync.Map // map[reflect.Type]bool
+
+// structTagTransform is a fieldTransform that encodes structs carrying log tags through
+// structMarshaler, so members tagged `log:"redact"` or `log:"-"` are masked or omitted
+// instead of being dumped verbatim. Structs without log tags are left to zap.
+func structTagTransform(f zapcore.Field) zapcore.Field {
+	if f.Type != zapcore.ReflectType || f.Interface == nil {
+		return f
+	}
+	if !hasLogTags(reflect.TypeOf(f.Interface)) {
+		return f
+	}
+	// Nil values are left to zap, which encodes them as null.
+	v, ok := indirect(reflect.ValueOf(f.Interface))
+	if !ok {
+		return f
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return zap.Object(f.Key, structMarshaler{v})
+	case reflect.Slice, reflect.Array:
+		return zap.Array(f.Key, sliceMarshaler{v})
+	}
+	return f
+}
+
+// indirect dereferences the pointers and interfaces of v. It reports false when it meets a
+// nil pointer, interface or slice.
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() != reflect.Slice || !v.IsNil()
+}
+
+// isStruct reports whether t is a struct or a pointer to one.
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// hasLogTags reports whether t is a struct, or a pointer, slice or array of structs,
+// that has members with log tags, directly or in nested structs.
+func hasLogTags(t reflect.Type) bool {
+	if cached, ok := taggedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	tagged := findLogTags(t, map[reflect.Type]bool{})
+	taggedTypes.Store(t, tagged)
+	return tagged
+}
+
+// findLogTags implements hasLogTags. Types being visited are skipped, so recursive types
+// terminate; their tags are found by the frame visiting them. The results of nested types are
+// not cached, since they can be incomplete while a type referring to them is being visited.
+func findLogTags(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if cached, ok := taggedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return findLogTags(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() && !sf.Anonymous {
+				continue
+			}
+			if _, ok := sf.Tag.Lookup(logTag); ok || findLogTags(sf.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structMarshaler is a zapcore.ObjectMarshaler for structs that honors log tags.
+// Member names follow the json tag, like zap's default reflection-based encoding.
+type structMarshaler struct {
+	v reflect.Value
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m structMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	v := m.v
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+
+		name, omitEmpty, skip := jsonName(sf)
+		if skip || sf.Tag.Get(logTag) == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" && isStruct(sf.Type) {
+			if err := (structMarshaler{fv}).MarshalLogObject(enc); err != nil {
+				return err
+			}
+			continue
+		}
+		if !sf.IsExported() || (omitEmpty && fv.IsZero()) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		switch {
+		case sf.Tag.Get(logTag) == "redact":
+			enc.AddString(name, redacted)
+		case hasLogTags(sf.Type):
+			if err := addTagged(enc, name, fv); err != nil {
+				return err
+			}
+		default:
+			if err := enc.AddReflected(name, fv.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addTagged adds v, whose type carries log tags, to enc under key. Nil values are added as null.
+func addTagged(enc zapcore.ObjectEncoder, key string, v reflect.Value) error {
+	v, ok := indirect(v)
+	switch {
+	case !ok:
+		return enc.AddReflected(key, nil)
+	case v.Kind() == reflect.Struct:
+		return enc.AddObject(key, structMarshaler{v})
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		return enc.AddArray(key, sliceMarshaler{v})
+	default:
+		return enc.AddReflected(key, v.Interface())
+	}
+}
+
+// sliceMarshaler is a zapcore.ArrayMarshaler for slices and arrays whose elements carry log
+// tags, such as structs, pointers to structs or nested slices of structs.
+type sliceMarshaler struct {
+	v reflect.Value
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (m sliceMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < m.v.Len(); i++ {
+		if err := appendTagged(enc, m.v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendTagged appends v, whose type carries log tags, to enc. Nil values are appended as null.
+func appendTagged(enc zapcore.ArrayEncoder, v reflect.Value) error {
+	v, ok := indirect(v)
+	switch {
+	case !ok:
+		return enc.AppendReflected(nil)
+	case v.Kind() == reflect.Struct:
+		return enc.AppendObject(structMarshaler{v})
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		return enc.AppendArray(sliceMarshaler{v})
+	default:
+		return enc.AppendReflected(v.Interface())
+	}
+}
+
+// jsonName returns the name and omitempty option of a struct member's json tag,
+// and whether the member is excluded from JSON.
+func jsonName(sf reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City   string `json:"city"`
+	Street string `json:"street" log:"redact"`
+}
+
+type account struct {
+	ID       int       `json:"id"`
+	Email    string    `json:"email" log:"redact"`
+	Password string    `log:"-"`
+	Note     string    `json:"note,omitempty"`
+	Home     address   `json:"home"`
+	Previous []address `json:"previous"`
+	Manager  *account  `json:"manager"`
+	internal string
+}
+
+type plain struct {
+	Name string `json:"name"`
+}
+
+func TestStructTagRedaction(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	acct := account{
+		ID:       7,
+		Email:    "jane@example.com",
+		Password: "hunter2",
+		Home:     address{City: "Utrecht", Street: "Main St 1"},
+		Previous: []address{{City: "Delft", Street: "Old St 2"}},
+		internal: "hidden",
+	}
+	l.Info(context.Background(), "account", "account", acct, "pointer", &acct, "accounts", []account{acct}, "plain", plain{Name: "x"})
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	want := map[string]any{
+		"id":       float64(7),
+		"email":    "[REDACTED]",
+		"home":     map[string]any{"city": "Utrecht", "street": "[REDACTED]"},
+		"previous": []any{map[string]any{"city": "Delft", "street": "[REDACTED]"}},
+		"manager":  nil,
+	}
+	require.Equal(t, want, entries[0]["account"])
+	require.Equal(t, want, entries[0]["pointer"], "pointers to structs should be handled")
+	require.Equal(t, []any{want}, entries[0]["accounts"], "slices of structs should be handled")
+	require.Equal(t, map[string]any{"name": "x"}, entries[0]["plain"], "structs without log tags are encoded as usual")
+	require.NotContains(t, sink.logs.String(), "hunter2")
+	require.NotContains(t, sink.logs.String(), "Main St")
+}
+
+type node struct {
+	Next   *node
+	Secret string `log:"redact"`
+}
+
+func TestStructTagRedactionRecursive(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	n := node{Next: &node{Secret: "hunter3"}, Secret: "hunter2"}
+	l.Info(context.Background(), "value", "node", n)
+	l.Info(context.Background(), "pointer", "node", &n)
+
+	require.NotContains(t, sink.logs.String(), "hunter")
+	for _, entry := range decodeLines(t, sink) {
+		require.Equal(t, "[REDACTED]", entry["node"].(map[string]any)["Secret"])
+	}
+}
+
+func TestStructTagRedactionContainers(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	addr := address{City: "Delft", Street: "Old St 2"}
+	want := map[string]any{"city": "Delft", "street": "[REDACTED]"}
+	var missing *[]address
+	l.Info(context.Background(), "containers",
+		"slice_pointer", &[]address{addr},
+		"nested", [][]address{{addr}, nil},
+		"array", [1][]*address{{&addr}},
+		"pointers", []*address{&addr, nil},
+		"nil_pointer", missing,
+	)
+
+	require.NotContains(t, sink.logs.String(), "Old St")
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, []any{want}, entries[0]["slice_pointer"], "pointers to slices should be handled")
+	require.Equal(t, []any{[]any{want}, nil}, entries[0]["nested"], "nested slices should be handled")
+	require.Equal(t, []any{[]any{want}}, entries[0]["array"], "arrays should be handled")
+	require.Equal(t, []any{want, nil}, entries[0]["pointers"], "nil elements should be written as null")
+	require.Contains(t, entries[0], "nil_pointer")
+	require.Nil(t, entries[0]["nil_pointer"])
+}
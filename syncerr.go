@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyncErrorFilter reports whether err, returned when syncing the output path, is benign and
+// should not be returned by Sync.
+type SyncErrorFilter func(path string, err error) bool
+
+// WithSyncErrorFilter replaces the filter of benign Sync errors, IgnoreConsoleSyncErrors by
+// default. A nil filter returns every error.
+func WithSyncErrorFilter(filter SyncErrorFilter) Option {
+	return func(l *Logger) {
+		l.syncErrorFilter = filter
+	}
+}
+
+// IgnoreConsoleSyncErrors is the default SyncErrorFilter. It ignores the errors returned when
+// syncing stdout or stderr while they are a terminal or a pipe, which cannot be synced: EINVAL,
+// ENOTSUP and ENOTTY, and ERROR_INVALID_HANDLE on Windows. Syncing files still reports them.
+func IgnoreConsoleSyncErrors(path string, err error) bool {
+	if path != "stdout" && path != "stderr" {
+		return false
+	}
+	for _, benign := range benignSyncErrors {
+		if errors.Is(err, benign) {
+			return true
+		}
+	}
+	return false
+}
+
+// benignConsoleSyncErrors are the errors returned when syncing a terminal or a pipe on all
+// platforms, see benignSyncErrors.
+var benignConsoleSyncErrors = []error{syscall.EINVAL, syscall.ENOTSUP, syscall.ENOTTY}
+
+// SyncError is a failure to sync an output path, returned by Sync. Several failures are
+// combined, so use errors.As to inspect them.
+type SyncError struct {
+	// Path is the output path that failed to sync. Error masks its credentials.
+	Path string
+	// Err is the error returned by its sink.
+	Err error
+}
+
+// Error implements error.
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("sync %s: %v", redactPath(e.Path), e.Err)
+}
+
+// Unwrap returns the error returned by the sink.
+func (e *SyncError) Unwrap() error {
+	return e.Err
+}
+
+// syncFilter is a zapcore.WriteSyncer dropping the benign errors of syncing an output path,
+// and reporting the others as SyncError.
+type syncFilter struct {
+	zapcore.WriteSyncer
+	path   string
+	benign SyncErrorFilter
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (s *syncFilter) Sync() error {
+	err := s.WriteSyncer.Sync()
+	if err == nil || (s.benign != nil && s.benign(s.path, err)) {
+		return nil
+	}
+	return &SyncError{Path: s.path, Err: err}
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package logger
+
+// benignSyncErrors are the errors ignored by IgnoreConsoleSyncErrors.
+var benignSyncErrors = benignConsoleSyncErrors
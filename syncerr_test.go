@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingSyncSink is a memorySink whose Sync returns err.
+type failingSyncSink struct {
+	memorySink
+	err error
+}
+
+// Sync returns the configured error.
+func (s *failingSyncSink) Sync() error {
+	return s.err
+}
+
+func TestSyncError(t *testing.T) {
+	sink := &failingSyncSink{err: syscall.EIO}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+
+	l, err := logger.New("test-service", logger.WithOutputPaths([]string{scheme + "://"}))
+	require.NoError(t, err)
+
+	err = l.Sync()
+	var syncErr *logger.SyncError
+	require.ErrorAs(t, err, &syncErr)
+	require.Equal(t, scheme+"://", syncErr.Path)
+	require.ErrorIs(t, err, syscall.EIO)
+
+	sink.err = syscall.EINVAL
+	require.ErrorIs(t, l.Sync(), syscall.EINVAL, "EINVAL is only benign on stdout and stderr")
+
+	err = &logger.SyncError{Path: scheme + "://collector?bearer=s3cret", Err: syscall.EIO}
+	require.EqualError(t, err, "sync "+scheme+"://collector?bearer=xxxxx: "+syscall.EIO.Error(), "credentials should be masked")
+}
+
+func TestWithSyncErrorFilter(t *testing.T) {
+	sink := &failingSyncSink{err: syscall.EIO}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{scheme + "://"}),
+		logger.WithSyncErrorFilter(func(_ string, err error) bool { return errors.Is(err, syscall.EIO) }),
+	)
+	require.NoError(t, err)
+	require.NoError(t, l.Sync())
+
+	sink.err = fmt.Errorf("disk full")
+	require.ErrorContains(t, l.Sync(), "disk full")
+}
+
+func TestIgnoreConsoleSyncErrors(t *testing.T) {
+	require.True(t, logger.IgnoreConsoleSyncErrors("stdout", syscall.EINVAL))
+	require.True(t, logger.IgnoreConsoleSyncErrors("stderr", fmt.Errorf("sync: %w", syscall.ENOTTY)))
+	require.False(t, logger.IgnoreConsoleSyncErrors("stdout", syscall.EIO))
+	require.False(t, logger.IgnoreConsoleSyncErrors("/var/log/app.log", syscall.EINVAL))
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package logger
+
+import (
+	"syscall"
+)
+
+// errorInvalidHandle is the ERROR_INVALID_HANDLE error, returned when syncing a console.
+const errorInvalidHandle = syscall.Errno(6)
+
+// benignSyncErrors are the errors ignored by IgnoreConsoleSyncErrors.
+var benignSyncErrors = append(benignConsoleSyncErrors, errorInvalidHandle)
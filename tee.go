@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// teeCore duplicates entries to several cores. Unlike zapcore.NewTee, its Write only
+// forwards an entry to the cores that are enabled for the entry's level. This matters
+// because the logger's wrapping cores register themselves in Check and call Write
+// directly, so the tee's members never get to run their own Check.
+// Together with the clearanceCores of the members added through WithClearance, it acts as
+// the router deciding which sinks receive an entry.
+type teeCore []zapcore.Core
+
+// newTeeCore creates a teeCore for the given cores.
+func newTeeCore(cores ...zapcore.Core) zapcore.Core {
+	return teeCore(cores)
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (t teeCore) Enabled(level zapcore.Level) bool {
+	for _, c := range t {
+		if c.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// With implements zapcore.Core.
+func (t teeCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := make(teeCore, len(t))
+	for i, c := range t {
+		clone[i] = c.With(fields)
+	}
+	return clone
+}
+
+// Check implements zapcore.Core.
+func (t teeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	for _, c := range t {
+		ce = c.Check(ent, ce)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (t teeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var err error
+	for _, c := range t {
+		if c.Enabled(ent.Level) {
+			err = errors.Join(err, c.Write(ent, fields))
+		}
+	}
+	return err
+}
+
+// Sync implements zapcore.Core.
+func (t teeCore) Sync() error {
+	var err error
+	for _, c := range t {
+		err = errors.Join(err, c.Sync())
+	}
+	return err
+}
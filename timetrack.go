@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// timeTrackConfig holds the settings for TimeTrack.
+type timeTrackConfig struct {
+	level     zapcore.Level
+	threshold time.Duration
+}
+
+// TimeTrackOption defines a functional option for configuring TimeTrack.
+type TimeTrackOption func(cfg *timeTrackConfig)
+
+// WithSlowThreshold logs only the operations that took at least d.
+func WithSlowThreshold(d time.Duration) TimeTrackOption {
+	return func(cfg *timeTrackConfig) {
+		cfg.threshold = d
+	}
+}
+
+// WithTimeTrackLevel sets the level at which the durations are logged. It defaults to InfoLevel.
+func WithTimeTrackLevel(level zapcore.Level) TimeTrackOption {
+	return func(cfg *timeTrackConfig) {
+		cfg.level = level
+	}
+}
+
+// TimeTrack starts timing the operation name and returns a function that logs an entry with
+// the name as its message and the milliseconds elapsed as duration_ms, typically deferred:
+//
+//	defer log.TimeTrack(ctx, "load_profile", logger.WithSlowThreshold(100*time.Millisecond))()
+func (l *Logger) TimeTrack(ctx context.Context, name string, opts ...TimeTrackOption) func() {
+	cfg := timeTrackConfig{level: zapcore.InfoLevel}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		if elapsed < cfg.threshold {
+			return
+		}
+		l.log(ctx, cfg.level, name, []interface{}{"duration_ms", float64(elapsed.Microseconds()) / 1000})
+	}
+}
@@ -0,0 +1,34 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTimeTrack(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	func() {
+		defer l.TimeTrack(context.Background(), "load_profile", logger.WithTimeTrackLevel(zapcore.ErrorLevel))()
+		time.Sleep(2 * time.Millisecond)
+	}()
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "error", entries[0]["level"])
+	require.Equal(t, "load_profile", entries[0]["msg"])
+	require.GreaterOrEqual(t, entries[0]["duration_ms"], float64(2))
+}
+
+func TestTimeTrackSlowThreshold(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+
+	l.TimeTrack(context.Background(), "fast", logger.WithSlowThreshold(time.Hour))()
+
+	require.Empty(t, sink.logs.String(), "operations faster than the threshold should not be logged")
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// traceIDKey, spanIDKey and traceSampledKey are the keys of the fields holding the trace
+// context returned by the GetTraceIDFn or GetTraceContextFn.
+const (
+	traceIDKey      = "trace_id"
+	spanIDKey       = "span_id"
+	traceSampledKey = "trace_sampled"
+)
+
+// WithTraceIDKey renames the trace_id field in the entries written to the output paths, e.g.
+// to traceId, X-B3-TraceId or correlation_id, as required by some log pipelines. Hooks, the
+// cores added through WithCore and formats with their own conventions, such as FormatECS,
+// still see it as trace_id.
+func WithTraceIDKey(key string) Option {
+	return func(l *Logger) {
+		l.traceIDKey = key
+	}
+}
+
+// WithTraceIDFirst writes the trace ID field to the output paths before all other fields,
+// including the fields added through With and the service field, for pipelines that only
+// inspect the beginning of an entry. The fields added through With are then encoded for every
+// entry again instead of once.
+func WithTraceIDFirst() Option {
+	return func(l *Logger) {
+		l.traceIDFirst = true
+	}
+}
+
+// traceFieldCore is a zapcore.Core renaming the trace ID field and moving it first.
+type traceFieldCore struct {
+	zapcore.Core
+	key   string
+	first bool
+	// fields are the fields added through With, held back while first is set so that the
+	// trace ID can precede them.
+	fields []zapcore.Field
+}
+
+// traceFieldCore wraps core, writing to the output paths, as configured through
+// WithTraceIDKey and WithTraceIDFirst.
+func (l *Logger) traceFieldCore(core zapcore.Core) zapcore.Core {
+	if (l.traceIDKey == "" || l.traceIDKey == traceIDKey) && !l.traceIDFirst {
+		return core
+	}
+	key := l.traceIDKey
+	if key == "" {
+		key = traceIDKey
+	}
+	return &traceFieldCore{Core: core, key: key, first: l.traceIDFirst}
+}
+
+// With implements zapcore.Core.
+func (c *traceFieldCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	fields = c.rename(fields)
+	if c.first {
+		clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	} else {
+		clone.Core = c.Core.With(fields)
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *traceFieldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *traceFieldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = c.rename(fields)
+	if !c.first {
+		return c.Core.Write(ent, fields)
+	}
+
+	ordered := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	for _, f := range fields {
+		if f.Key == c.key {
+			ordered = append(ordered, f)
+		}
+	}
+	ordered = append(ordered, c.fields...)
+	for _, f := range fields {
+		if f.Key != c.key {
+			ordered = append(ordered, f)
+		}
+	}
+	return c.Core.Write(ent, ordered)
+}
+
+// rename returns fields with the trace ID field renamed, leaving the caller's slice untouched.
+func (c *traceFieldCore) rename(fields []zapcore.Field) []zapcore.Field {
+	if c.key == traceIDKey {
+		return fields
+	}
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		if f.Key == traceIDKey {
+			f.Key = c.key
+		}
+		out[i] = f
+	}
+	return out
+}
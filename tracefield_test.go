@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTraceIDKey(t *testing.T) {
+	traceFn := func(context.Context) string { return "trace-1" }
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFn), logger.WithTraceIDKey("X-B3-TraceId"))
+
+	l.Info(context.Background(), "order placed")
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "trace-1", entries[0]["X-B3-TraceId"])
+	require.NotContains(t, entries[0], "trace_id")
+}
+
+func TestWithTraceIDFirst(t *testing.T) {
+	traceFn := func(context.Context) string { return "trace-1" }
+	l, sink := newMemoryLogger(t,
+		logger.WithTraceID(traceFn),
+		logger.WithTraceIDKey("correlation_id"),
+		logger.WithTraceIDFirst(),
+	)
+
+	l.With("component", "checkout").Info(context.Background(), "order placed", "order", 42)
+
+	line := sink.logs.String()
+	require.Contains(t, line, `"msg":"order placed","correlation_id":"trace-1",`)
+	require.Less(t, strings.Index(line, `"correlation_id"`), strings.Index(line, `"service"`))
+	require.Less(t, strings.Index(line, `"service"`), strings.Index(line, `"component"`))
+	require.Less(t, strings.Index(line, `"component"`), strings.Index(line, `"order"`))
+}
+
+func TestWithTraceContext(t *testing.T) {
+	traceFn := func(ctx context.Context) (string, string, bool) {
+		if ctx.Value(requestKey{}) == nil {
+			return "", "", false
+		}
+		return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true
+	}
+	l, sink := newMemoryLogger(t, logger.WithTraceContext(traceFn))
+
+	l.Info(context.WithValue(context.Background(), requestKey{}, "r-1"), "traced")
+	l.Info(context.Background(), "untraced")
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", entries[0]["span_id"])
+	require.Equal(t, true, entries[0]["trace_sampled"])
+	for _, key := range []string{"trace_id", "span_id", "trace_sampled"} {
+		require.NotContains(t, entries[1], key)
+	}
+}
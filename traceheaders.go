@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// The headers of the W3C Trace Context and B3 propagation formats.
+const (
+	TraceparentHeader = "traceparent"
+	B3Header          = "b3"
+	B3TraceIDHeader   = "X-B3-TraceId"
+	B3SpanIDHeader    = "X-B3-SpanId"
+	B3SampledHeader   = "X-B3-Sampled"
+	B3FlagsHeader     = "X-B3-Flags"
+)
+
+// traceHeadersKey is the context key of the trace context extracted from request headers.
+type traceHeadersKey struct{}
+
+// traceHeaders is the trace context extracted from request headers.
+type traceHeaders struct {
+	traceID, spanID string
+	sampled         bool
+}
+
+// ContextWithHTTPTraceHeaders returns a copy of the request's context carrying the trace
+// context of its traceparent, b3 or X-B3-* headers, in that order of precedence, for services
+// that propagate traces without running a tracing SDK. Log with WithTraceContext
+// (TraceContextFromHeaders) to add the trace_id, span_id and trace_sampled fields. Invalid
+// headers are ignored; without a valid header, the request's context is returned unchanged.
+func ContextWithHTTPTraceHeaders(r *http.Request) context.Context {
+	return contextWithTraceHeaders(r.Context(), r.Header.Get)
+}
+
+// ContextWithTraceMetadata is the equivalent of ContextWithHTTPTraceHeaders for gRPC
+// metadata, whose keys are lowercase. A google.golang.org/grpc/metadata.MD can be passed as is:
+//
+//	md, _ := metadata.FromIncomingContext(ctx)
+//	ctx = logger.ContextWithTraceMetadata(ctx, md)
+func ContextWithTraceMetadata(ctx context.Context, md map[string][]string) context.Context {
+	return contextWithTraceHeaders(ctx, func(key string) string {
+		if values := md[strings.ToLower(key)]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	})
+}
+
+// TraceContextFromHeaders is a GetTraceContextFn returning the trace context stored by
+// ContextWithHTTPTraceHeaders or ContextWithTraceMetadata, for WithTraceContext.
+func TraceContextFromHeaders(ctx context.Context) (traceID, spanID string, sampled bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+	h, _ := ctx.Value(traceHeadersKey{}).(traceHeaders)
+	return h.traceID, h.spanID, h.sampled
+}
+
+// contextWithTraceHeaders returns a copy of ctx carrying the trace context of the headers
+// returned by header, or ctx if there is none.
+func contextWithTraceHeaders(ctx context.Context, header func(key string) string) context.Context {
+	h, ok := parseTraceparent(header(TraceparentHeader))
+	if !ok {
+		h, ok = parseB3(header(B3Header))
+	}
+	if !ok {
+		h, ok = parseB3Multi(header)
+	}
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, traceHeadersKey{}, h)
+}
+
+// parseTraceparent parses a W3C traceparent header: version-traceid-spanid-flags.
+func parseTraceparent(v string) (traceHeaders, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || !isHexID(parts[0], 2) || parts[0] == "ff" || !isHexID(parts[3], 2) {
+		return traceHeaders{}, false
+	}
+	// Version 00 has exactly four parts; later versions may append more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return traceHeaders{}, false
+	}
+	if !isHexID(parts[1], 32) || !isHexID(parts[2], 16) {
+		return traceHeaders{}, false
+	}
+	return traceHeaders{
+		traceID: parts[1],
+		spanID:  parts[2],
+		sampled: hexDigit(parts[3][1])&1 == 1,
+	}, true
+}
+
+// parseB3 parses a single b3 header: traceid-spanid[-sampled[-parentspanid]]. A header
+// holding only the sampling state carries no trace.
+func parseB3(v string) (traceHeaders, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		return traceHeaders{}, false
+	}
+	traceID, ok := b3TraceID(parts[0])
+	if !ok || !isHexID(parts[1], 16) {
+		return traceHeaders{}, false
+	}
+	h := traceHeaders{traceID: traceID, spanID: parts[1]}
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "1", "d":
+			h.sampled = true
+		case "0":
+		default:
+			return traceHeaders{}, false
+		}
+	}
+	return h, true
+}
+
+// parseB3Multi parses the X-B3-* headers.
+func parseB3Multi(header func(key string) string) (traceHeaders, bool) {
+	traceID, ok := b3TraceID(header(B3TraceIDHeader))
+	spanID := strings.ToLower(header(B3SpanIDHeader))
+	if !ok || !isHexID(spanID, 16) {
+		return traceHeaders{}, false
+	}
+	sampled := header(B3SampledHeader)
+	return traceHeaders{
+		traceID: traceID,
+		spanID:  spanID,
+		sampled: sampled == "1" || sampled == "true" || header(B3FlagsHeader) == "1",
+	}, true
+}
+
+// b3TraceID returns a B3 trace ID of 16 or 32 hex digits as 32 lowercase hex digits, padding
+// 64-bit IDs with zeros as OpenTelemetry does.
+func b3TraceID(v string) (string, bool) {
+	v = strings.ToLower(v)
+	switch {
+	case isHexID(v, 32):
+		return v, true
+	case isHexID(v, 16):
+		return strings.Repeat("0", 16) + v, true
+	default:
+		return "", false
+	}
+}
+
+// isHexID reports whether v consists of n lowercase hex digits, not all zero.
+func isHexID(v string, n int) bool {
+	if len(v) != n {
+		return false
+	}
+	zero := true
+	for i := 0; i < len(v); i++ {
+		if hexDigit(v[i]) < 0 {
+			return false
+		}
+		zero = zero && v[i] == '0'
+	}
+	// Two-digit values, the version and flags, may be zero.
+	return !zero || n == 2
+}
+
+// hexDigit returns the value of a lowercase hex digit, or -1.
+func hexDigit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	default:
+		return -1
+	}
+}
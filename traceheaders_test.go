@@ -0,0 +1,79 @@
+package logger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithHTTPTraceHeaders(t *testing.T) {
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		traceID, spanID string
+		sampled         bool
+	}{
+		{
+			name:    "traceparent",
+			headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7", sampled: true,
+		},
+		{
+			name: "traceparent before b3",
+			headers: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+				"b3":          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7",
+		},
+		{
+			name:    "b3",
+			headers: map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"},
+			traceID: "80f198ee56343ba864fe8b2a57d3eff7", spanID: "e457b5a2e4d86bd1", sampled: true,
+		},
+		{
+			name: "b3 multi with a 64-bit trace ID",
+			headers: map[string]string{
+				"X-B3-TraceId": "a3ce929d0e0e4736",
+				"X-B3-SpanId":  "00f067aa0ba902b7",
+				"X-B3-Sampled": "1",
+			},
+			traceID: "0000000000000000a3ce929d0e0e4736", spanID: "00f067aa0ba902b7", sampled: true,
+		},
+		{name: "b3 sampling state only", headers: map[string]string{"b3": "1"}},
+		{name: "zero trace ID", headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}},
+		{name: "invalid version", headers: map[string]string{"traceparent": "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+		{name: "no headers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			traceID, spanID, sampled := logger.TraceContextFromHeaders(logger.ContextWithHTTPTraceHeaders(req))
+			require.Equal(t, tt.traceID, traceID)
+			require.Equal(t, tt.spanID, spanID)
+			require.Equal(t, tt.sampled, sampled)
+		})
+	}
+}
+
+func TestContextWithTraceMetadata(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceContext(logger.TraceContextFromHeaders))
+	md := map[string][]string{"traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}
+
+	l.Info(logger.ContextWithTraceMetadata(context.Background(), md), "traced")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", entries[0]["span_id"])
+	require.Equal(t, true, entries[0]["trace_sampled"])
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithTraceSampling keeps only a fraction of the Debug and Info entries, decided per trace:
+// the decision is derived from a hash of the trace ID, so either all or none of a request's
+// verbose entries are written. rate is the fraction of traces to keep, between 0 and 1.
+// Entries without a trace ID, and entries at Warn level and above, are always kept.
+func WithTraceSampling(rate float64) Option {
+	return func(l *Logger) {
+		l.traceSampling = &rate
+	}
+}
+
+// keepTrace reports whether a Debug or Info entry of the given trace is kept.
+func keepTrace(rate float64, traceID string) bool {
+	if traceID == "" || rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+	return float64(mix64(h.Sum64())) < rate*math.MaxUint64
+}
+
+// mix64 is the SplitMix64 finalizer. FNV spreads changes in the last bytes of similar IDs
+// poorly into the high bits, which the threshold comparison depends on.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// sampledOut reports whether an entry at lvl for ctx is dropped by trace sampling.
+func (l *Logger) sampledOut(lvl zapcore.Level, traceID string) bool {
+	return l.traceSampling != nil && lvl <= zapcore.InfoLevel && !keepTrace(*l.traceSampling, traceID)
+}
+
+// WithSampledDebug writes Debug entries only for traces that are sampled, per the sampled flag
+// returned by the GetTraceContextFn set through WithTraceContext, giving detailed logs for the
+// sampled requests while keeping the overall volume low. The Debug entries of sampled traces
+// are written even when the level disables DebugLevel; those of other traces, and entries
+// without a trace context, are not written, but can still be kept by WithPrecedingDebug.
+func WithSampledDebug() Option {
+	return func(l *Logger) {
+		l.sampledDebug = true
+	}
+}
+
+// traceSampled reports whether the trace carried by ctx is sampled.
+func (l *Logger) traceSampled(ctx context.Context) bool {
+	if l.getTraceCtx == nil {
+		return false
+	}
+	traceID, _, sampled := l.getTraceCtx(ctx)
+	return traceID != "" && sampled
+}
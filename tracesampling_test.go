@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTraceSamplingIsConsistentPerTrace(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	l, sink := newMemoryLogger(t,
+		logger.WithTraceID(traceFromContext),
+		logger.WithTraceSampling(0.5),
+		logger.WithLevel(zap.DebugLevel),
+		logger.WithStats(time.Minute),
+		logger.WithSampling(0, 0, 0),
+	)
+
+	const traces = 200
+	for i := 0; i < traces; i++ {
+		ctx := context.WithValue(context.Background(), traceKey{}, fmt.Sprintf("trace-%d", i))
+		l.Debug(ctx, "step one")
+		l.Info(ctx, "step two")
+		l.Error(ctx, "always kept")
+	}
+
+	perTrace := map[string][]string{}
+	for _, e := range decodeLines(t, sink) {
+		id := e["trace_id"].(string)
+		perTrace[id] = append(perTrace[id], e["msg"].(string))
+	}
+
+	kept := 0
+	for _, msgs := range perTrace {
+		switch len(msgs) {
+		case 3:
+			kept++
+		case 1:
+			require.Equal(t, []string{"always kept"}, msgs, "errors should never be sampled out")
+		default:
+			t.Fatalf("partial trace logs: %v", msgs)
+		}
+	}
+	require.Len(t, perTrace, traces)
+	require.InDelta(t, traces/2, kept, traces/5, "roughly half the traces should be kept")
+	require.Equal(t, uint64(2*(traces-kept)), l.Stats().Dropped)
+}
+
+func TestTraceSamplingKeepsEntriesWithoutTrace(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithTraceID(traceFromContext), logger.WithTraceSampling(0))
+
+	l.Info(context.Background(), "no trace")
+	l.Info(context.WithValue(context.Background(), traceKey{}, "trace-1"), "sampled out")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "no trace", entries[0]["msg"])
+}
+
+func TestWithSampledDebug(t *testing.T) {
+	if !logger.DebugEnabled {
+		t.Skip("Debug logging is compiled out")
+	}
+	traceCtx := func(ctx context.Context) (string, string, bool) {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id, "", id == "sampled"
+	}
+	l, sink := newMemoryLogger(t, logger.WithTraceContext(traceCtx), logger.WithSampledDebug())
+
+	l.Debug(context.WithValue(context.Background(), traceKey{}, "sampled"), "sampled detail")
+	l.Debug(context.WithValue(context.Background(), traceKey{}, "unsampled"), "unsampled detail")
+	l.Debug(context.Background(), "no trace")
+	l.Info(context.WithValue(context.Background(), traceKey{}, "unsampled"), "info")
+
+	var msgs []string
+	for _, e := range decodeLines(t, sink) {
+		msgs = append(msgs, e["msg"].(string))
+	}
+	require.Equal(t, []string{"sampled detail", "info"}, msgs)
+}
+
+func TestTraceSamplingIgnoresDisabledLevels(t *testing.T) {
+	l, _ := newMemoryLogger(t,
+		logger.WithTraceID(traceFromContext),
+		logger.WithTraceSampling(0),
+		logger.WithStats(time.Minute),
+		logger.WithSampling(0, 0, 0),
+	)
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	l.Debug(ctx, "disabled by the level")
+	require.Zero(t, l.Stats().Dropped, "entries the level disables should not be counted as dropped")
+
+	l.Info(ctx, "sampled out")
+	require.Equal(t, uint64(1), l.Stats().Dropped)
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldTransform rewrites a field before it is encoded. Fields of zapcore.SkipType, the
+// markers read by the cores such as Privacy, are never passed to it.
+type fieldTransform func(f zapcore.Field) zapcore.Field
+
+// messageTransform rewrites an entry's message before it is encoded.
+type messageTransform func(msg string) string
+
+// transformCore is a zapcore.Core that rewrites the message, context fields and entry
+// fields before they reach the wrapped core, so the rewrites apply to every output and hook.
+type transformCore struct {
+	zapcore.Core
+	transform fieldTransform
+	message   messageTransform
+}
+
+// newTransformCore wraps core so that all fields and messages pass through the given
+// transforms, in order.
+func newTransformCore(core zapcore.Core, transforms []fieldTransform, messages []messageTransform) zapcore.Core {
+	return &transformCore{
+		Core: core,
+		transform: func(f zapcore.Field) zapcore.Field {
+			if f.Type == zapcore.SkipType {
+				return f
+			}
+			for _, t := range transforms {
+				f = t(f)
+			}
+			return f
+		},
+		message: func(msg string) string {
+			for _, t := range messages {
+				msg = t(msg)
+			}
+			return msg
+		},
+	}
+}
+
+// With implements zapcore.Core.
+func (c *transformCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(c.apply(fields))
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *transformCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *transformCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.message(ent.Message)
+	return c.Core.Write(ent, c.apply(fields))
+}
+
+// apply returns a transformed copy of fields, leaving the caller's slice untouched.
+func (c *transformCore) apply(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = c.transform(f)
+	}
+	return out
+}
+
+// mapNested applies fn to the generic representation of a structured field value,
+// i.e. objects, arrays and reflected values, and returns a field holding the result.
+// Other fields are returned unchanged.
+func mapNested(f zapcore.Field, fn func(v interface{}) interface{}) zapcore.Field {
+	var value interface{}
+	switch f.Type {
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		value = enc.Fields[f.Key]
+	case zapcore.ReflectType:
+		data, err := json.Marshal(f.Interface)
+		if err != nil || json.Unmarshal(data, &value) != nil {
+			return f
+		}
+	default:
+		return f
+	}
+	return zap.Any(f.Key, fn(value))
+}
+
+// walkValue rebuilds the nested maps and slices of v, calling fn for every key-value pair
+// of a map. fn returns the value to keep for the key.
+func walkValue(v interface{}, fn func(key string, value interface{}) interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = fn(key, walkValue(value, fn))
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = walkValue(value, fn)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// truncatedKey is the key of the field marking entries holding truncated values.
+const truncatedKey = "_truncated"
+
+// WithMaxFieldBytes truncates the values of string, byte string and binary fields longer than
+// n bytes to n bytes, so that a single oversized payload, such as a dumped request body, can
+// not push an entry beyond the per-event size limit of the log pipeline. The messages of
+// logged errors and the strings nested in objects, arrays and groups are truncated too, while
+// numbers and booleans are kept. Entries holding a
+// truncated value carry a _truncated field set to true. The service and trace context fields
+// are kept whole, and strings are truncated at a character boundary. Values are truncated
+// after redaction, scrubbing and offloading; see WithOffload to keep large payloads
+// recoverable instead.
+func WithMaxFieldBytes(n int) Option {
+	return func(l *Logger) {
+		l.maxFieldBytes = n
+	}
+}
+
+// truncateCore is a zapcore.Core truncating oversized field values.
+type truncateCore struct {
+	zapcore.Core
+	max int
+	// marked is set when the fields added through With already hold the marker.
+	marked bool
+}
+
+// With implements zapcore.Core.
+func (c *truncateCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	fields, truncated := c.truncate(fields)
+	if truncated && !c.marked {
+		fields = append(fields, zap.Bool(truncatedKey, true))
+		clone.marked = true
+	}
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *truncateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *truncateCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields, truncated := c.truncate(fields)
+	if truncated && !c.marked {
+		fields = append(fields, zap.Bool(truncatedKey, true))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// truncate returns fields with oversized values truncated, copying the slice only when a value
+// is, and whether any was.
+func (c *truncateCore) truncate(fields []zapcore.Field) ([]zapcore.Field, bool) {
+	var out []zapcore.Field
+	for i, f := range fields {
+		switch {
+		case isOwnKey(f.Key):
+			continue
+		case f.Type == zapcore.StringType && len(f.String) > c.max:
+			f.String = truncateString(f.String, c.max)
+		case f.Type == zapcore.ByteStringType || f.Type == zapcore.BinaryType:
+			b, ok := f.Interface.([]byte)
+			if !ok || len(b) <= c.max {
+				continue
+			}
+			if f.Type == zapcore.ByteStringType {
+				f.Interface = []byte(truncateString(string(b), c.max))
+			} else {
+				f.Interface = b[:c.max]
+			}
+		case f.Type == zapcore.ErrorType:
+			err, ok := f.Interface.(error)
+			if !ok || err == nil || len(err.Error()) <= c.max {
+				continue
+			}
+			msg := truncateString(err.Error(), c.max)
+			// The original error is kept for the error fingerprint, like for scrubbing.
+			if scrubbed, ok := err.(*scrubbedError); ok {
+				err = scrubbed.err
+			}
+			f.Interface = &scrubbedError{err: err, msg: msg}
+		default:
+			var truncated bool
+			nested := mapNested(f, func(v interface{}) interface{} {
+				return truncateValue(v, c.max, &truncated)
+			})
+			if !truncated {
+				continue
+			}
+			f = nested
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)+1), fields...)
+		}
+		out[i] = f
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+// truncateValue truncates the strings and byte slices nested in v, the generic representation
+// of a structured value, setting truncated when it does.
+func truncateValue(v interface{}, n int, truncated *bool) interface{} {
+	switch v := v.(type) {
+	case string:
+		if len(v) > n {
+			*truncated = true
+			return truncateString(v, n)
+		}
+		return v
+	case []byte:
+		if len(v) > n {
+			*truncated = true
+			return v[:n]
+		}
+		return v
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = truncateValue(value, n, truncated)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = truncateValue(value, n, truncated)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// truncateString cuts s to at most n bytes, without splitting a UTF-8 character.
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithMaxFieldBytes(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithMaxFieldBytes(8))
+
+	l.Info(context.Background(), "dump",
+		"body", strings.Repeat("x", 100),
+		"name", "héhéhé", // é is two bytes, so the eighth and ninth bytes are one character.
+		zap.ByteString("raw", []byte("0123456789")),
+		zap.Binary("blob", []byte("0123456789")),
+		"short", "ok",
+		"count", 123456789012,
+	)
+	l.Info(context.Background(), "small", "body", "ok")
+
+	entries := decodeLines(t, sink)
+	require.Equal(t, "xxxxxxxx", entries[0]["body"])
+	require.Equal(t, "héhéh", entries[0]["name"])
+	require.Equal(t, "01234567", entries[0]["raw"])
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("01234567")), entries[0]["blob"])
+	require.Equal(t, "ok", entries[0]["short"])
+	require.EqualValues(t, 123456789012, entries[0]["count"])
+	require.Equal(t, true, entries[0]["_truncated"])
+	require.NotContains(t, entries[1], "_truncated")
+}
+
+func TestWithMaxFieldBytesWith(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithMaxFieldBytes(4))
+
+	l.With("query", "SELECT 1").Info(context.Background(), "entry", "table", "orders")
+
+	out := sink.logs.String()
+	require.Equal(t, 1, strings.Count(out, `"_truncated":true`), "the marker should be written once")
+	entry := decodeLines(t, sink)[0]
+	require.Equal(t, "SELE", entry["query"])
+	require.Equal(t, "orde", entry["table"])
+}
+
+func TestWithMaxFieldBytesNested(t *testing.T) {
+	l, sink := newMemoryLogger(t, logger.WithMaxFieldBytes(8))
+	ctx := context.Background()
+	big := strings.Repeat("x", 100)
+
+	l.Info(ctx, "group", logger.Group("http", "body", big, "status", 200))
+	l.WithGroup("http").Info(ctx, "with group", "body", big)
+	l.Info(ctx, "error", "error", errors.New(big))
+	l.Info(ctx, "array", "items", []string{big, "ok"})
+	l.Info(ctx, "reflected", "payload", map[string]any{"nested": []any{big}})
+
+	require.NotContains(t, sink.logs.String(), strings.Repeat("x", 9))
+	entries := decodeLines(t, sink)
+	require.Equal(t, map[string]any{"body": "xxxxxxxx", "status": float64(200)}, entries[0]["http"])
+	require.Equal(t, map[string]any{"body": "xxxxxxxx"}, entries[1]["http"])
+	require.Equal(t, "xxxxxxxx", entries[2]["error"])
+	require.Equal(t, []any{"xxxxxxxx", "ok"}, entries[3]["items"])
+	require.Equal(t, map[string]any{"nested": []any{"xxxxxxxx"}}, entries[4]["payload"])
+	for _, entry := range entries {
+		require.Equal(t, true, entry["_truncated"], entry["msg"])
+	}
+}
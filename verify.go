@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WriteVerifier is implemented by sinks that can prove that a write landed at its
+// destination, for example because the destination acknowledged it. VerifyWrite is called
+// after every successful write of p, see WithVerifiedWrites.
+type WriteVerifier interface {
+	VerifyWrite(p []byte) error
+}
+
+// VerificationError reports a write to an output path that could not be verified, see
+// WithVerifiedWrites.
+type VerificationError struct {
+	// Path is the output path the entry was written to.
+	Path string
+	// Err describes why the write could not be verified.
+	Err error
+}
+
+// Error implements error.
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("verify write to %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the cause of the failed verification.
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// errReadBackMismatch is the cause of a VerificationError when a file does not hold the
+// written entry.
+var errReadBackMismatch = errors.New("read back mismatch")
+
+// WithVerifiedWrites verifies every write to the given output paths, for example for audit
+// logs that must prove that their records landed. Files are read back after every write and
+// compared with the written entry; they must not be written by other processes meanwhile.
+// Sinks created through a SinkFactory must implement WriteVerifier. A write that cannot be
+// verified fails with a *VerificationError, which is passed to the hooks registered through
+// WithWriteErrorHook and reported through zap's error output. With WithBufferedWrites, the
+// failures of background flushes are not reported. New fails when a path is not among the
+// output paths or cannot be verified, such as stdout.
+func WithVerifiedWrites(paths ...string) Option {
+	return func(l *Logger) {
+		l.verifiedPaths = append(l.verifiedPaths, paths...)
+	}
+}
+
+// verifiedSink is a zapcore.WriteSyncer verifying every write to an output path.
+type verifiedSink struct {
+	zapcore.WriteSyncer
+	path string
+	// verify verifies the write of p. offset is the offset returned by offset before the
+	// write, if set.
+	verify func(p []byte, offset int64) error
+	offset func() (int64, error)
+
+	// mu serializes the writes, so that each one is verified before the next.
+	mu sync.Mutex
+}
+
+// Write implements io.Writer.
+func (s *verifiedSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var offset int64
+	if s.offset != nil {
+		var err error
+		if offset, err = s.offset(); err != nil {
+			return 0, &VerificationError{Path: s.path, Err: err}
+		}
+	}
+	n, err := s.WriteSyncer.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := s.verify(p, offset); err != nil {
+		return n, &VerificationError{Path: s.path, Err: err}
+	}
+	return n, nil
+}
+
+// newVerifierSink wraps sink, created by a SinkFactory for path, so that its writes are
+// verified through its WriteVerifier implementation.
+func newVerifierSink(ws zapcore.WriteSyncer, path string, sink zap.Sink) (*verifiedSink, error) {
+	v, ok := sink.(WriteVerifier)
+	if !ok {
+		return nil, fmt.Errorf("verify writes to %q: the sink does not implement WriteVerifier", path)
+	}
+	return &verifiedSink{
+		WriteSyncer: ws,
+		path:        path,
+		verify:      func(p []byte, _ int64) error { return v.VerifyWrite(p) },
+	}, nil
+}
+
+// newReadBackSink wraps ws, opened by zap.Open for the file at path, so that every write is
+// read back from the file. It returns the sink and a function closing the file it reads.
+func newReadBackSink(ws zapcore.WriteSyncer, path string) (*verifiedSink, func(), error) {
+	name, ok := outputFile(path)
+	if !ok {
+		return nil, nil, fmt.Errorf("verify writes to %q: not a file", path)
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("verify writes to %q: %w", path, err)
+	}
+
+	s := &verifiedSink{
+		WriteSyncer: ws,
+		path:        path,
+		offset: func() (int64, error) {
+			info, err := file.Stat()
+			if err != nil {
+				return 0, err
+			}
+			return info.Size(), nil
+		},
+		verify: func(p []byte, offset int64) error {
+			buf := make([]byte, len(p))
+			if _, err := file.ReadAt(buf, offset); err != nil {
+				return err
+			}
+			if !bytes.Equal(buf, p) {
+				return errReadBackMismatch
+			}
+			return nil
+		},
+	}
+	return s, func() { _ = file.Close() }, nil
+}
+
+// outputFile returns the name of the file an output path opened by zap.Open writes to. It
+// reports false for stdout and stderr.
+func outputFile(path string) (string, bool) {
+	switch path {
+	case "stdout", "stderr":
+		return "", false
+	}
+	if filepath.IsAbs(path) {
+		return path, true
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", false
+	}
+	switch u.Scheme {
+	case "":
+		return path, true
+	case "file":
+		return u.Path, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logger "github.com/janduursma/zap-logger-wrapper/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// verifyingSink is a memorySink implementing logger.WriteVerifier.
+type verifyingSink struct {
+	memorySink
+	err error
+}
+
+// VerifyWrite returns the configured error.
+func (s *verifyingSink) VerifyWrite(_ []byte) error {
+	return s.err
+}
+
+func TestWithVerifiedWritesReadsBackFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	var writeErr error
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{path}),
+		logger.WithVerifiedWrites(path),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErr = err }),
+	)
+	require.NoError(t, err)
+
+	l.Info(context.Background(), "first")
+	l.Info(context.Background(), "second")
+	require.NoError(t, writeErr)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "second")
+}
+
+func TestWithVerifiedWritesFactorySink(t *testing.T) {
+	sink := &verifyingSink{err: errors.New("not acknowledged")}
+	scheme := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return sink, nil })
+	path := scheme + "://"
+
+	var writeErr error
+	l, err := logger.New("test-service",
+		logger.WithOutputPaths([]string{path}),
+		logger.WithVerifiedWrites(path),
+		logger.WithWriteErrorHook(func(_ zapcore.Entry, _ []zapcore.Field, err error) { writeErr = err }),
+	)
+	require.NoError(t, err)
+	l.Info(context.Background(), "audit record")
+
+	var verifyErr *logger.VerificationError
+	require.ErrorAs(t, writeErr, &verifyErr)
+	require.Equal(t, path, verifyErr.Path)
+	require.ErrorContains(t, writeErr, "not acknowledged")
+	require.Contains(t, sink.logs.String(), "audit record", "the entry is still written")
+}
+
+func TestWithVerifiedWritesErrors(t *testing.T) {
+	plain := registerSinkFactory(t, func(_ logger.SinkConfig) (zap.Sink, error) { return &memorySink{}, nil })
+	for _, tc := range []struct {
+		name     string
+		paths    []string
+		verified string
+		want     string
+	}{
+		{name: "stdout", paths: []string{"stdout"}, verified: "stdout", want: "not a file"},
+		{name: "no verifier", paths: []string{plain + "://"}, verified: plain + "://", want: "WriteVerifier"},
+		{name: "unknown path", paths: []string{"stdout"}, verified: "/var/log/other.log", want: "not an output path"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := logger.New("test-service",
+				logger.WithOutputPaths(tc.paths),
+				logger.WithVerifiedWrites(tc.verified),
+			)
+			require.ErrorContains(t, err, tc.want)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// keyVal is a single inherited field, either a key-value pair or a strongly typed zap field.
+type keyVal struct {
+	key   string
+	items []interface{}
+}
+
+// splitKeyVals groups loosely typed key-value pairs into individual fields.
+// Malformed trailing or non-string keys are kept as is, so zap can report them.
+func splitKeyVals(keyVals []interface{}) []keyVal {
+	var fields []keyVal
+	for i := 0; i < len(keyVals); {
+		if f, ok := keyVals[i].(zapcore.Field); ok {
+			fields = append(fields, keyVal{key: f.Key, items: keyVals[i : i+1]})
+			i++
+			continue
+		}
+		if i+1 == len(keyVals) {
+			fields = append(fields, keyVal{items: keyVals[i:]})
+			break
+		}
+		key, _ := keyVals[i].(string)
+		fields = append(fields, keyVal{key: key, items: keyVals[i : i+2]})
+		i += 2
+	}
+	return fields
+}
+
+// Without returns a child Logger that no longer includes the inherited fields with the given keys.
+// This is useful when handing work to code that should not see some of the parent's context,
+// for example dropping a tenant field before passing the logger to a shared worker pool.
+// The fields added to the groups of WithGroup are dropped as well.
+func (l *Logger) Without(keys ...string) *Logger {
+	drop := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		drop[k] = struct{}{}
+	}
+
+	kept := dropKeyVals(l.fields, drop)
+	child := *l
+	child.fields = kept
+	child.zapLogger = l.baseLogger.With(kept...)
+	child.group = l.group.without(drop)
+	return &child
+}
+
+// dropKeyVals returns the fields of keyVals whose keys are not in drop.
+func dropKeyVals(keyVals []interface{}, drop map[string]struct{}) []interface{} {
+	var kept []interface{}
+	for _, f := range splitKeyVals(keyVals) {
+		if _, ok := drop[f.key]; ok && f.key != "" {
+			continue
+		}
+		kept = append(kept, f.items...)
+	}
+	return kept
+}
+
+// WithReplace returns a child Logger in which the inherited field key is set to value.
+// Unlike With, any value the parent already holds for key is replaced instead of duplicated.
+func (l *Logger) WithReplace(key string, value interface{}) *Logger {
+	return l.Without(key).With(key, value)
+}
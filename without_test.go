@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithout(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	parent := l.With("tenant", "acme", zap.String("region", "eu"), "component", "billing")
+	parent.Without("tenant", "region").Info(ctx, "shared pool work")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.NotContains(t, entries[0], "tenant", "removed field should not be inherited")
+	require.NotContains(t, entries[0], "region", "removed typed field should not be inherited")
+	require.Equal(t, "billing", entries[0]["component"], "other fields should still be inherited")
+	require.Equal(t, "test-service", entries[0]["service"], "initial fields should be kept")
+}
+
+func TestWithoutDoesNotAffectParent(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	parent := l.With("tenant", "acme")
+	_ = parent.Without("tenant")
+	parent.Info(ctx, "parent")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 1)
+	require.Equal(t, "acme", entries[0]["tenant"])
+}
+
+func TestWithReplace(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	l.With("tenant", "acme", "user", "jane").WithReplace("tenant", "globex").Info(ctx, "replaced")
+
+	require.Contains(t, sink.logs.String(), `"tenant":"globex"`)
+	require.NotContains(t, sink.logs.String(), `"tenant":"acme"`, "replaced value should not be duplicated")
+	require.Contains(t, sink.logs.String(), `"user":"jane"`)
+}
+
+func TestWithFieldsDoNotLeakBetweenSiblings(t *testing.T) {
+	l, sink := newMemoryLogger(t)
+	ctx := context.Background()
+
+	parent := l.With("a", 1)
+	first := parent.With("b", 2)
+	second := parent.With("c", 3)
+	first.Without("a").Info(ctx, "first")
+	second.Info(ctx, "second")
+
+	entries := decodeLines(t, sink)
+	require.Len(t, entries, 2)
+	require.Equal(t, map[string]any{"b": float64(2)}, pick(entries[0], "a", "b", "c"))
+	require.Equal(t, map[string]any{"a": float64(1), "c": float64(3)}, pick(entries[1], "a", "b", "c"))
+}
+
+// pick returns the subset of entry with the given keys.
+func pick(entry map[string]any, keys ...string) map[string]any {
+	out := map[string]any{}
+	for _, k := range keys {
+		if v, ok := entry[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}